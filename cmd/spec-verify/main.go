@@ -3,14 +3,21 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/k-totani/spec-verify/internal/ai"
+	"github.com/k-totani/spec-verify/internal/cache"
 	"github.com/k-totani/spec-verify/internal/config"
+	"github.com/k-totani/spec-verify/internal/egress"
+	"github.com/k-totani/spec-verify/internal/i18n"
 	"github.com/k-totani/spec-verify/internal/parser"
+	"github.com/k-totani/spec-verify/internal/redact"
 	"github.com/k-totani/spec-verify/internal/verifier"
 )
 
@@ -28,9 +35,24 @@ const (
 	separatorWidthNarrow = 40
 	// リスト表示の最大件数
 	maxDisplayItems = 3
+	// cache prune のデフォルトの保持期間
+	defaultCachePruneAge = 30 * 24 * time.Hour
 )
 
+// 終了コード
+const (
+	// exitCodeFailed は検証が合格基準を満たさなかった場合やエラーの場合
+	exitCodeFailed = 1
+	// exitCodeBudgetExceeded は予算の上限によりスキップされたSPECがある場合
+	exitCodeBudgetExceeded = 3
+)
+
+// msg はCLIの出力に使用するメッセージカタログ（main の開始時に設定の言語で切り替える）
+var msg = i18n.New(i18n.DefaultLanguage)
+
 func main() {
+	setLanguage(os.Args[1:])
+
 	if len(os.Args) < 2 {
 		printUsage()
 		os.Exit(0)
@@ -51,6 +73,10 @@ func main() {
 		runEndpoints(os.Args[2:])
 	case "coverage":
 		runCoverage(os.Args[2:])
+	case "cache":
+		runCache(os.Args[2:])
+	case "prompt":
+		runPrompt(os.Args[2:])
 	case "version", "-v", "--version":
 		fmt.Printf("spec-verify version %s\n", version)
 	case "help", "-h", "--help":
@@ -67,12 +93,22 @@ type commonOptions struct {
 	configFile string
 	apiKey     string // CLI引数で渡すAPIキー
 	provider   string // AIプロバイダー指定
+	baseURL    string // AIプロバイダーのベースURL指定
+	model      string // AIモデル指定
+	cassette   string // 応答を記録/再生するカセットファイル
+	language   string // 出力とAIの回答の言語
+	// cache options
+	noCache      bool // 検証結果キャッシュを使用しない
+	refreshCache bool // キャッシュを読まずに検証し、結果で上書きする
 	// check-specific options
-	threshold int
-	failUnder int
-	specType  string   // 後方互換用
-	specTypes []string // 複数タイプ指定
-	groupName string   // グループ指定
+	dryRun        bool // APIを呼び出さずにトークン数とコストを見積もる
+	sections      bool // SPECのセクションごとに検証する
+	threshold     int
+	failUnder     int
+	failUnderMode string   // 個別閾値の判定に使用する一致度（median, lower_bound）
+	specType      string   // 後方互換用
+	specTypes     []string // 複数タイプ指定
+	groupName     string   // グループ指定
 }
 
 // parseCommonOptions parses common options from arguments
@@ -96,12 +132,35 @@ func parseCommonOptions(args []string) commonOptions {
 		case arg == "--provider" && i+1 < len(args):
 			opts.provider = args[i+1]
 			i++
+		case arg == "--base-url" && i+1 < len(args):
+			opts.baseURL = args[i+1]
+			i++
+		case arg == "--model" && i+1 < len(args):
+			opts.model = args[i+1]
+			i++
+		case arg == "--cassette" && i+1 < len(args):
+			opts.cassette = args[i+1]
+			i++
+		case arg == "--lang" && i+1 < len(args):
+			opts.language = args[i+1]
+			i++
 		case arg == "--threshold" && i+1 < len(args):
 			fmt.Sscanf(args[i+1], "%d", &opts.threshold)
 			i++
 		case arg == "--fail-under" && i+1 < len(args):
 			fmt.Sscanf(args[i+1], "%d", &opts.failUnder)
 			i++
+		case arg == "--fail-under-mode" && i+1 < len(args):
+			opts.failUnderMode = args[i+1]
+			i++
+		case arg == "--no-cache":
+			opts.noCache = true
+		case arg == "--refresh-cache":
+			opts.refreshCache = true
+		case arg == "--dry-run":
+			opts.dryRun = true
+		case arg == "--sections":
+			opts.sections = true
 		case (arg == "--group" || arg == "-g") && i+1 < len(args):
 			opts.groupName = args[i+1]
 			i++
@@ -127,9 +186,24 @@ func (opts commonOptions) buildLoadOptions() []config.LoadOption {
 	if opts.apiKey != "" {
 		loadOpts = append(loadOpts, config.WithAPIKey(opts.apiKey))
 	}
+	if opts.baseURL != "" {
+		loadOpts = append(loadOpts, config.WithBaseURL(opts.baseURL))
+	}
+	if opts.model != "" {
+		loadOpts = append(loadOpts, config.WithModel(opts.model))
+	}
+	if opts.cassette != "" {
+		loadOpts = append(loadOpts, config.WithCassette(opts.cassette))
+	}
+	if opts.language != "" {
+		loadOpts = append(loadOpts, config.WithLanguage(opts.language))
+	}
 	return loadOpts
 }
 
+// errNoValidTypes は指定されたSPECタイプが全て未定義の場合のエラー
+var errNoValidTypes = errors.New("no valid spec types")
+
 // validateSpecTypes はSPECタイプのバリデーションを行い、有効なタイプのみを返す
 // 存在しないタイプがある場合は警告を出力する
 func validateSpecTypes(cfg *config.Config, types []string, contextLabel string) ([]string, error) {
@@ -146,13 +220,11 @@ func validateSpecTypes(cfg *config.Config, types []string, contextLabel string)
 
 	// 警告はstderrに出力（JSON出力時にも対応）
 	if contextLabel != "" {
-		fmt.Fprintf(os.Stderr, "⚠️  警告: %sに存在しないタイプが含まれています: %s\n",
-			contextLabel, strings.Join(undefinedTypes, ", "))
+		fmt.Fprintln(os.Stderr, msg.Sprintf("warn.undefined_types_in", contextLabel, strings.Join(undefinedTypes, ", ")))
 	} else {
-		fmt.Fprintf(os.Stderr, "⚠️  警告: 存在しないタイプが指定されています: %s\n",
-			strings.Join(undefinedTypes, ", "))
+		fmt.Fprintln(os.Stderr, msg.Sprintf("warn.undefined_types", strings.Join(undefinedTypes, ", ")))
 	}
-	fmt.Fprintln(os.Stderr, "定義済みタイプを確認するには: spec-verify types")
+	fmt.Fprintln(os.Stderr, msg.Sprintf("hint.list_types"))
 
 	// 存在しないタイプを除外
 	var validTypes []string
@@ -163,7 +235,7 @@ func validateSpecTypes(cfg *config.Config, types []string, contextLabel string)
 	}
 
 	if len(validTypes) == 0 {
-		return nil, fmt.Errorf("有効なタイプが1つもありません")
+		return nil, errNoValidTypes
 	}
 
 	return validTypes, nil
@@ -178,92 +250,46 @@ func loadConfig(opts commonOptions) (*config.Config, error) {
 	return config.Load(configFile, opts.buildLoadOptions()...)
 }
 
+// setLanguage は --lang と設定ファイルの language から出力の言語を決める
+// 設定ファイルを読み込めない場合はデフォルトの言語のままにする（エラーは各コマンドで表示する）
+func setLanguage(args []string) {
+	cfg, err := loadConfig(parseCommonOptions(args))
+	if err != nil || cfg.Language == "" {
+		return
+	}
+	if !i18n.Supported(cfg.Language) {
+		// JSON出力を壊さないよう警告は標準エラーに出力する
+		fmt.Fprintln(os.Stderr, msg.Sprintf("warn.unsupported_language", cfg.Language, strings.Join(i18n.Languages(), ", ")))
+		return
+	}
+	msg = i18n.New(cfg.Language)
+}
+
 func printUsage() {
-	fmt.Println(`spec-verify - SPEC駆動開発のための検証ツール
-
-Usage:
-  spec-verify <command> [options]
-  go run github.com/k-totani/spec-verify/cmd/spec-verify@latest <command> [options]
-
-Commands:
-  init              設定ファイルを初期化
-  check [type...]   SPECとコードの一致度を検証
-                    type: ui, api, domain, model 等（設定で定義）
-                    複数指定可能、省略で全て
-  types             定義済みSPECタイプ一覧を表示
-  groups            定義済みグループ一覧を表示
-  endpoints         APIエンドポイント一覧を表示
-  coverage          ルートカバレッジレポート（ページ/APIに対するSPEC網羅率）
-  version           バージョンを表示
-  help              このヘルプを表示
-
-Options:
-  --format json      JSON形式で出力（CI向け）
-  --threshold N      合格ラインを指定（デフォルト: 50）
-  --fail-under N     個別閾値を指定（N%未満のSPECがあれば失敗）
-  --group, -g NAME   グループ単位で検証
-  --config FILE      設定ファイルを指定
-  --api-key KEY      APIキーを直接指定（環境変数より優先）
-  --provider NAME    AIプロバイダーを指定（claude, openai, gemini）
-
-Environment Variables (優先順位: --api-key > 環境変数 > .env > 設定ファイル):
-  ANTHROPIC_API_KEY    Claude APIキー
-  OPENAI_API_KEY       OpenAI APIキー
-  GOOGLE_API_KEY       Gemini APIキー
-  SPEC_VERIFY_API_KEY  汎用APIキー（プロバイダーに関わらず使用可能）
-
-.env File Support:
-  プロジェクトルートに .env ファイルを配置すると自動的に読み込まれます。
-  既存の環境変数は上書きされません。
-
-Examples:
-  # 基本的な使い方
-  spec-verify init
-  spec-verify check
-  spec-verify check ui
-
-  # go run で直接実行
-  go run github.com/k-totani/spec-verify/cmd/spec-verify@latest check
-
-  # APIキーを直接指定
-  spec-verify check --api-key sk-xxx
-
-  # 複数タイプ/グループ指定
-  spec-verify check domain model          # 複数タイプ指定
-  spec-verify check --group backend       # グループ単位で検証
-
-  # CI向け
-  spec-verify check --format json
-  spec-verify check api --threshold 70
-  spec-verify coverage --format json
-  spec-verify coverage --fail-under 80   # カバレッジ80%未満で失敗`)
+	fmt.Println(msg.Sprintf("usage", strings.Join(i18n.Languages(), ", ")))
 }
 
 func runInit() {
 	configFile := config.FindConfigFile()
 
 	if _, err := os.Stat(configFile); err == nil {
-		fmt.Printf("設定ファイル %s は既に存在します。上書きしますか？ [y/N] ", configFile)
+		fmt.Print(msg.Sprintf("init.exists", configFile))
 		var answer string
 		fmt.Scanln(&answer)
 		if strings.ToLower(answer) != "y" {
-			fmt.Println("キャンセルしました。")
+			fmt.Println(msg.Sprintf("init.cancelled"))
 			return
 		}
 	}
 
 	cfg := config.DefaultConfig()
 	if err := cfg.Save(configFile); err != nil {
-		fmt.Printf("エラー: 設定ファイルの作成に失敗しました: %v\n", err)
+		fmt.Println(msg.Sprintf("error.create_config", err))
 		os.Exit(1)
 	}
 
-	fmt.Printf("✅ 設定ファイル %s を作成しました。\n", configFile)
-	fmt.Println("\n次のステップ:")
-	fmt.Println("1. 設定ファイルを編集してプロジェクトに合わせてください")
-	fmt.Println("2. ANTHROPIC_API_KEY 環境変数を設定してください")
-	fmt.Println("3. specs/ ディレクトリにSPECファイルを配置してください")
-	fmt.Println("4. spec-verify check を実行してください")
+	fmt.Println(msg.Sprintf("init.created", configFile))
+	fmt.Println(msg.Sprintf("init.next_steps"))
 }
 
 func runCheck(args []string) {
@@ -273,7 +299,7 @@ func runCheck(args []string) {
 	// 設定を読み込む
 	cfg, err := loadConfig(commonOpts)
 	if err != nil {
-		fmt.Printf("エラー: 設定ファイルの読み込みに失敗しました: %v\n", err)
+		fmt.Println(msg.Sprintf("error.load_config", err))
 		os.Exit(1)
 	}
 
@@ -284,20 +310,23 @@ func runCheck(args []string) {
 	if commonOpts.failUnder > 0 {
 		cfg.Options.FailUnder = commonOpts.failUnder
 	}
-
-	// APIキーの確認
-	if cfg.AIAPIKey == "" {
-		fmt.Println("エラー: APIキーが設定されていません。")
-		fmt.Println("ANTHROPIC_API_KEY 環境変数を設定するか、設定ファイルに api_key を追加してください。")
-		os.Exit(1)
+	if commonOpts.failUnderMode != "" {
+		cfg.Options.FailUnderMode = commonOpts.failUnderMode
 	}
-
-	// Verifierを作成
-	v, err := verifier.New(cfg)
-	if err != nil {
-		fmt.Printf("エラー: Verifierの作成に失敗しました: %v\n", err)
+	switch cfg.Options.FailUnderMode {
+	case "", config.FailUnderModeMedian, config.FailUnderModeLowerBound:
+	default:
+		fmt.Println(msg.Sprintf("error.fail_under_mode",
+			config.FailUnderModeMedian, config.FailUnderModeLowerBound, cfg.Options.FailUnderMode))
 		os.Exit(1)
 	}
+	if commonOpts.sections {
+		cfg.Sections.Enabled = true
+	}
+	if commonOpts.noCache {
+		disabled := false
+		cfg.Cache.Enabled = &disabled
+	}
 
 	// 検証対象タイプを決定
 	var specTypes []string
@@ -305,36 +334,66 @@ func runCheck(args []string) {
 	// グループ指定の場合
 	if commonOpts.groupName != "" {
 		if !cfg.HasGroup(commonOpts.groupName) {
-			fmt.Printf("エラー: グループ '%s' は定義されていません。\n", commonOpts.groupName)
-			fmt.Println("定義済みグループを確認するには: spec-verify groups")
+			fmt.Println(msg.Sprintf("error.undefined_group", commonOpts.groupName))
+			fmt.Println(msg.Sprintf("hint.list_groups"))
 			os.Exit(1)
 		}
 		specTypes = cfg.GetTypesByGroup(commonOpts.groupName)
 
 		// グループ内のタイプをバリデーション
-		specTypes, err = validateSpecTypes(cfg, specTypes, fmt.Sprintf("グループ '%s'", commonOpts.groupName))
+		specTypes, err = validateSpecTypes(cfg, specTypes, msg.Sprintf("label.group", commonOpts.groupName))
 		if err != nil {
-			fmt.Printf("エラー: グループ '%s' に%v\n", commonOpts.groupName, err)
+			fmt.Println(msg.Sprintf("error.group_no_valid_types", commonOpts.groupName))
 			os.Exit(1)
 		}
 	} else if len(commonOpts.specTypes) > 0 {
 		// 複数タイプ指定の場合
 		specTypes, err = validateSpecTypes(cfg, commonOpts.specTypes, "")
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			fmt.Fprintln(os.Stderr, msg.Sprintf("error.no_valid_types"))
 			os.Exit(1)
 		}
 	}
 
+	// ドライラン: APIを呼び出さずに見積もりのみ行う（APIキーは不要）
+	if commonOpts.dryRun {
+		estimate, err := verifier.EstimateMultipleTypes(cfg, specTypes)
+		if err != nil {
+			fmt.Println(msg.Sprintf("error.estimate", err))
+			os.Exit(1)
+		}
+		if commonOpts.jsonOutput {
+			outputEstimateJSON(estimate)
+		} else {
+			outputEstimateConsole(estimate)
+		}
+		return
+	}
+
+	// APIキーの確認（ローカルプロバイダーは不要）
+	if !ensureAPIKey(cfg) {
+		fmt.Println(msg.Sprintf("error.no_api_key"))
+		fmt.Println(msg.Sprintf("hint.set_api_key"))
+		fmt.Println(msg.Sprintf("hint.heuristic_check"))
+		os.Exit(1)
+	}
+
+	// Verifierを作成
+	v, err := verifier.New(cfg, verifier.WithRefreshCache(commonOpts.refreshCache))
+	if err != nil {
+		fmt.Println(msg.Sprintf("error.create_verifier", err))
+		os.Exit(1)
+	}
+
 	// 検証を実行
 	ctx := context.Background()
 
 	if !commonOpts.jsonOutput {
-		fmt.Println("\n🔍 SPEC検証を開始します...")
+		fmt.Println("\n" + msg.Sprintf("check.start"))
 		if commonOpts.groupName != "" {
-			fmt.Printf("   グループ: %s (タイプ: %s)\n", commonOpts.groupName, strings.Join(specTypes, ", "))
+			fmt.Println(msg.Sprintf("check.group", commonOpts.groupName, strings.Join(specTypes, ", ")))
 		} else if len(specTypes) > 0 {
-			fmt.Printf("   タイプ: %s\n", strings.Join(specTypes, ", "))
+			fmt.Println(msg.Sprintf("check.types", strings.Join(specTypes, ", ")))
 		}
 		fmt.Println(strings.Repeat("━", separatorWidthNormal))
 	}
@@ -348,23 +407,28 @@ func runCheck(args []string) {
 		summary, err = v.VerifyAll(ctx, commonOpts.specType)
 	}
 	if err != nil {
-		fmt.Printf("エラー: 検証に失敗しました: %v\n", err)
+		fmt.Println(msg.Sprintf("error.verify", err))
 		os.Exit(1)
 	}
 
 	// 個別閾値チェック
 	if cfg.Options.FailUnder > 0 {
 		summary.FailUnder = cfg.Options.FailUnder
-		summary.FailingSpecs = buildFailingSpecs(summary.Results, cfg.Options.FailUnder)
+		summary.FailUnderMode = cfg.Options.FailUnderMode
+		summary.FailingSpecs = buildFailingSpecs(summary.Results, cfg.Options.FailUnder, cfg.UsesLowerBound())
 	}
 
 	if commonOpts.jsonOutput {
 		outputJSON(summary)
 	} else {
-		outputConsole(summary, cfg.Options.FailUnder)
+		outputConsole(summary, cfg.Options.FailUnder, cfg.UsesLowerBound())
 	}
 
 	// 終了コード
+	// 予算超過でスキップされたSPECがある場合は結果が不完全なため、合否より優先して区別できるコードを返す
+	if summary.SkippedSpecs > 0 {
+		os.Exit(exitCodeBudgetExceeded)
+	}
 	failed := false
 	if !summary.IsPassing(cfg.Options.PassThreshold) {
 		failed = true
@@ -373,23 +437,100 @@ func runCheck(args []string) {
 		failed = true
 	}
 	if failed {
-		os.Exit(1)
+		os.Exit(exitCodeFailed)
 	}
 }
 
+func outputEstimateJSON(estimate *verifier.Estimate) {
+	data, _ := json.MarshalIndent(estimate, "", "  ")
+	fmt.Println(string(data))
+}
+
+func outputEstimateConsole(estimate *verifier.Estimate) {
+	fmt.Println("\n" + msg.Sprintf("estimate.header"))
+	fmt.Println(msg.Sprintf("estimate.provider", estimate.Provider, estimate.Model))
+	if estimate.CallsPerSpec > 1 {
+		fmt.Println(msg.Sprintf("estimate.calls_per_spec", estimate.CallsPerSpec))
+	}
+	if estimate.PriceKnown {
+		fmt.Println(msg.Sprintf("estimate.price", estimate.InputPerMTok, estimate.OutputPerMTok))
+	} else {
+		fmt.Println(msg.Sprintf("estimate.price_unknown"))
+	}
+	fmt.Println(strings.Repeat("━", separatorWidthNormal))
+
+	for _, spec := range estimate.Specs {
+		fmt.Printf("\n📄 %s\n", spec.SpecFile)
+		if spec.Title != "" {
+			fmt.Println(msg.Sprintf("result.title", spec.Title))
+		}
+		switch {
+		case spec.Error != "":
+			fmt.Println(msg.Sprintf("result.error", spec.Error))
+			continue
+		case spec.NoCode:
+			fmt.Println(msg.Sprintf("estimate.no_code"))
+			continue
+		}
+		fmt.Println(msg.Sprintf("estimate.code", len(spec.CodeFiles), formatBytes(spec.CodeBytes)))
+		fmt.Println(msg.Sprintf("estimate.tokens", spec.InputTokens, spec.OutputTokens))
+		if spec.Cached {
+			fmt.Println(msg.Sprintf("estimate.cached"))
+		} else if estimate.PriceKnown {
+			fmt.Println(msg.Sprintf("estimate.cost", spec.Cost))
+		}
+	}
+
+	fmt.Println("\n" + strings.Repeat("━", separatorWidthNormal))
+	fmt.Println("\n" + msg.Sprintf("estimate.total"))
+	fmt.Println(msg.Sprintf("summary.total_specs", estimate.TotalSpecs))
+	fmt.Println(msg.Sprintf("estimate.requests", estimate.Requests))
+	fmt.Println(msg.Sprintf("estimate.files", estimate.TotalFiles))
+	fmt.Println(msg.Sprintf("estimate.bytes", formatBytes(estimate.TotalBytes)))
+	fmt.Println(msg.Sprintf("estimate.tokens", estimate.InputTokens, estimate.OutputTokens))
+	if estimate.PriceKnown {
+		fmt.Println(msg.Sprintf("estimate.cost", estimate.Cost))
+	}
+	fmt.Println()
+}
+
+// formatBytes はバイト数を読みやすい単位で返す
+func formatBytes(n int) string {
+	switch {
+	case n >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(n)/1024/1024)
+	case n >= 1024:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+// failUnderScore は個別閾値の判定に使用する一致度を返す
+// lowerBound の場合はサンプル/メンバーの最小値を使用する
+func failUnderScore(v *ai.VerificationResult, lowerBound bool) int {
+	if lowerBound {
+		return v.LowerBound()
+	}
+	return v.MatchPercentage
+}
+
 // buildFailingSpecs は個別閾値を下回ったSPECを抽出する
-func buildFailingSpecs(results []verifier.Result, failUnder int) []verifier.FailingSpec {
+func buildFailingSpecs(results []verifier.Result, failUnder int, lowerBound bool) []verifier.FailingSpec {
 	var failing []verifier.FailingSpec
 	for _, result := range results {
 		// エラーがあるものは対象外（エラーは別で表示）
 		if result.Error != nil {
 			continue
 		}
-		if result.Verification != nil && result.Verification.MatchPercentage < failUnder {
+		if result.Verification == nil {
+			continue
+		}
+		if score := failUnderScore(result.Verification, lowerBound); score < failUnder {
 			failing = append(failing, verifier.FailingSpec{
 				SpecFile:        result.SpecFile,
 				Title:           result.Title,
-				MatchPercentage: result.Verification.MatchPercentage,
+				MatchPercentage: score,
 			})
 		}
 	}
@@ -401,38 +542,106 @@ func outputJSON(summary *verifier.Summary) {
 	fmt.Println(string(data))
 }
 
-func outputConsole(summary *verifier.Summary, failUnder int) {
+func outputConsole(summary *verifier.Summary, failUnder int, lowerBound bool) {
 	for _, result := range summary.Results {
 		fmt.Printf("\n📄 %s\n", result.SpecFile)
-		fmt.Printf("   タイトル: %s\n", result.Title)
+		fmt.Println(msg.Sprintf("result.title", result.Title))
 		if result.RoutePath != "" {
-			fmt.Printf("   パス: %s\n", result.RoutePath)
+			fmt.Println(msg.Sprintf("result.path", result.RoutePath))
+		}
+		fmt.Println(msg.Sprintf("result.code", len(result.CodeFiles)))
+		if len(result.Redactions) > 0 {
+			fmt.Println(msg.Sprintf("result.redacted", redact.Total(result.Redactions), redact.Summary(result.Redactions)))
+		}
+		if len(result.DroppedFiles) > 0 {
+			fmt.Println(msg.Sprintf("result.dropped", strings.Join(result.DroppedFiles, ", ")))
+		}
+		if result.Cached {
+			fmt.Println(msg.Sprintf("result.cached"))
 		}
-		fmt.Printf("   関連コード: %dファイル\n", len(result.CodeFiles))
 
 		if result.Error != nil {
-			fmt.Printf("   ❌ エラー: %v\n", result.Error)
+			fmt.Println(msg.Sprintf("result.error", result.Error))
+			printSections(result.Sections)
+			continue
+		}
+
+		if result.Skipped {
+			fmt.Println(msg.Sprintf("result.skipped", result.SkipReason))
+			printSections(result.Sections)
 			continue
 		}
 
 		if result.Verification == nil {
-			fmt.Println("   ⚠️  検証結果がありません")
+			fmt.Println(msg.Sprintf("result.no_verification"))
 			continue
 		}
 
 		emoji := getStatusEmoji(float64(result.Verification.MatchPercentage))
 		// 個別閾値未達の場合はマークを追加
 		belowThreshold := ""
-		if failUnder > 0 && result.Verification.MatchPercentage < failUnder {
-			belowThreshold = fmt.Sprintf(" ← Below threshold (%d%%)", failUnder)
+		if failUnder > 0 && failUnderScore(result.Verification, lowerBound) < failUnder {
+			belowThreshold = msg.Sprintf("result.below_threshold", failUnder)
+		}
+		fmt.Println(msg.Sprintf("result.match", emoji, result.Verification.MatchPercentage, belowThreshold))
+		if result.Verification.Retries > 0 {
+			fmt.Println(msg.Sprintf("result.retries", result.Verification.Retries))
+		}
+		if c := result.Verification.Consensus; c != nil {
+			var scores []string
+			for _, m := range c.Members {
+				if m.Error != "" {
+					scores = append(scores, msg.Sprintf("result.member_error", m.Provider, m.Model))
+					continue
+				}
+				scores = append(scores, fmt.Sprintf("%s/%s %d%%", m.Provider, m.Model, m.MatchPercentage))
+			}
+			fmt.Println(msg.Sprintf("result.ensemble", strings.Join(scores, " / "), c.Spread))
+			if c.NeedsReview {
+				fmt.Println(msg.Sprintf("result.needs_review"))
+			}
+		}
+		if s := result.Verification.Samples; s != nil {
+			fmt.Println(msg.Sprintf("result.samples", len(s.Scores)+s.Failed, s.Min, s.Max, s.StdDev))
+			if s.Failed > 0 {
+				fmt.Println(msg.Sprintf("result.samples_failed", s.Failed))
+			}
+			if s.Unstable {
+				fmt.Println(msg.Sprintf("result.unstable"))
+			}
+			for _, vote := range s.UnstableMatched {
+				fmt.Println(msg.Sprintf("result.unstable_matched", vote.Item, vote.Votes, len(s.Scores)))
+			}
+			for _, vote := range s.UnstableUnmatched {
+				fmt.Println(msg.Sprintf("result.unstable_unmatched", vote.Item, vote.Votes, len(s.Scores)))
+			}
+		}
+		if len(result.Usage) > 0 {
+			for _, u := range result.Usage {
+				fmt.Println(msg.Sprintf("result.usage", u.Provider, u.Model, u.InputTokens, u.OutputTokens, formatLatency(u.LatencyMs), u.Attempts))
+			}
+		} else if result.Verification.Provider != "" {
+			fmt.Println(msg.Sprintf("result.provider", result.Verification.Provider, result.Verification.Model))
+		}
+		if result.Verification.Fallback != "" {
+			fmt.Println(msg.Sprintf("result.fallback", result.Verification.Fallback))
+		}
+		printSections(result.Sections)
+		if result.Verification.Chunks > 0 {
+			fmt.Println(msg.Sprintf("result.chunks", result.Verification.Chunks))
+		}
+		switch result.Verification.Repair {
+		case ai.RepairFixed:
+			fmt.Println(msg.Sprintf("result.repaired"))
+		case ai.RepairReasked:
+			fmt.Println(msg.Sprintf("result.reasked"))
 		}
-		fmt.Printf("   %s 一致度: %d%%%s\n", emoji, result.Verification.MatchPercentage, belowThreshold)
 
 		if len(result.Verification.MatchedItems) > 0 {
-			fmt.Println("   ✓ 一致:")
+			fmt.Println(msg.Sprintf("result.matched"))
 			for i, item := range result.Verification.MatchedItems {
 				if i >= maxDisplayItems {
-					fmt.Printf("     ... 他%d件\n", len(result.Verification.MatchedItems)-maxDisplayItems)
+					fmt.Println(msg.Sprintf("result.more_items", len(result.Verification.MatchedItems)-maxDisplayItems))
 					break
 				}
 				fmt.Printf("     - %s\n", item)
@@ -440,10 +649,10 @@ func outputConsole(summary *verifier.Summary, failUnder int) {
 		}
 
 		if len(result.Verification.UnmatchedItems) > 0 {
-			fmt.Println("   ✗ 不一致:")
+			fmt.Println(msg.Sprintf("result.unmatched"))
 			for i, item := range result.Verification.UnmatchedItems {
 				if i >= maxDisplayItems {
-					fmt.Printf("     ... 他%d件\n", len(result.Verification.UnmatchedItems)-maxDisplayItems)
+					fmt.Println(msg.Sprintf("result.more_items", len(result.Verification.UnmatchedItems)-maxDisplayItems))
 					break
 				}
 				fmt.Printf("     - %s\n", item)
@@ -453,14 +662,26 @@ func outputConsole(summary *verifier.Summary, failUnder int) {
 
 	// サマリー
 	fmt.Println("\n" + strings.Repeat("━", separatorWidthNormal))
-	fmt.Println("\n📊 サマリー")
-	fmt.Printf("   総SPEC数: %d\n", summary.TotalSpecs)
-	fmt.Printf("   平均一致度: %.1f%%\n", summary.AverageMatch)
-	fmt.Printf("   高一致(≥80%%): %d件\n", summary.HighMatchCount)
-	fmt.Printf("   低一致(<50%%): %d件\n", summary.LowMatchCount)
+	fmt.Println("\n" + msg.Sprintf("summary.header"))
+	fmt.Println(msg.Sprintf("summary.total_specs", summary.TotalSpecs))
+	fmt.Println(msg.Sprintf("summary.average", summary.AverageMatch))
+	fmt.Println(msg.Sprintf("summary.high", summary.HighMatchCount))
+	fmt.Println(msg.Sprintf("summary.low", summary.LowMatchCount))
+	if summary.CachedSpecs > 0 {
+		fmt.Println(msg.Sprintf("summary.cached", summary.CachedSpecs))
+	}
+	if summary.Redactions > 0 {
+		fmt.Println(msg.Sprintf("summary.redacted", summary.Redactions))
+	}
+	if summary.DroppedFiles > 0 {
+		fmt.Println(msg.Sprintf("summary.dropped", summary.DroppedFiles))
+	}
+	if summary.SkippedSpecs > 0 {
+		fmt.Println(msg.Sprintf("summary.skipped", summary.SkippedSpecs, exitCodeBudgetExceeded))
+	}
 
 	// 詳細バー
-	fmt.Println("\n   詳細:")
+	fmt.Println("\n" + msg.Sprintf("summary.details"))
 	for _, result := range summary.Results {
 		percentage := 0
 		if result.Verification != nil {
@@ -470,9 +691,52 @@ func outputConsole(summary *verifier.Summary, failUnder int) {
 		fmt.Printf("   %s %3d%% %s\n", bar, percentage, result.SpecFile)
 	}
 
+	// セクション単位の検証でのセクションごとの平均
+	if len(summary.SectionAverages) > 0 {
+		fmt.Println("\n" + msg.Sprintf("summary.section_averages"))
+		for _, section := range summary.SectionAverages {
+			bar := buildProgressBar(section.AverageMatch, progressBarLengthSmall)
+			fmt.Println(msg.Sprintf("summary.section_average", bar, section.AverageMatch, section.Name, section.Specs))
+		}
+	}
+
+	// APIの使用量（チームごとの費用の把握用）
+	if len(summary.UsageByModel) > 0 {
+		fmt.Println("\n" + msg.Sprintf("summary.usage_by_model"))
+		for _, u := range summary.UsageByModel {
+			printUsageTotal(u.Provider+"/"+u.Model, u)
+		}
+	}
+	if len(summary.UsageBySpecType) > 0 {
+		fmt.Println("\n" + msg.Sprintf("summary.usage_by_spec_type"))
+		for _, u := range summary.UsageBySpecType {
+			printUsageTotal(u.SpecType, u)
+		}
+	}
+
+	// アンサンブル検証で評価が割れたSPECの表示
+	if len(summary.ReviewSpecs) > 0 {
+		fmt.Println("\n" + msg.Sprintf("summary.review", len(summary.ReviewSpecs)))
+		for _, spec := range summary.ReviewSpecs {
+			fmt.Println(msg.Sprintf("summary.review_spec", spec.SpecFile, spec.MatchPercentage, spec.Spread, spec.Title))
+		}
+	}
+
+	// 自己一貫性チェックで結果がばらついたSPECの表示
+	if len(summary.UnstableSpecs) > 0 {
+		fmt.Println("\n" + msg.Sprintf("summary.unstable", len(summary.UnstableSpecs)))
+		for _, spec := range summary.UnstableSpecs {
+			fmt.Println(msg.Sprintf("summary.unstable_spec", spec.SpecFile, spec.MatchPercentage, spec.Min, spec.Max, spec.StdDev, spec.Title))
+		}
+	}
+
 	// 個別閾値未達の表示
 	if len(summary.FailingSpecs) > 0 {
-		fmt.Printf("\n❌ 個別閾値未達 (%d%% 未満): %d件\n", failUnder, len(summary.FailingSpecs))
+		basis := ""
+		if lowerBound {
+			basis = msg.Sprintf("summary.failing_lower_bound")
+		}
+		fmt.Println("\n" + msg.Sprintf("summary.failing", failUnder, basis, len(summary.FailingSpecs)))
 		for _, spec := range summary.FailingSpecs {
 			fmt.Printf("   - %s (%d%%) : %s\n", spec.SpecFile, spec.MatchPercentage, spec.Title)
 		}
@@ -481,6 +745,36 @@ func outputConsole(summary *verifier.Summary, failUnder int) {
 	fmt.Println()
 }
 
+// printUsageTotal は集計した使用量を1行で表示する
+func printUsageTotal(label string, u verifier.UsageTotal) {
+	fmt.Println(msg.Sprintf("summary.usage_total", label, u.Specs, u.InputTokens, u.OutputTokens, formatLatency(u.LatencyMs), u.Attempts))
+}
+
+// formatLatency はミリ秒の所要時間を読みやすい形式で返す
+func formatLatency(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).String()
+}
+
+// printSections はセクションごとの一致度を表示する
+// SPECがスキップまたはエラーになった場合も、それまでに検証したセクションと失敗したセクションを表示する
+func printSections(sections []verifier.SectionResult) {
+	if len(sections) == 0 {
+		return
+	}
+	fmt.Println(msg.Sprintf("result.sections"))
+	for _, section := range sections {
+		switch {
+		case section.Error != "":
+			fmt.Println(msg.Sprintf("result.section_error", section.Name, section.Weight, section.Error))
+		case section.SkipReason != "":
+			fmt.Println(msg.Sprintf("result.section_skipped", section.Name, section.Weight, section.SkipReason))
+		default:
+			bar := buildProgressBar(float64(section.Verification.MatchPercentage), progressBarLengthSmall)
+			fmt.Println(msg.Sprintf("result.section", bar, section.Verification.MatchPercentage, section.Name, section.Weight))
+		}
+	}
+}
+
 // getStatusEmoji returns an emoji based on the percentage threshold
 func getStatusEmoji(percentage float64) string {
 	if percentage >= 80 {
@@ -514,33 +808,50 @@ func getCategoryTag(category string) string {
 // getCategoryDisplay はカテゴリの表示名とアイコンを返す
 func getCategoryDisplay(category string) (emoji string, label string) {
 	if category == categoryUI {
-		return "🖥️", "ページ (UI)"
+		return "🖥️", msg.Sprintf("coverage.category_ui")
 	}
 	return "🔌", "API"
 }
 
+// ensureAPIKey はプロバイダーに必要なAPIキーがあるかを確認する
+// キーがなく ai.heuristic_fallback が有効な場合は、ヒューリスティック検証に切り替えて true を返す
+func ensureAPIKey(cfg *config.Config) bool {
+	if cfg.AIAPIKey != "" || !ai.RequiresAPIKey(cfg.AIProvider) {
+		return true
+	}
+	if !cfg.AI.HeuristicFallback {
+		return false
+	}
+	// JSON出力を壊さないよう警告は標準エラーに出力する
+	fmt.Fprintln(os.Stderr, msg.Sprintf("warn.heuristic_no_key"))
+	cfg.AIProvider = ai.HeuristicProviderName
+	cfg.AIProviderFallbacks = nil
+	return true
+}
+
 // loadConfigAndProvider loads config and creates AI provider from common options
 // Returns config, provider, and bool indicating success (false means error was printed and os.Exit should be called)
 func loadConfigAndProvider(opts commonOptions) (*config.Config, ai.Provider, bool) {
 	cfg, err := loadConfig(opts)
 	if err != nil {
-		fmt.Printf("エラー: 設定ファイルの読み込みに失敗しました: %v\n", err)
+		fmt.Println(msg.Sprintf("error.load_config", err))
 		return nil, nil, false
 	}
 
 	if len(cfg.APISources) == 0 {
-		fmt.Println("エラー: api_sources が設定されていません。")
+		fmt.Println(msg.Sprintf("error.no_api_sources"))
 		return nil, nil, false
 	}
 
-	if cfg.AIAPIKey == "" {
-		fmt.Println("エラー: APIキーが設定されていません。")
+	if !ensureAPIKey(cfg) {
+		fmt.Println(msg.Sprintf("error.no_api_key"))
+		fmt.Println(msg.Sprintf("hint.heuristic_extract"))
 		return nil, nil, false
 	}
 
-	provider, err := ai.NewProvider(cfg.AIProvider, cfg.AIAPIKey)
+	provider, err := verifier.NewProvider(cfg)
 	if err != nil {
-		fmt.Printf("エラー: AIプロバイダーの作成に失敗しました: %v\n", err)
+		fmt.Println(msg.Sprintf("error.create_provider", err))
 		return nil, nil, false
 	}
 
@@ -558,7 +869,7 @@ func runEndpoints(args []string) {
 			os.Exit(1)
 		}
 		if len(cfg.APISources) == 0 {
-			fmt.Println("設定ファイルに以下のように api_sources を追加してください:")
+			fmt.Println(msg.Sprintf("hint.add_api_sources"))
 			fmt.Println(`
 api_sources:
   - type: express
@@ -572,16 +883,38 @@ api_sources:
 	}
 
 	if !commonOpts.jsonOutput {
-		fmt.Println("\n📡 APIエンドポイントを抽出中...")
+		fmt.Println("\n" + msg.Sprintf("endpoints.extracting"))
+	}
+
+	policy, err := egress.NewPolicy(cfg.Egress.Allow, cfg.Egress.Deny)
+	if err != nil {
+		fmt.Println(msg.Sprintf("error.extract_endpoints", err))
+		os.Exit(1)
+	}
+	redactor, err := redact.FromConfig(cfg)
+	if err != nil {
+		fmt.Println(msg.Sprintf("error.extract_endpoints", err))
+		os.Exit(1)
+	}
+	// JSON出力を壊さないよう、除外したファイルとマスクした件数は標準エラーに出力する
+	onDropped := func(path string) {
+		fmt.Fprintln(os.Stderr, msg.Sprintf("endpoints.dropped", path))
+	}
+	onRedacted := func(counts map[string]int) {
+		fmt.Fprintln(os.Stderr, msg.Sprintf("endpoints.redacted", redact.Total(counts), redact.Summary(counts)))
 	}
 
 	ctx := context.Background()
-	endpoints, err := parser.ExtractEndpoints(ctx, cfg.APISources, provider)
+	endpoints, err := parser.ExtractEndpoints(ctx, cfg.APISources, provider,
+		parser.WithEgressPolicy(policy, onDropped), parser.WithRedactor(redactor, onRedacted))
 	if err != nil {
-		fmt.Printf("エラー: エンドポイントの抽出に失敗しました: %v\n", err)
+		fmt.Println(msg.Sprintf("error.extract_endpoints", err))
 		os.Exit(1)
 	}
 
+	// 応答の修復や再質問を経て抽出したエンドポイントも、JSON出力を壊さないよう標準エラーに出力する
+	printEndpointRepairs(os.Stderr, parser.CountRepairs(endpoints))
+
 	if commonOpts.jsonOutput {
 		outputEndpointsJSON(endpoints)
 	} else {
@@ -589,6 +922,16 @@ api_sources:
 	}
 }
 
+// printEndpointRepairs は応答の修復や再質問を経て抽出したエンドポイント数を出力する
+func printEndpointRepairs(w io.Writer, repairs map[string]int) {
+	if n := repairs[ai.RepairFixed]; n > 0 {
+		fmt.Fprintln(w, msg.Sprintf("endpoints.repaired", n))
+	}
+	if n := repairs[ai.RepairReasked]; n > 0 {
+		fmt.Fprintln(w, msg.Sprintf("endpoints.reasked", n))
+	}
+}
+
 func outputEndpointsJSON(endpoints []parser.Endpoint) {
 	data, _ := json.MarshalIndent(endpoints, "", "  ")
 	fmt.Println(string(data))
@@ -596,11 +939,11 @@ func outputEndpointsJSON(endpoints []parser.Endpoint) {
 
 func outputEndpointsConsole(endpoints []parser.Endpoint) {
 	if len(endpoints) == 0 {
-		fmt.Println("エンドポイントが見つかりませんでした。")
+		fmt.Println(msg.Sprintf("endpoints.none"))
 		return
 	}
 
-	fmt.Printf("📡 検出されたエンドポイント (%d件)\n", len(endpoints))
+	fmt.Println(msg.Sprintf("endpoints.header", len(endpoints)))
 	fmt.Println(strings.Repeat("━", separatorWidthWide))
 
 	// ソースごとにグループ化
@@ -610,7 +953,7 @@ func outputEndpointsConsole(endpoints []parser.Endpoint) {
 	}
 
 	for source, eps := range bySource {
-		fmt.Printf("\n📁 %s (%d件)\n", source, len(eps))
+		fmt.Println("\n" + msg.Sprintf("endpoints.source", source, len(eps)))
 		fmt.Println(strings.Repeat("─", separatorWidthNarrow))
 		for _, ep := range eps {
 			desc := ""
@@ -636,19 +979,19 @@ func runCoverage(args []string) {
 	if !ok {
 		// Provide more specific error message for coverage command
 		if cfg != nil && len(cfg.APISources) == 0 {
-			fmt.Println("カバレッジレポートにはAPIエンドポイントの抽出設定が必要です。")
+			fmt.Println(msg.Sprintf("hint.coverage_api_sources"))
 		}
 		os.Exit(1)
 	}
 
 	if !commonOpts.jsonOutput {
-		fmt.Println("\n📊 APIカバレッジレポートを生成中...")
+		fmt.Println("\n" + msg.Sprintf("coverage.generating"))
 	}
 
 	ctx := context.Background()
 	report, err := parser.CalculateCoverage(ctx, cfg, provider)
 	if err != nil {
-		fmt.Printf("エラー: カバレッジレポートの生成に失敗しました: %v\n", err)
+		fmt.Println(msg.Sprintf("error.coverage", err))
 		os.Exit(1)
 	}
 
@@ -661,7 +1004,7 @@ func runCoverage(args []string) {
 	// 閾値チェック
 	if commonOpts.failUnder > 0 && report.CoveragePercentage < float64(commonOpts.failUnder) {
 		if !commonOpts.jsonOutput {
-			fmt.Printf("❌ カバレッジが閾値未満です: %.1f%% < %d%%\n", report.CoveragePercentage, commonOpts.failUnder)
+			fmt.Println(msg.Sprintf("coverage.below_threshold", report.CoveragePercentage, commonOpts.failUnder))
 		}
 		os.Exit(1)
 	}
@@ -689,15 +1032,29 @@ func outputCoverageConsole(report *parser.CoverageReport, failUnder int) {
 	printCoveredRoutes(report.Covered)
 	printUncoveredRoutes(report.Uncovered)
 	printOrphanedSpecs(report.Orphaned)
+	if len(report.DroppedFiles) > 0 {
+		fmt.Println()
+		for _, path := range report.DroppedFiles {
+			fmt.Println(msg.Sprintf("endpoints.dropped", path))
+		}
+	}
+	if len(report.Redactions) > 0 {
+		fmt.Println()
+		fmt.Println(msg.Sprintf("endpoints.redacted", redact.Total(report.Redactions), redact.Summary(report.Redactions)))
+	}
+	if len(report.Repairs) > 0 {
+		fmt.Println()
+		printEndpointRepairs(os.Stdout, report.Repairs)
+	}
 	fmt.Println()
 }
 
 // printCoverageHeader はカバレッジレポートのヘッダーを出力する
 func printCoverageHeader(failUnder int) {
 	fmt.Println(strings.Repeat("━", separatorWidthWide))
-	fmt.Println("📊 ルートカバレッジレポート")
+	fmt.Println(msg.Sprintf("coverage.header"))
 	if failUnder > 0 {
-		fmt.Printf("   (閾値: %d%%)\n", failUnder)
+		fmt.Println(msg.Sprintf("coverage.threshold", failUnder))
 	}
 	fmt.Println(strings.Repeat("━", separatorWidthWide))
 }
@@ -705,13 +1062,13 @@ func printCoverageHeader(failUnder int) {
 // printOverallSummary は全体サマリーを出力する
 func printOverallSummary(report *parser.CoverageReport) {
 	emoji := getStatusEmoji(report.CoveragePercentage)
-	fmt.Printf("\n%s 全体カバレッジ: %.1f%%\n", emoji, report.CoveragePercentage)
-	fmt.Printf("   ルート総数: %d\n", report.TotalEndpoints)
-	fmt.Printf("   カバー済み (SPECあり): %d\n", report.CoveredEndpoints)
-	fmt.Printf("   未カバー (SPECなし): %d\n", report.UncoveredEndpoints)
-	fmt.Printf("   SPEC総数: %d\n", report.TotalSpecs)
+	fmt.Println("\n" + msg.Sprintf("coverage.overall", emoji, report.CoveragePercentage))
+	fmt.Println(msg.Sprintf("coverage.total_routes", report.TotalEndpoints))
+	fmt.Println(msg.Sprintf("coverage.covered", report.CoveredEndpoints))
+	fmt.Println(msg.Sprintf("coverage.uncovered", report.UncoveredEndpoints))
+	fmt.Println(msg.Sprintf("coverage.total_specs", report.TotalSpecs))
 	if report.OrphanedSpecs > 0 {
-		fmt.Printf("   孤立SPEC (対応なし): %d\n", report.OrphanedSpecs)
+		fmt.Println(msg.Sprintf("coverage.orphaned", report.OrphanedSpecs))
 	}
 
 	bar := buildProgressBar(report.CoveragePercentage, progressBarLength)
@@ -725,7 +1082,7 @@ func printCategoryBreakdown(report *parser.CoverageReport) {
 	}
 
 	fmt.Println("\n" + strings.Repeat("─", separatorWidthWide))
-	fmt.Println("📂 カテゴリ別カバレッジ")
+	fmt.Println(msg.Sprintf("coverage.by_category"))
 	fmt.Println(strings.Repeat("─", separatorWidthWide))
 
 	// UIを先に、APIを後に表示
@@ -745,7 +1102,7 @@ func printCategoryCoverage(catName string, cat *parser.CategoryCoverage) {
 	statusEmoji := getStatusEmoji(cat.Percentage)
 
 	fmt.Printf("\n%s %s\n", catEmoji, catLabel)
-	fmt.Printf("   %s カバレッジ: %.1f%% (%d/%d)\n", statusEmoji, cat.Percentage, cat.Covered, cat.Total)
+	fmt.Println(msg.Sprintf("coverage.category", statusEmoji, cat.Percentage, cat.Covered, cat.Total))
 	fmt.Printf("   [%s]\n", buildProgressBar(cat.Percentage, progressBarLength))
 
 	if len(cat.UncoveredItems) > 0 {
@@ -755,7 +1112,7 @@ func printCategoryCoverage(catName string, cat *parser.CategoryCoverage) {
 
 // printUncoveredSummary は未カバー項目の簡潔なサマリーを出力する
 func printUncoveredSummary(catName string, items []parser.CoverageItem) {
-	fmt.Printf("   ❌ 未カバー: ")
+	fmt.Print(msg.Sprintf("coverage.uncovered_items"))
 	paths := make([]string, 0, len(items))
 	for _, item := range items {
 		if catName == categoryAPI && item.Method != "" {
@@ -768,7 +1125,7 @@ func printUncoveredSummary(catName string, items []parser.CoverageItem) {
 	if len(paths) <= maxDisplay {
 		fmt.Println(strings.Join(paths, ", "))
 	} else {
-		fmt.Printf("%s ... 他%d件\n", strings.Join(paths[:maxDisplay], ", "), len(paths)-maxDisplay)
+		fmt.Println(msg.Sprintf("coverage.more_items", strings.Join(paths[:maxDisplay], ", "), len(paths)-maxDisplay))
 	}
 }
 
@@ -779,7 +1136,7 @@ func printCoveredRoutes(items []parser.CoverageItem) {
 	}
 
 	fmt.Println("\n" + strings.Repeat("─", separatorWidthWide))
-	fmt.Printf("✅ カバー済みルート (%d件)\n", len(items))
+	fmt.Println(msg.Sprintf("coverage.covered_routes", len(items)))
 	fmt.Println(strings.Repeat("─", separatorWidthNarrow))
 	for _, item := range items {
 		specInfo := ""
@@ -797,7 +1154,7 @@ func printUncoveredRoutes(items []parser.CoverageItem) {
 	}
 
 	fmt.Println("\n" + strings.Repeat("─", separatorWidthWide))
-	fmt.Printf("❌ 未カバールート (%d件)\n", len(items))
+	fmt.Println(msg.Sprintf("coverage.uncovered_routes", len(items)))
 	fmt.Println(strings.Repeat("─", separatorWidthNarrow))
 	for _, item := range items {
 		file := ""
@@ -815,7 +1172,7 @@ func printOrphanedSpecs(items []parser.OrphanedSpec) {
 	}
 
 	fmt.Println("\n" + strings.Repeat("─", separatorWidthWide))
-	fmt.Printf("⚠️  孤立SPEC（対応するルートなし） (%d件)\n", len(items))
+	fmt.Println(msg.Sprintf("coverage.orphaned_specs", len(items)))
 	fmt.Println(strings.Repeat("─", separatorWidthNarrow))
 	for _, item := range items {
 		routePath := ""
@@ -834,7 +1191,7 @@ func runTypes(args []string) {
 
 	cfg, err := loadConfig(commonOpts)
 	if err != nil {
-		fmt.Printf("エラー: 設定ファイルの読み込みに失敗しました: %v\n", err)
+		fmt.Println(msg.Sprintf("error.load_config", err))
 		os.Exit(1)
 	}
 
@@ -877,12 +1234,12 @@ func outputTypesJSON(cfg *config.Config, types []string) {
 
 func outputTypesConsole(cfg *config.Config, types []string) {
 	if len(types) == 0 {
-		fmt.Println("定義されているSPECタイプがありません。")
-		fmt.Println("設定ファイルに spec_types または mapping を追加してください。")
+		fmt.Println(msg.Sprintf("types.none"))
+		fmt.Println(msg.Sprintf("types.hint_add"))
 		return
 	}
 
-	fmt.Println("\n📋 定義済みSPECタイプ")
+	fmt.Println("\n" + msg.Sprintf("types.header"))
 	fmt.Println(strings.Repeat("━", separatorWidthNormal))
 
 	for _, typeName := range types {
@@ -892,18 +1249,18 @@ func outputTypesConsole(cfg *config.Config, types []string) {
 		}
 
 		fmt.Printf("\n🏷️  %s\n", typeName)
-		fmt.Printf("   コードパス: %s\n", strings.Join(info.CodePaths, ", "))
+		fmt.Println(msg.Sprintf("types.code_paths", strings.Join(info.CodePaths, ", ")))
 		if len(info.VerificationFocus) > 0 {
-			fmt.Println("   検証観点:")
+			fmt.Println(msg.Sprintf("types.focus"))
 			for _, focus := range info.VerificationFocus {
 				fmt.Printf("     - %s\n", focus)
 			}
 		}
 		if len(info.FilePatterns) > 0 {
-			fmt.Printf("   ファイルパターン: %s\n", strings.Join(info.FilePatterns, ", "))
+			fmt.Println(msg.Sprintf("types.file_patterns", strings.Join(info.FilePatterns, ", ")))
 		}
 		if len(info.ExcludePatterns) > 0 {
-			fmt.Printf("   除外パターン: %s\n", strings.Join(info.ExcludePatterns, ", "))
+			fmt.Println(msg.Sprintf("types.exclude_patterns", strings.Join(info.ExcludePatterns, ", ")))
 		}
 	}
 
@@ -915,7 +1272,7 @@ func runGroups(args []string) {
 
 	cfg, err := loadConfig(commonOpts)
 	if err != nil {
-		fmt.Printf("エラー: 設定ファイルの読み込みに失敗しました: %v\n", err)
+		fmt.Println(msg.Sprintf("error.load_config", err))
 		os.Exit(1)
 	}
 
@@ -953,21 +1310,13 @@ func outputGroupsJSON(cfg *config.Config, groups []string) {
 
 func outputGroupsConsole(cfg *config.Config, groups []string) {
 	if len(groups) == 0 {
-		fmt.Println("定義されているグループがありません。")
-		fmt.Println("設定ファイルに groups を追加してください。")
-		fmt.Println(`
-例:
-groups:
-  frontend:
-    types: [ui]
-    description: "フロントエンド関連"
-  backend:
-    types: [api, service]
-    description: "バックエンド関連"`)
+		fmt.Println(msg.Sprintf("groups.none"))
+		fmt.Println(msg.Sprintf("groups.hint_add"))
+		fmt.Println(msg.Sprintf("groups.example"))
 		return
 	}
 
-	fmt.Println("\n📦 定義済みグループ")
+	fmt.Println("\n" + msg.Sprintf("groups.header"))
 	fmt.Println(strings.Repeat("━", separatorWidthNormal))
 
 	for _, groupName := range groups {
@@ -977,13 +1326,108 @@ groups:
 		}
 
 		fmt.Printf("\n🏷️  %s\n", groupName)
-		fmt.Printf("   タイプ: %s\n", strings.Join(group.Types, ", "))
+		fmt.Println(msg.Sprintf("groups.types", strings.Join(group.Types, ", ")))
 		if group.Description != "" {
-			fmt.Printf("   説明: %s\n", group.Description)
+			fmt.Println(msg.Sprintf("groups.description", group.Description))
 		}
 	}
 
-	fmt.Printf("\n使用方法:\n")
-	fmt.Printf("  spec-verify check --group <グループ名>\n")
+	fmt.Println(msg.Sprintf("groups.usage"))
 	fmt.Println()
 }
+
+// runCache はキャッシュ管理コマンドを実行する
+func runCache(args []string) {
+	if len(args) == 0 || args[0] != "prune" {
+		fmt.Println(msg.Sprintf("cache.usage"))
+		os.Exit(1)
+	}
+
+	commonOpts := parseCommonOptions(args[1:])
+	olderThan := defaultCachePruneAge
+	for i := 1; i < len(args); i++ {
+		switch {
+		case args[i] == "--all":
+			olderThan = 0
+		case args[i] == "--older-than" && i+1 < len(args):
+			d, err := time.ParseDuration(args[i+1])
+			if err != nil || d <= 0 {
+				fmt.Println(msg.Sprintf("error.older_than", args[i+1]))
+				os.Exit(1)
+			}
+			olderThan = d
+			i++
+		}
+	}
+
+	cfg, err := loadConfig(commonOpts)
+	if err != nil {
+		fmt.Println(msg.Sprintf("error.load_config", err))
+		os.Exit(1)
+	}
+
+	c := cache.New(cfg.Cache.Dir)
+	result, err := c.Prune(olderThan)
+	if err != nil {
+		fmt.Println(msg.Sprintf("error.prune", err))
+		os.Exit(1)
+	}
+
+	if commonOpts.jsonOutput {
+		data, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+	fmt.Println(msg.Sprintf("cache.pruned",
+		result.Removed, float64(result.RemovedBytes)/1024, result.Kept, c.Dir()))
+}
+
+// runPrompt はプロンプト関連のコマンドを実行する
+func runPrompt(args []string) {
+	if len(args) < 2 || args[0] != "show" || strings.HasPrefix(args[1], "-") {
+		fmt.Println(msg.Sprintf("prompt.usage"))
+		os.Exit(1)
+	}
+
+	specFile := args[1]
+	commonOpts := parseCommonOptions(args[2:])
+	cfg, err := loadConfig(commonOpts)
+	if err != nil {
+		fmt.Println(msg.Sprintf("error.load_config", err))
+		os.Exit(1)
+	}
+
+	preview, err := verifier.RenderPrompts(cfg, specFile)
+	if err != nil {
+		fmt.Println(msg.Sprintf("error.render_prompt", err))
+		os.Exit(1)
+	}
+
+	if commonOpts.jsonOutput {
+		data, _ := json.MarshalIndent(preview, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	fmt.Println(msg.Sprintf("prompt.template", preview.Template))
+	if preview.EvidenceTemplate != "" {
+		fmt.Println(msg.Sprintf("prompt.chunked", preview.EvidenceTemplate, preview.ReduceTemplate))
+	}
+	if len(preview.Prompts) == 0 {
+		fmt.Println(msg.Sprintf("prompt.no_code"))
+		return
+	}
+	for _, p := range preview.Prompts {
+		switch {
+		case p.Section != "" && p.Chunk > 0:
+			fmt.Println("\n" + msg.Sprintf("prompt.section_chunk", p.Section, p.Chunk, p.Chunks))
+		case p.Chunk > 0:
+			fmt.Println("\n" + msg.Sprintf("prompt.chunk", p.Chunk, p.Chunks))
+		case p.Section != "":
+			fmt.Println("\n" + msg.Sprintf("prompt.section", p.Section))
+		default:
+			fmt.Println()
+		}
+		fmt.Println(p.Prompt)
+	}
+}
@@ -0,0 +1,7 @@
+import { Router } from "express";
+
+export const orders = Router();
+
+orders.post("/orders", (req, res) => {
+  res.status(201).json({ id: 1 });
+});
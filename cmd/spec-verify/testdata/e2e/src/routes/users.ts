@@ -0,0 +1,11 @@
+import { Router } from "express";
+
+export const users = Router();
+
+users.get("/users", (req, res) => {
+  res.json([{ id: 1, name: "alice" }]);
+});
+
+users.get("/users/:id", (req, res) => {
+  res.json({ id: req.params.id });
+});
@@ -0,0 +1,364 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// cassetteVersion はカセットファイルの形式バージョン
+const cassetteVersion = 1
+
+// カセットに記録するリクエストの種類
+const (
+	cassetteKindVerify    = "verify"
+	cassetteKindEndpoints = "endpoints"
+)
+
+// ReplayProviderName はカセットから応答を再生するプロバイダーの名前
+const ReplayProviderName = "replay"
+
+// replayModel は再生プロバイダーのモデル名
+const replayModel = "cassette"
+
+// ErrCassetteMiss はカセットに該当するプロンプトの記録がない場合のエラー
+var ErrCassetteMiss = errors.New("no recorded response in cassette")
+
+// CassetteEntry はカセットに記録された1件のリクエストと応答
+type CassetteEntry struct {
+	// プロンプトのハッシュ（種類を含む）
+	Key string `json:"key"`
+
+	// リクエストの種類（verify, endpoints）
+	Kind string `json:"kind"`
+
+	// 記録時のプロバイダー名とモデル名（参考情報。再生時の照合には使用しない）
+	Provider string `json:"provider,omitempty"`
+	Model    string `json:"model,omitempty"`
+
+	// 検証結果（kind が verify の場合）
+	Verification *VerificationResult `json:"verification,omitempty"`
+
+	// エンドポイント抽出結果（kind が endpoints の場合）
+	Endpoints []EndpointResult `json:"endpoints,omitempty"`
+}
+
+// cassetteFile はカセットファイルの内容
+type cassetteFile struct {
+	Version int             `json:"version"`
+	Entries []CassetteEntry `json:"entries"`
+}
+
+// Cassette はプロンプトのハッシュをキーとして応答を保存するファイル
+// 同じパスのカセットはプロセス内で1つのインスタンスを共有し、記録のたびにファイルへ書き出す
+type Cassette struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]CassetteEntry
+}
+
+// openCassettes はプロセス内で開いているカセット（キーは絶対パス）
+var openCassettes sync.Map
+
+// OpenCassette は記録用にカセットを開く
+// ファイルが存在しない場合は空のカセットを作成する（最初の記録時にファイルを書き出す）
+func OpenCassette(path string) (*Cassette, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve cassette path: %w", err)
+	}
+	if c, ok := openCassettes.Load(abs); ok {
+		return c.(*Cassette), nil
+	}
+
+	c := &Cassette{path: abs, entries: make(map[string]CassetteEntry)}
+	data, err := os.ReadFile(abs)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	default:
+		var file cassetteFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+		}
+		if file.Version != cassetteVersion {
+			return nil, fmt.Errorf("unsupported cassette version %d in %s (expected %d)", file.Version, path, cassetteVersion)
+		}
+		for _, e := range file.Entries {
+			c.entries[e.Key] = e
+		}
+	}
+
+	actual, _ := openCassettes.LoadOrStore(abs, c)
+	return actual.(*Cassette), nil
+}
+
+// LoadCassette は再生用にカセットを開く
+// OpenCassette と異なり、ファイルが存在しない場合はエラーを返す
+func LoadCassette(path string) (*Cassette, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open cassette: %w", err)
+	}
+	return OpenCassette(path)
+}
+
+// Path はカセットファイルのパスを返す
+func (c *Cassette) Path() string {
+	return c.path
+}
+
+// Len は記録されている応答の数を返す
+func (c *Cassette) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// lookup はキーに対応する記録を返す
+func (c *Cassette) lookup(key string) (CassetteEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	return e, ok
+}
+
+// record は応答を記録してファイルに書き出す
+// 同じキーの記録がある場合は上書きする
+func (c *Cassette) record(entry CassetteEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[entry.Key] = entry
+	return c.save()
+}
+
+// save はカセットをファイルに書き出す（呼び出し側でロックを取得すること）
+// 差分を確認しやすいようにキー順に並べ、一時ファイルからの rename で書き換える
+func (c *Cassette) save() error {
+	file := cassetteFile{Version: cassetteVersion, Entries: make([]CassetteEntry, 0, len(c.entries))}
+	for _, e := range c.entries {
+		file.Entries = append(file.Entries, e)
+	}
+	sort.Slice(file.Entries, func(i, j int) bool {
+		return file.Entries[i].Key < file.Entries[j].Key
+	})
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".cassette-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	// カセットはリポジトリにコミットして共有するため、通常のファイルと同じ権限にする
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// cassetteKey はリクエストの種類とプロンプトからカセットのキーを作成する
+func cassetteKey(kind, prompt string) string {
+	sum := sha256.Sum256([]byte(kind + "\x00" + prompt))
+	return hex.EncodeToString(sum[:])
+}
+
+// verifyCassetteKey は検証リクエストのキーを返す
+func verifyCassetteKey(specContent string, codeContents map[string]string, opts *VerifyOptions) (string, error) {
+	prompt, err := BuildVerificationPrompt(specContent, codeContents, opts)
+	if err != nil {
+		return "", err
+	}
+	return cassetteKey(cassetteKindVerify, prompt), nil
+}
+
+// endpointsCassetteKey はエンドポイント抽出リクエストのキーを返す
+func endpointsCassetteKey(opts *ExtractOptions, codeContent string) (string, error) {
+	prompt, err := buildExtractionPrompt(opts, codeContent)
+	if err != nil {
+		return "", err
+	}
+	return cassetteKey(cassetteKindEndpoints, prompt), nil
+}
+
+// RecordingProvider は任意のプロバイダーをラップし、応答をカセットに記録するプロバイダー
+type RecordingProvider struct {
+	inner    Provider
+	cassette *Cassette
+}
+
+// NewRecordingProvider は inner の応答を cassette に記録するプロバイダーを作成する
+func NewRecordingProvider(inner Provider, cassette *Cassette) *RecordingProvider {
+	return &RecordingProvider{inner: inner, cassette: cassette}
+}
+
+// Name はラップしているプロバイダーの名前を返す
+func (p *RecordingProvider) Name() string {
+	return p.inner.Name()
+}
+
+// Model はラップしているプロバイダーのモデル名を返す
+func (p *RecordingProvider) Model() string {
+	return p.inner.Model()
+}
+
+// Verify はSPECとコードの一致度を検証し、結果を記録する
+func (p *RecordingProvider) Verify(ctx context.Context, specContent string, codeContents map[string]string) (*VerificationResult, error) {
+	return p.VerifyWithOptions(ctx, specContent, codeContents, nil)
+}
+
+// VerifyWithOptions は検証観点を指定してSPECとコードの一致度を検証し、結果を記録する
+func (p *RecordingProvider) VerifyWithOptions(ctx context.Context, specContent string, codeContents map[string]string, opts *VerifyOptions) (*VerificationResult, error) {
+	var result *VerificationResult
+	var err error
+	if opts != nil {
+		result, err = p.inner.VerifyWithOptions(ctx, specContent, codeContents, opts)
+	} else {
+		result, err = p.inner.Verify(ctx, specContent, codeContents)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := verifyCassetteKey(specContent, codeContents, opts)
+	if err != nil {
+		return nil, err
+	}
+	// 再試行回数、所要時間、呼び出し回数は記録時の状況によるため保存しない
+	recorded := *result
+	recorded.Retries = 0
+	recorded.LatencyMs = 0
+	recorded.Attempts = 0
+	if err := p.cassette.record(CassetteEntry{
+		Key:          key,
+		Kind:         cassetteKindVerify,
+		Provider:     p.inner.Name(),
+		Model:        p.inner.Model(),
+		Verification: &recorded,
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ExtractEndpoints はコードからAPIエンドポイント/ページルートを抽出し、結果を記録する
+func (p *RecordingProvider) ExtractEndpoints(ctx context.Context, opts *ExtractOptions, codeContent string) ([]EndpointResult, error) {
+	endpoints, err := p.inner.ExtractEndpoints(ctx, opts, codeContent)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := endpointsCassetteKey(opts, codeContent)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.cassette.record(CassetteEntry{
+		Key:       key,
+		Kind:      cassetteKindEndpoints,
+		Provider:  p.inner.Name(),
+		Model:     p.inner.Model(),
+		Endpoints: endpoints,
+	}); err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// ReplayProvider はカセットに記録された応答を返すプロバイダー
+// ネットワークやAPIキーを必要としないため、オフラインのテストやデモに使用する
+type ReplayProvider struct {
+	cassette *Cassette
+
+	// 記録がない場合に呼び出すプロバイダー（nilの場合は ErrCassetteMiss を返す）
+	fallback Provider
+}
+
+// NewReplayProvider は cassette から応答を再生するプロバイダーを作成する
+// fallback を指定すると記録がないリクエストをそのプロバイダーに渡す
+// fallback を RecordingProvider にすると、足りない応答を同じカセットに追記できる
+func NewReplayProvider(cassette *Cassette, fallback Provider) *ReplayProvider {
+	return &ReplayProvider{cassette: cassette, fallback: fallback}
+}
+
+// Name はプロバイダー名を返す
+func (p *ReplayProvider) Name() string {
+	return ReplayProviderName
+}
+
+// Model は使用しているモデル名を返す
+func (p *ReplayProvider) Model() string {
+	return replayModel
+}
+
+// Verify はカセットから検証結果を返す
+func (p *ReplayProvider) Verify(ctx context.Context, specContent string, codeContents map[string]string) (*VerificationResult, error) {
+	return p.VerifyWithOptions(ctx, specContent, codeContents, nil)
+}
+
+// VerifyWithOptions はカセットから検証結果を返す
+func (p *ReplayProvider) VerifyWithOptions(ctx context.Context, specContent string, codeContents map[string]string, opts *VerifyOptions) (*VerificationResult, error) {
+	key, err := verifyCassetteKey(specContent, codeContents, opts)
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := p.cassette.lookup(key); ok && e.Kind == cassetteKindVerify && e.Verification != nil {
+		// 再生した応答はAPIを呼び出していないため、記録時の使用量は返さない
+		result := *e.Verification
+		result.Usage = nil
+		result.Retries = 0
+		result.LatencyMs = 0
+		result.Attempts = 0
+		return &result, nil
+	}
+	if p.fallback != nil {
+		if opts != nil {
+			return p.fallback.VerifyWithOptions(ctx, specContent, codeContents, opts)
+		}
+		return p.fallback.Verify(ctx, specContent, codeContents)
+	}
+	return nil, p.missError(cassetteKindVerify, key)
+}
+
+// ExtractEndpoints はカセットからエンドポイント抽出結果を返す
+func (p *ReplayProvider) ExtractEndpoints(ctx context.Context, opts *ExtractOptions, codeContent string) ([]EndpointResult, error) {
+	key, err := endpointsCassetteKey(opts, codeContent)
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := p.cassette.lookup(key); ok && e.Kind == cassetteKindEndpoints {
+		return append([]EndpointResult(nil), e.Endpoints...), nil
+	}
+	if p.fallback != nil {
+		return p.fallback.ExtractEndpoints(ctx, opts, codeContent)
+	}
+	return nil, p.missError(cassetteKindEndpoints, key)
+}
+
+// missError は記録がない場合のエラーを返す
+func (p *ReplayProvider) missError(kind, key string) error {
+	return fmt.Errorf("%w: %s request %s in %s", ErrCassetteMiss, kind, key[:12], p.cassette.Path())
+}
@@ -0,0 +1,108 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+// countingOllamaServer は固定の応答を返し、リクエスト数を数えるOllama互換のテスト用サーバー
+func countingOllamaServer(t *testing.T, content string, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"message":{"role":"assistant","content":` + content + `},"done":true}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	var requests atomic.Int32
+	server := countingOllamaServer(t, `"{\"matchPercentage\":75,\"matchedItems\":[\"a\"],\"unmatchedItems\":[],\"notes\":\"n\"}"`, &requests)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	code := map[string]string{"src/a.ts": "code"}
+	opts := &VerifyOptions{VerificationFocus: []string{"バリデーション"}}
+
+	cassette, err := OpenCassette(path)
+	if err != nil {
+		t.Fatalf("OpenCassette failed: %v", err)
+	}
+	inner, _ := NewOllamaProvider(server.URL)
+	recorder := NewRecordingProvider(inner, cassette)
+	if _, err := recorder.VerifyWithOptions(context.Background(), "# spec", code, opts); err != nil {
+		t.Fatalf("record failed: %v", err)
+	}
+	if cassette.Len() != 1 || requests.Load() != 1 {
+		t.Fatalf("Len = %d, requests = %d; want 1, 1", cassette.Len(), requests.Load())
+	}
+
+	// 同じパスは同じインスタンスを共有する
+	loaded, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette failed: %v", err)
+	}
+	if loaded != cassette {
+		t.Error("expected the same cassette instance for the same path")
+	}
+
+	replay := NewReplayProvider(loaded, nil)
+	result, err := replay.VerifyWithOptions(context.Background(), "# spec", code, opts)
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	if result.MatchPercentage != 75 || requests.Load() != 1 {
+		t.Errorf("result = %+v, requests = %d", result, requests.Load())
+	}
+	// 再生した応答はAPIを呼び出していないため使用量を返さない
+	if result.Usage != nil || result.Attempts != 0 {
+		t.Errorf("Usage = %+v, Attempts = %d; want no usage for a replayed response", result.Usage, result.Attempts)
+	}
+
+	// プロンプトが異なる（検証観点なし）場合は記録がない
+	if _, err := replay.Verify(context.Background(), "# spec", code); !errors.Is(err, ErrCassetteMiss) {
+		t.Errorf("err = %v, want ErrCassetteMiss", err)
+	}
+	if _, err := replay.ExtractEndpoints(context.Background(), nil, "code"); !errors.Is(err, ErrCassetteMiss) {
+		t.Errorf("err = %v, want ErrCassetteMiss", err)
+	}
+}
+
+func TestReplayFallback(t *testing.T) {
+	var requests atomic.Int32
+	server := countingOllamaServer(t, `"{\"endpoints\":[{\"method\":\"GET\",\"path\":\"/users\"}]}"`, &requests)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	cassette, err := OpenCassette(path)
+	if err != nil {
+		t.Fatalf("OpenCassette failed: %v", err)
+	}
+	inner, _ := NewOllamaProvider(server.URL)
+	replay := NewReplayProvider(cassette, NewRecordingProvider(inner, cassette))
+	opts := &ExtractOptions{SourceType: "express", Category: CategoryAPI}
+
+	// 1回目は記録がないためフォールバックで取得して記録し、2回目はカセットから再生する
+	for i := 0; i < 2; i++ {
+		endpoints, err := replay.ExtractEndpoints(context.Background(), opts, "router.get('/users')")
+		if err != nil {
+			t.Fatalf("ExtractEndpoints failed: %v", err)
+		}
+		if len(endpoints) != 1 || endpoints[0].Path != "/users" {
+			t.Errorf("endpoints = %+v", endpoints)
+		}
+	}
+	if requests.Load() != 1 {
+		t.Errorf("requests = %d, want 1", requests.Load())
+	}
+}
+
+func TestLoadCassette_Errors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadCassette(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected error for missing cassette")
+	}
+}
@@ -0,0 +1,100 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ChainProvider は複数のプロバイダーを順に試すプロバイダー
+// 先頭のプロバイダーが再試行可能なエラー（レート制限、過負荷、サーバーエラー、ネットワークエラー）で
+// 失敗した場合に次のプロバイダーで処理する。認証エラーやリクエストの誤りでは次に進まない
+type ChainProvider struct {
+	providers []Provider
+}
+
+// NewChainProvider は providers を先頭から順に試すプロバイダーを作成する
+func NewChainProvider(providers ...Provider) (*ChainProvider, error) {
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("provider chain requires at least one provider")
+	}
+	return &ChainProvider{providers: providers}, nil
+}
+
+// Name は先頭のプロバイダー名を返す
+func (p *ChainProvider) Name() string {
+	return p.providers[0].Name()
+}
+
+// Model は先頭のプロバイダーのモデル名を返す
+func (p *ChainProvider) Model() string {
+	return p.providers[0].Model()
+}
+
+// Providers はチェーンを構成するプロバイダーを順に返す
+func (p *ChainProvider) Providers() []Provider {
+	return p.providers
+}
+
+// Verify はSPECとコードの一致度を検証する
+func (p *ChainProvider) Verify(ctx context.Context, specContent string, codeContents map[string]string) (*VerificationResult, error) {
+	return p.VerifyWithOptions(ctx, specContent, codeContents, nil)
+}
+
+// VerifyWithOptions はプロバイダーを順に試して検証する
+// 結果には検証したプロバイダーとモデルを設定し、先頭以外のプロバイダーの結果には Fallback も設定する
+func (p *ChainProvider) VerifyWithOptions(ctx context.Context, specContent string, codeContents map[string]string, opts *VerifyOptions) (*VerificationResult, error) {
+	var errs []error
+	for i, provider := range p.providers {
+		var result *VerificationResult
+		var err error
+		if opts != nil {
+			result, err = provider.VerifyWithOptions(ctx, specContent, codeContents, opts)
+		} else {
+			result, err = provider.Verify(ctx, specContent, codeContents)
+		}
+		if err == nil {
+			result.Provider = provider.Name()
+			result.Model = provider.Model()
+			if i > 0 {
+				result.Fallback = provider.Name()
+			}
+			return result, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+		if !p.shouldFallBack(ctx, i, err) {
+			break
+		}
+	}
+	return nil, chainError(errs)
+}
+
+// ExtractEndpoints はプロバイダーを順に試してエンドポイントを抽出する
+func (p *ChainProvider) ExtractEndpoints(ctx context.Context, opts *ExtractOptions, codeContent string) ([]EndpointResult, error) {
+	var errs []error
+	for i, provider := range p.providers {
+		endpoints, err := provider.ExtractEndpoints(ctx, opts, codeContent)
+		if err == nil {
+			return endpoints, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+		if !p.shouldFallBack(ctx, i, err) {
+			break
+		}
+	}
+	return nil, chainError(errs)
+}
+
+// shouldFallBack は i 番目のプロバイダーのエラーで次のプロバイダーを試すかを返す
+func (p *ChainProvider) shouldFallBack(ctx context.Context, i int, err error) bool {
+	return i+1 < len(p.providers) && ctx.Err() == nil && IsRetryable(err)
+}
+
+// chainError は試したプロバイダーのエラーをまとめる
+// 1つのプロバイダーしか試していない場合はそのエラーをそのまま返す
+func chainError(errs []error) error {
+	if len(errs) == 1 {
+		return errors.Unwrap(errs[0])
+	}
+	return fmt.Errorf("all providers in the chain failed: %w", errors.Join(errs...))
+}
@@ -0,0 +1,124 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// chainStubProvider は固定の結果またはエラーを返すテスト用プロバイダー
+type chainStubProvider struct {
+	name  string
+	err   error
+	calls int
+}
+
+func (p *chainStubProvider) Verify(ctx context.Context, specContent string, codeContents map[string]string) (*VerificationResult, error) {
+	return p.VerifyWithOptions(ctx, specContent, codeContents, nil)
+}
+
+func (p *chainStubProvider) VerifyWithOptions(ctx context.Context, specContent string, codeContents map[string]string, opts *VerifyOptions) (*VerificationResult, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &VerificationResult{MatchPercentage: 70}, nil
+}
+
+func (p *chainStubProvider) ExtractEndpoints(ctx context.Context, opts *ExtractOptions, codeContent string) ([]EndpointResult, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return []EndpointResult{{Method: "GET", Path: "/" + p.name}}, nil
+}
+
+func (p *chainStubProvider) Name() string  { return p.name }
+func (p *chainStubProvider) Model() string { return p.name + "-model" }
+
+func TestChainProvider_FallsBackOnRetryableError(t *testing.T) {
+	overloaded := &APIError{Provider: "claude", StatusCode: 529, Kind: ErrorKindOverloaded, Message: "overloaded"}
+	claude := &chainStubProvider{name: "claude", err: overloaded}
+	openai := &chainStubProvider{name: "openai"}
+	ollama := &chainStubProvider{name: "ollama"}
+
+	p, err := NewChainProvider(claude, openai, ollama)
+	if err != nil {
+		t.Fatalf("NewChainProvider failed: %v", err)
+	}
+	if p.Name() != "claude" || p.Model() != "claude-model" {
+		t.Errorf("identity = %s/%s, want the first provider", p.Name(), p.Model())
+	}
+
+	result, err := p.Verify(context.Background(), "# spec", nil)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if result.Provider != "openai" || result.Model != "openai-model" || result.Fallback != "openai" {
+		t.Errorf("result = %+v, want the openai result marked as a fallback", result)
+	}
+	if claude.calls != 1 || openai.calls != 1 || ollama.calls != 0 {
+		t.Errorf("calls = %d/%d/%d, want 1/1/0", claude.calls, openai.calls, ollama.calls)
+	}
+
+	endpoints, err := p.ExtractEndpoints(context.Background(), nil, "code")
+	if err != nil || len(endpoints) != 1 || endpoints[0].Path != "/openai" {
+		t.Errorf("ExtractEndpoints = %+v, %v", endpoints, err)
+	}
+}
+
+func TestChainProvider_PrimarySucceeds(t *testing.T) {
+	claude := &chainStubProvider{name: "claude"}
+	openai := &chainStubProvider{name: "openai"}
+	p, _ := NewChainProvider(claude, openai)
+
+	result, err := p.VerifyWithOptions(context.Background(), "# spec", nil, &VerifyOptions{})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if result.Provider != "claude" || result.Fallback != "" {
+		t.Errorf("result = %+v, want the claude result without fallback", result)
+	}
+	if openai.calls != 0 {
+		t.Errorf("openai was called %d times, want 0", openai.calls)
+	}
+}
+
+func TestChainProvider_StopsOnNonRetryableError(t *testing.T) {
+	unauthorized := &APIError{Provider: "claude", StatusCode: 401, Kind: ErrorKindAuth, Message: "invalid x-api-key"}
+	claude := &chainStubProvider{name: "claude", err: unauthorized}
+	openai := &chainStubProvider{name: "openai"}
+	p, _ := NewChainProvider(claude, openai)
+
+	_, err := p.Verify(context.Background(), "# spec", nil)
+	if !errors.Is(err, unauthorized) {
+		t.Errorf("err = %v, want the primary error", err)
+	}
+	if openai.calls != 0 {
+		t.Errorf("openai was called %d times, want 0", openai.calls)
+	}
+}
+
+func TestChainProvider_AllFail(t *testing.T) {
+	claude := &chainStubProvider{name: "claude", err: &APIError{StatusCode: 529, Kind: ErrorKindOverloaded}}
+	openai := &chainStubProvider{name: "openai", err: &APIError{StatusCode: 429, Kind: ErrorKindRateLimit}}
+	p, _ := NewChainProvider(claude, openai)
+
+	_, err := p.Verify(context.Background(), "# spec", nil)
+	if err == nil {
+		t.Fatal("expected error but got nil")
+	}
+	if !strings.Contains(err.Error(), "claude:") || !strings.Contains(err.Error(), "openai:") {
+		t.Errorf("err = %v, want errors from every provider", err)
+	}
+	if !IsRetryable(err) {
+		t.Error("the combined error should remain retryable")
+	}
+}
+
+func TestNewChainProvider_Empty(t *testing.T) {
+	if _, err := NewChainProvider(); err == nil {
+		t.Error("expected error but got nil")
+	}
+}
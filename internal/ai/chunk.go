@@ -0,0 +1,320 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// verificationMaxTokens は検証（チャンクごとの根拠抽出を含む）のデフォルトの最大出力トークン数
+const verificationMaxTokens = 2000
+
+// minChunkTokens はチャンクに割り当てるコードの最小トークン数
+// SPECとプロンプトだけでコンテキストウィンドウがほぼ埋まる場合は分割しても検証できない
+const minChunkTokens = 500
+
+// defaultContextWindows は各プロバイダーのデフォルトモデルのコンテキストウィンドウ（入力+出力のトークン数）
+// ローカルモデルはサーバーの設定に依存するため含めない（設定ファイルの ai.context_windows で指定する）
+var defaultContextWindows = map[string]int{
+	claudeDefaultModel: 200_000,
+	openaiDefaultModel: 128_000,
+	geminiDefaultModel: 1_048_576,
+}
+
+// LookupContextWindow はモデルのコンテキストウィンドウのトークン数を返す
+// overrides（設定ファイルの ai.context_windows）を優先し、なければ組み込みの値を使用する
+func LookupContextWindow(model string, overrides map[string]int) (int, bool) {
+	if window, ok := overrides[model]; ok && window > 0 {
+		return window, true
+	}
+	window, ok := defaultContextWindows[model]
+	return window, ok
+}
+
+// contextWindowFor はモデルのコンテキストウィンドウを返す（不明な場合は0）
+func (c ProviderConfig) contextWindowFor(model string) int {
+	window, _ := LookupContextWindow(model, c.ContextWindows)
+	return window
+}
+
+// verifyInContext はSPECとコードを検証する
+// プロンプトがモデルのコンテキストウィンドウに収まらない場合は、コードをチャンクに分割して
+// チャンクごとに実装の根拠を抽出し（map）、集めた根拠から最終的な検証結果を求める（reduce）
+func verifyInContext(ctx context.Context, transport Transport, cfg ProviderConfig, specContent string, codeContents map[string]string, opts *VerifyOptions) (*VerificationResult, error) {
+	prompt, err := BuildVerificationPrompt(specContent, codeContents, opts)
+	if err != nil {
+		return nil, err
+	}
+	model := transport.Model()
+	window := cfg.contextWindowFor(model)
+	outputTokens := cfg.maxTokensOr(verificationMaxTokens)
+	chunks, err := planChunks(model, window, outputTokens, prompt, specContent, codeContents, opts)
+	if err != nil {
+		return nil, err
+	}
+	if chunks == nil {
+		return requestVerification(ctx, transport, cfg, prompt)
+	}
+
+	var evidence []ChunkEvidence
+	var usage Usage
+	retries := 0
+	schema := cfg.schemaFor(evidenceResponseSchema)
+	for i, chunk := range chunks {
+		evidencePrompt, err := buildEvidencePrompt(specContent, chunk, opts, i+1, len(chunks))
+		if err != nil {
+			return nil, err
+		}
+		// 途中のチャンクで失敗した場合も、それまでのチャンクの使用量を返す
+		resp, err := complete(ctx, transport, cfg, evidencePrompt, verificationMaxTokens, schema)
+		if err != nil {
+			return nil, withUsage(fmt.Errorf("chunk %d/%d: %w", i+1, len(chunks), err), usage)
+		}
+		usage.Add(resp.Usage)
+		retries += resp.Retries
+		found, err := decodeEvidence(resp)
+		if err != nil {
+			return nil, withUsage(fmt.Errorf("chunk %d/%d: %w", i+1, len(chunks), err), usage)
+		}
+		evidence = append(evidence, found...)
+	}
+
+	reducePrompt, err := buildReducePrompt(specContent, evidence, opts, len(chunks))
+	if err != nil {
+		return nil, withUsage(err, usage)
+	}
+	if EstimateTokens(reducePrompt)+outputTokens > window {
+		return nil, withUsage(fmt.Errorf("evidence collected from %d chunks does not fit in the context window of model %q (%d tokens)", len(chunks), model, window), usage)
+	}
+	result, err := requestVerification(ctx, transport, cfg, reducePrompt)
+	if err != nil {
+		return nil, withUsage(err, usage)
+	}
+	result.Chunks = len(chunks)
+	result.Retries += retries
+	if result.Usage != nil {
+		usage.Add(*result.Usage)
+	}
+	result.Usage = &usage
+	return result, nil
+}
+
+// planChunks は検証プロンプトがコンテキストウィンドウに収まらない場合に、コードをチャンクに分割する
+// コンテキストウィンドウが不明（0）な場合とプロンプトが収まる場合は nil を返す
+func planChunks(model string, window, outputTokens int, prompt, specContent string, codeContents map[string]string, opts *VerifyOptions) ([]map[string]string, error) {
+	if window == 0 || EstimateTokens(prompt)+outputTokens <= window {
+		return nil, nil
+	}
+	empty, err := buildEvidencePrompt(specContent, nil, opts, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+	budget := window - outputTokens - EstimateTokens(empty)
+	if budget < minChunkTokens {
+		return nil, fmt.Errorf("spec is too large for the context window of model %q (%d tokens); set ai.context_windows to adjust", model, window)
+	}
+	return splitCode(codeContents, budget), nil
+}
+
+// BuildChunkPrompts は検証プロンプトがモデルのコンテキストウィンドウに収まらない場合に、
+// チャンクごとに実装の根拠を抽出するプロンプトを構築する（収まる場合とコンテキストウィンドウが不明な場合は nil）
+// 最終的な検証のプロンプトは抽出した根拠から構築するため含まない
+// contextWindows と maxOutputTokens には設定ファイルの ai.context_windows と ai.max_output_tokens を渡す
+func BuildChunkPrompts(model string, contextWindows map[string]int, maxOutputTokens int, specContent string, codeContents map[string]string, opts *VerifyOptions) ([]string, error) {
+	cfg := ProviderConfig{ContextWindows: contextWindows, MaxOutputTokens: maxOutputTokens}
+	prompt, err := BuildVerificationPrompt(specContent, codeContents, opts)
+	if err != nil {
+		return nil, err
+	}
+	chunks, err := planChunks(model, cfg.contextWindowFor(model), cfg.maxTokensOr(verificationMaxTokens), prompt, specContent, codeContents, opts)
+	if err != nil || chunks == nil {
+		return nil, err
+	}
+
+	prompts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		if prompts[i], err = buildEvidencePrompt(specContent, chunk, opts, i+1, len(chunks)); err != nil {
+			return nil, err
+		}
+	}
+	return prompts, nil
+}
+
+// splitCode はコードをコードセクションのトークン数が budget 以下になるチャンクに分割する
+// ファイルはパス順に詰め、1ファイルで budget を超える場合は行単位で分割して「パス (1/3)」のように名前を付ける
+func splitCode(codeContents map[string]string, budget int) []map[string]string {
+	var chunks []map[string]string
+	current := map[string]string{}
+	used := 0
+	add := func(name, content string) {
+		tokens := EstimateTokens(buildCodeSection(map[string]string{name: content}))
+		if used+tokens > budget && len(current) > 0 {
+			chunks = append(chunks, current)
+			current = map[string]string{}
+			used = 0
+		}
+		current[name] = content
+		used += tokens
+	}
+
+	for _, filePath := range sortedPaths(codeContents) {
+		content := codeContents[filePath]
+		if EstimateTokens(buildCodeSection(map[string]string{filePath: content})) <= budget {
+			add(filePath, content)
+			continue
+		}
+		// 見出し（パスと分割番号）の分を除いた量で分割する
+		header := EstimateTokens(buildCodeSection(map[string]string{filePath + " (999/999)": ""}))
+		parts := splitText(content, max(budget-header, 1))
+		for i, part := range parts {
+			add(fmt.Sprintf("%s (%d/%d)", filePath, i+1, len(parts)), part)
+		}
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+// splitText はテキストを推定トークン数が budget 以下の部分に行単位で分割する
+// 1行で budget を超える場合はその行を途中で区切る
+func splitText(text string, budget int) []string {
+	var parts []string
+	var current strings.Builder
+	used := 0
+	flush := func() {
+		if current.Len() > 0 {
+			parts = append(parts, current.String())
+			current.Reset()
+			used = 0
+		}
+	}
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		tokens := EstimateTokens(line)
+		if used+tokens > budget {
+			flush()
+		}
+		for tokens > budget {
+			cut := cutAtTokens(line, budget)
+			parts = append(parts, line[:cut])
+			line = line[cut:]
+			tokens = EstimateTokens(line)
+		}
+		current.WriteString(line)
+		used += tokens
+	}
+	flush()
+	return parts
+}
+
+// cutAtTokens は text の先頭から推定トークン数が budget に収まる位置（バイト数）を返す
+// 少なくとも1文字は含める
+func cutAtTokens(text string, budget int) int {
+	var ascii, other int
+	end := 0
+	for end < len(text) {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+		if (ascii+3)/4+other > budget && end > 0 {
+			break
+		}
+		end += size
+	}
+	return end
+}
+
+// ChunkEvidence はチャンクから見つかったSPEC項目の実装の根拠
+type ChunkEvidence struct {
+	// SPECの項目
+	Item string `json:"item"`
+
+	// 根拠となるファイル
+	File string `json:"file"`
+
+	// 実装内容（SPECと異なる点があればその内容）
+	Detail string `json:"detail"`
+}
+
+// evidenceResponseSchema はチャンクごとの根拠抽出結果のスキーマ
+var evidenceResponseSchema = &ResponseSchema{
+	Name:        "report_evidence",
+	Description: "コードの断片から見つかったSPEC項目の実装の根拠を報告する",
+	Schema: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"evidence": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"item":   map[string]any{"type": "string"},
+						"file":   map[string]any{"type": "string"},
+						"detail": map[string]any{"type": "string"},
+					},
+					"required":             []string{"item", "file", "detail"},
+					"additionalProperties": false,
+				},
+			},
+		},
+		"required":             []string{"evidence"},
+		"additionalProperties": false,
+	},
+}
+
+// buildEvidencePrompt はチャンクごとに実装の根拠を抽出するプロンプトを構築する
+// opts.EvidenceTemplate が指定されていない場合は組み込みのテンプレートを使用する
+func buildEvidencePrompt(specContent string, chunk map[string]string, opts *VerifyOptions, index, total int) (string, error) {
+	tmpl := defaultEvidenceTemplate
+	if opts != nil && opts.EvidenceTemplate != nil {
+		tmpl = opts.EvidenceTemplate
+	}
+	return tmpl.render(ChunkPromptData{
+		VerifyPromptData: verifyPromptData(specContent, chunk, opts),
+		Index:            index,
+		Total:            total,
+	})
+}
+
+// buildReducePrompt はチャンクごとの根拠から最終的な検証結果を求めるプロンプトを構築する
+// opts.ReduceTemplate が指定されていない場合は組み込みのテンプレートを使用する
+func buildReducePrompt(specContent string, evidence []ChunkEvidence, opts *VerifyOptions, chunks int) (string, error) {
+	tmpl := defaultReduceTemplate
+	if opts != nil && opts.ReduceTemplate != nil {
+		tmpl = opts.ReduceTemplate
+	}
+	return tmpl.render(ChunkPromptData{
+		VerifyPromptData: verifyPromptData(specContent, nil, opts),
+		Total:            chunks,
+		Evidence:         evidence,
+	})
+}
+
+// decodeEvidence はAPIレスポンスから根拠の一覧を取り出す
+func decodeEvidence(resp *Completion) ([]ChunkEvidence, error) {
+	text := resp.Text
+	if !resp.Structured {
+		if matches := regexp.MustCompile("```json\\s*([\\s\\S]*?)\\s*```").FindStringSubmatch(text); len(matches) >= 2 {
+			text = matches[1]
+		}
+	}
+
+	var parsed struct {
+		Evidence []ChunkEvidence `json:"evidence"`
+	}
+	err := json.Unmarshal([]byte(text), &parsed)
+	if err != nil {
+		repaired, ok := repairJSON(text, "{")
+		if !ok || json.Unmarshal([]byte(repaired), &parsed) != nil {
+			return nil, fmt.Errorf("failed to parse evidence result: %w", err)
+		}
+	}
+	return parsed.Evidence, nil
+}
@@ -0,0 +1,235 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLookupContextWindow(t *testing.T) {
+	if window, ok := LookupContextWindow(claudeDefaultModel, nil); !ok || window != 200_000 {
+		t.Errorf("claude default = %d, %v", window, ok)
+	}
+	if window, ok := LookupContextWindow(claudeDefaultModel, map[string]int{claudeDefaultModel: 50_000}); !ok || window != 50_000 {
+		t.Errorf("override = %d, %v", window, ok)
+	}
+	if _, ok := LookupContextWindow("unknown-model", nil); ok {
+		t.Error("expected unknown model to have no context window")
+	}
+}
+
+func TestSplitCode(t *testing.T) {
+	code := map[string]string{
+		"a.ts":     strings.Repeat("const a = 1;\n", 20),
+		"b.ts":     "export const b = 2;\n",
+		"large.ts": strings.Repeat("export function handler() { return 'x'; }\n", 100),
+	}
+	budget := 300
+
+	chunks := splitCode(code, budget)
+	if len(chunks) < 2 {
+		t.Fatalf("chunks = %d, want at least 2", len(chunks))
+	}
+
+	rebuilt := map[string]string{}
+	for _, chunk := range chunks {
+		if tokens := EstimateTokens(buildCodeSection(chunk)); tokens > budget {
+			t.Errorf("chunk has %d tokens, want <= %d", tokens, budget)
+		}
+		for name, content := range chunk {
+			path, _, _ := strings.Cut(name, " (")
+			rebuilt[path] += content
+		}
+	}
+	for path, content := range code {
+		if rebuilt[path] != content {
+			t.Errorf("%s was not preserved by splitting", path)
+		}
+	}
+}
+
+func TestSplitText_LongLine(t *testing.T) {
+	text := strings.Repeat("あ", 50)
+	parts := splitText(text, 20)
+	if len(parts) != 3 || strings.Join(parts, "") != text {
+		t.Errorf("parts = %q", parts)
+	}
+}
+
+// chunkedOllamaServer はチャンクごとの根拠抽出と最終評価に応答するOllama互換のテスト用サーバー
+func chunkedOllamaServer(t *testing.T, prompts *[]string, numCtx *float64) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+			Options map[string]any `json:"options"`
+		}
+		json.Unmarshal(body, &req)
+		prompt := req.Messages[0].Content
+
+		mu.Lock()
+		*prompts = append(*prompts, prompt)
+		*numCtx, _ = req.Options["num_ctx"].(float64)
+		mu.Unlock()
+
+		var content string
+		switch {
+		case strings.Contains(prompt, "## コードの断片"):
+			var evidence []ChunkEvidence
+			if strings.Contains(prompt, "### users.ts") {
+				evidence = append(evidence, ChunkEvidence{Item: "ユーザー一覧を返す", File: "users.ts", Detail: "GET /users"})
+			}
+			encoded, _ := json.Marshal(map[string]any{"evidence": evidence})
+			content = string(encoded)
+		case strings.Contains(prompt, "- ユーザー一覧を返す [users.ts]: GET /users"):
+			content = `{"matchPercentage":50,"matchedItems":["ユーザー一覧を返す"],"unmatchedItems":["注文を作成する"],"notes":""}`
+		default:
+			content = `{"matchPercentage":0,"matchedItems":[],"unmatchedItems":[],"notes":"unexpected prompt"}`
+		}
+		encoded, _ := json.Marshal(content)
+		fmt.Fprintf(w, `{"message":{"role":"assistant","content":%s},"done":true,"prompt_eval_count":100,"eval_count":10}`, encoded)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestVerifyInContext_MapReduce(t *testing.T) {
+	var prompts []string
+	var numCtx float64
+	server := chunkedOllamaServer(t, &prompts, &numCtx)
+
+	code := map[string]string{
+		"orders.ts": strings.Repeat("// 注文の処理\n", 400),
+		"users.ts":  strings.Repeat("router.get('/users', list);\n", 200),
+	}
+	spec := "# API\n\n- ユーザー一覧を返す\n- 注文を作成する\n"
+
+	p, _ := NewOllamaProvider(server.URL, WithContextWindows(map[string]int{ollamaDefaultModel: 4000}))
+	result, err := p.Verify(context.Background(), spec, code)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if result.Chunks < 2 || len(prompts) != result.Chunks+1 {
+		t.Fatalf("chunks = %d, requests = %d", result.Chunks, len(prompts))
+	}
+	if result.MatchPercentage != 50 {
+		t.Errorf("result = %+v", result)
+	}
+	if result.Usage == nil || result.Usage.InputTokens != 100*len(prompts) {
+		t.Errorf("usage = %+v, want the total of all requests", result.Usage)
+	}
+	if numCtx != 4000 {
+		t.Errorf("num_ctx = %v, want 4000", numCtx)
+	}
+	for _, prompt := range prompts {
+		if EstimateTokens(prompt)+verificationMaxTokens > 4000 {
+			t.Errorf("prompt has %d tokens, which does not fit in the context window", EstimateTokens(prompt))
+		}
+	}
+}
+
+func TestVerifyInContext_FitsInWindow(t *testing.T) {
+	var prompts []string
+	var numCtx float64
+	server := chunkedOllamaServer(t, &prompts, &numCtx)
+
+	// コンテキストウィンドウが不明なモデルは分割しない
+	p, _ := NewOllamaProvider(server.URL)
+	result, err := p.Verify(context.Background(), "# spec", map[string]string{"users.ts": strings.Repeat("x\n", 5000)})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if result.Chunks != 0 || len(prompts) != 1 || numCtx != 0 {
+		t.Errorf("chunks = %d, requests = %d, num_ctx = %v", result.Chunks, len(prompts), numCtx)
+	}
+}
+
+func TestVerifyInContext_SpecTooLarge(t *testing.T) {
+	var prompts []string
+	var numCtx float64
+	server := chunkedOllamaServer(t, &prompts, &numCtx)
+
+	p, _ := NewOllamaProvider(server.URL, WithContextWindows(map[string]int{ollamaDefaultModel: 3000}))
+	spec := strings.Repeat("- 項目\n", 500)
+	_, err := p.Verify(context.Background(), spec, map[string]string{"a.ts": strings.Repeat("x\n", 2000)})
+	if err == nil || !strings.Contains(err.Error(), "too large for the context window") {
+		t.Errorf("err = %v", err)
+	}
+	if len(prompts) != 0 {
+		t.Errorf("sent %d requests, want 0", len(prompts))
+	}
+}
+
+func TestChunkPrompts_Language(t *testing.T) {
+	english := &VerifyOptions{Language: "English"}
+	if prompt, _ := buildEvidencePrompt("# spec", nil, nil, 1, 2); strings.Contains(prompt, "で記述してください") {
+		t.Error("the evidence prompt should not include a language instruction by default")
+	}
+	if prompt, _ := buildEvidencePrompt("# spec", nil, english, 1, 2); !strings.Contains(prompt, "detail の内容はEnglishで記述してください。") {
+		t.Error("the evidence prompt should instruct the answer language")
+	}
+	if prompt, _ := buildReducePrompt("# spec", nil, english, 2); !strings.Contains(prompt, "notes の内容はEnglishで記述してください。") {
+		t.Error("the reduce prompt should instruct the answer language")
+	}
+}
+
+func TestChunkPrompts_Template(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, text string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	evidence, err := LoadEvidenceTemplate(write("evidence.tmpl", "{{.SpecType}} {{.Index}}/{{.Total}}{{range .Files}} {{.Path}}{{end}}"))
+	if err != nil {
+		t.Fatalf("LoadEvidenceTemplate failed: %v", err)
+	}
+	reduce, err := LoadReduceTemplate(write("reduce.tmpl", "{{.Title}}{{range .Evidence}} {{.Item}}@{{.File}}{{end}}"))
+	if err != nil {
+		t.Fatalf("LoadReduceTemplate failed: %v", err)
+	}
+	if _, err := LoadReduceTemplate(write("broken.tmpl", "{{.Unknown}}")); err == nil {
+		t.Error("expected error for a template that references an unknown field")
+	}
+
+	opts := &VerifyOptions{Title: "API", SpecType: "api", EvidenceTemplate: evidence, ReduceTemplate: reduce}
+	if got, _ := buildEvidencePrompt("# spec", map[string]string{"b.ts": "", "a.ts": ""}, opts, 2, 3); got != "api 2/3 a.ts b.ts" {
+		t.Errorf("evidence prompt = %q", got)
+	}
+	if got, _ := buildReducePrompt("# spec", []ChunkEvidence{{Item: "一覧", File: "a.ts"}}, opts, 3); got != "API 一覧@a.ts" {
+		t.Errorf("reduce prompt = %q", got)
+	}
+}
+
+func TestBuildChunkPrompts(t *testing.T) {
+	code := map[string]string{
+		"orders.ts": strings.Repeat("// 注文の処理\n", 400),
+		"users.ts":  strings.Repeat("router.get('/users', list);\n", 200),
+	}
+	prompts, err := BuildChunkPrompts("local-model", map[string]int{"local-model": 4000}, 0, "# API", code, nil)
+	if err != nil {
+		t.Fatalf("BuildChunkPrompts failed: %v", err)
+	}
+	if len(prompts) < 2 || !strings.Contains(prompts[0], fmt.Sprintf("## コードの断片 (1/%d)", len(prompts))) {
+		t.Errorf("prompts = %d, first = %.200q", len(prompts), prompts[0])
+	}
+
+	// コンテキストウィンドウに収まる場合は分割しない
+	if prompts, err := BuildChunkPrompts("local-model", nil, 0, "# API", code, nil); err != nil || prompts != nil {
+		t.Errorf("prompts = %d, err = %v; want nil", len(prompts), err)
+	}
+}
@@ -5,42 +5,73 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"regexp"
 	"strings"
 )
 
-const claudeAPIURL = "https://api.anthropic.com/v1/messages"
+const (
+	claudeDefaultBaseURL = "https://api.anthropic.com"
+	claudeDefaultModel   = "claude-sonnet-4-20250514"
+)
 
-// ClaudeProvider はClaude APIを使用したプロバイダー
-type ClaudeProvider struct {
+// ClaudeTransport はClaude Messages APIのワイヤーフォーマットを実装した Transport
+type ClaudeTransport struct {
+	apiURL string
 	apiKey string
 	model  string
+	config ProviderConfig
+	client *http.Client
+}
+
+// NewClaudeProvider はClaude APIを使用したプロバイダーを作成する
+func NewClaudeProvider(apiKey string, opts ...ProviderOption) (*TransportProvider, error) {
+	transport, err := NewClaudeTransport(apiKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewTransportProvider(transport, opts...), nil
 }
 
-// NewClaudeProvider は新しいClaudeProviderを作成する
-func NewClaudeProvider(apiKey string) (*ClaudeProvider, error) {
+// NewClaudeTransport は新しいClaudeTransportを作成する
+func NewClaudeTransport(apiKey string, opts ...ProviderOption) (*ClaudeTransport, error) {
 	if apiKey == "" {
 		return nil, fmt.Errorf("API key is required")
 	}
 
-	return &ClaudeProvider{
+	cfg := newProviderConfig(opts)
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = claudeDefaultBaseURL
+	}
+
+	return &ClaudeTransport{
+		apiURL: strings.TrimSuffix(baseURL, "/") + "/v1/messages",
 		apiKey: apiKey,
-		model:  "claude-sonnet-4-20250514",
+		model:  cfg.modelOr(claudeDefaultModel),
+		config: cfg,
+		client: cfg.httpClient(),
 	}, nil
 }
 
 // Name はプロバイダー名を返す
-func (p *ClaudeProvider) Name() string {
+func (p *ClaudeTransport) Name() string {
 	return "claude"
 }
 
+// Model は使用しているモデル名を返す
+func (p *ClaudeTransport) Model() string {
+	return p.model
+}
+
 // claudeRequest はClaude APIへのリクエスト
 type claudeRequest struct {
-	Model     string          `json:"model"`
-	MaxTokens int             `json:"max_tokens"`
-	Messages  []claudeMessage `json:"messages"`
+	Model       string          `json:"model"`
+	MaxTokens   int             `json:"max_tokens"`
+	Temperature *float64        `json:"temperature,omitempty"`
+	Messages    []claudeMessage `json:"messages"`
+	Tools       []claudeTool    `json:"tools,omitempty"`
+	ToolChoice  *claudeToolPick `json:"tool_choice,omitempty"`
 }
 
 type claudeMessage struct {
@@ -48,102 +79,37 @@ type claudeMessage struct {
 	Content string `json:"content"`
 }
 
+// claudeTool は構造化出力に使用するツール定義
+type claudeTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+// claudeToolPick は使用するツールの指定
+type claudeToolPick struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
 // claudeResponse はClaude APIからのレスポンス
 type claudeResponse struct {
 	Content []struct {
-		Type string `json:"type"`
-		Text string `json:"text"`
+		Type  string          `json:"type"`
+		Text  string          `json:"text"`
+		Name  string          `json:"name,omitempty"`
+		Input json.RawMessage `json:"input,omitempty"`
 	} `json:"content"`
+	Usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
 	Error *struct {
 		Type    string `json:"type"`
 		Message string `json:"message"`
 	} `json:"error,omitempty"`
 }
 
-// Verify はSPECとコードの一致度を検証する
-func (p *ClaudeProvider) Verify(ctx context.Context, specContent string, codeContents map[string]string) (*VerificationResult, error) {
-	return p.VerifyWithOptions(ctx, specContent, codeContents, nil)
-}
-
-// VerifyWithOptions は検証観点を指定してSPECとコードの一致度を検証する
-func (p *ClaudeProvider) VerifyWithOptions(ctx context.Context, specContent string, codeContents map[string]string, opts *VerifyOptions) (*VerificationResult, error) {
-	var prompt string
-	if opts != nil && len(opts.VerificationFocus) > 0 {
-		prompt = buildVerificationPromptWithFocus(specContent, codeContents, opts.VerificationFocus)
-	} else {
-		prompt = buildVerificationPrompt(specContent, codeContents)
-	}
-
-	text, err := p.callAPI(ctx, prompt, 2000)
-	if err != nil {
-		return nil, err
-	}
-
-	return parseVerificationResult(text)
-}
-
-// buildCodeSection はコードセクションを構築する共通関数
-func buildCodeSection(codeContents map[string]string) string {
-	var codeSection strings.Builder
-	for filePath, content := range codeContents {
-		codeSection.WriteString(fmt.Sprintf("\n### %s\n```\n%s\n```\n", filePath, content))
-	}
-	return codeSection.String()
-}
-
-// getDefaultVerificationFocus はデフォルトの検証観点を返す
-func getDefaultVerificationFocus() []string {
-	return []string{
-		"画面構成: SPECに記載された要素がコードに存在するか",
-		"状態管理: SPECに記載された状態やフックが使用されているか",
-		"処理フロー: SPECに記載された処理フローがコードで実装されているか",
-		"バリデーション: SPECに記載されたバリデーションルールが実装されているか",
-		"エラーハンドリング: SPECに記載されたエラーケースが処理されているか",
-	}
-}
-
-// buildVerificationPrompt は検証用のプロンプトを構築する
-// デフォルトの検証観点を使用してbuildVerificationPromptWithFocusを呼び出す
-func buildVerificationPrompt(specContent string, codeContents map[string]string) string {
-	return buildVerificationPromptWithFocus(specContent, codeContents, getDefaultVerificationFocus())
-}
-
-// buildVerificationPromptWithFocus はカスタム検証観点を含むプロンプトを構築する
-func buildVerificationPromptWithFocus(specContent string, codeContents map[string]string, verificationFocus []string) string {
-	codeSection := buildCodeSection(codeContents)
-
-	// 検証観点をフォーマット
-	var focusSection strings.Builder
-	for i, focus := range verificationFocus {
-		focusSection.WriteString(fmt.Sprintf("%d. %s\n", i+1, focus))
-	}
-
-	return fmt.Sprintf(`あなたはコードレビューの専門家です。以下のSPEC(仕様書)と実際のコードを比較して、一致度を評価してください。
-
-## SPEC(仕様書)
-%s
-
-## 実際のコード
-%s
-
-## 評価基準
-以下の観点で重点的に評価してください:
-%s
-
-## 出力形式
-以下のJSON形式で出力してください:
-%sjson
-{
-  "matchPercentage": <0-100の数値>,
-  "matchedItems": ["一致している項目1", "一致している項目2", ...],
-  "unmatchedItems": ["一致していない項目1", "一致していない項目2", ...],
-  "notes": "補足コメント(未実装の機能や改善点など)"
-}
-%s
-
-JSONのみを出力してください。`, specContent, codeSection, focusSection.String(), "```", "```")
-}
-
 // parseVerificationResult はClaude APIのレスポンスから検証結果を抽出する
 func parseVerificationResult(text string) (*VerificationResult, error) {
 	// JSONブロックを抽出
@@ -159,136 +125,88 @@ func parseVerificationResult(text string) (*VerificationResult, error) {
 	}
 
 	var result VerificationResult
-	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse verification result: %w", err)
-	}
-
-	return &result, nil
-}
-
-// ExtractEndpoints はコードからAPIエンドポイント/ページルートを抽出する
-func (p *ClaudeProvider) ExtractEndpoints(ctx context.Context, opts *ExtractOptions, codeContent string) ([]EndpointResult, error) {
-	var prompt string
-	if opts.IsUICategory() {
-		prompt = buildUIRouteExtractionPrompt(opts.GetSourceType(), codeContent)
-	} else {
-		prompt = buildEndpointExtractionPrompt(opts.GetSourceType(), codeContent)
+	err := json.Unmarshal([]byte(jsonStr), &result)
+	if err == nil {
+		return &result, nil
 	}
 
-	text, err := p.callAPI(ctx, prompt, 4000)
-	if err != nil {
-		return nil, err
+	// 途中で切れた・不正なJSONは修復して再解析する
+	if repaired, ok := repairJSON(jsonStr, "{"); ok {
+		if fixed, rerr := unmarshalVerificationResult(repaired); rerr == nil {
+			fixed.Repair = RepairFixed
+			return fixed, nil
+		}
 	}
 
-	return parseEndpointResult(text)
+	return nil, fmt.Errorf("failed to parse verification result: %w", err)
 }
 
-// callAPI はClaude APIを呼び出す共通関数
-func (p *ClaudeProvider) callAPI(ctx context.Context, prompt string, maxTokens int) (string, error) {
+// Complete はClaude APIにプロンプトを送信する
+func (p *ClaudeTransport) Complete(ctx context.Context, request *CompletionRequest) (*Completion, error) {
 	req := claudeRequest{
-		Model:     p.model,
-		MaxTokens: maxTokens,
+		Model:       p.model,
+		MaxTokens:   request.MaxTokens,
+		Temperature: p.config.Temperature,
 		Messages: []claudeMessage{
-			{Role: "user", Content: prompt},
+			{Role: "user", Content: request.Prompt},
 		},
 	}
-
-	reqBody, err := json.Marshal(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", claudeAPIURL, bytes.NewReader(reqBody))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+	// 構造化出力はツールの強制使用で実現する（ツールの入力がスキーマに沿ったJSONになる）
+	if request.Schema != nil {
+		req.Tools = []claudeTool{
+			{Name: request.Schema.Name, Description: request.Schema.Description, InputSchema: request.Schema.Schema},
+		}
+		req.ToolChoice = &claudeToolPick{Type: "tool", Name: request.Schema.Name}
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("x-api-key", p.apiKey)
-	httpReq.Header.Set("anthropic-version", "2023-06-01")
-
-	client := &http.Client{}
-	resp, err := client.Do(httpReq)
+	reqBody, err := json.Marshal(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	body, retries, err := sendWithRetry(ctx, p.client, p.config.Retry, p.config.RateLimiter, EstimateTokens(request.Prompt), p.Name(), func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", p.apiURL, bytes.NewReader(reqBody))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("x-api-key", p.apiKey)
+		httpReq.Header.Set("anthropic-version", "2023-06-01")
+		return httpReq, nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, err
 	}
 
 	var claudeResp claudeResponse
 	if err := json.Unmarshal(body, &claudeResp); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	if claudeResp.Error != nil {
-		return "", fmt.Errorf("API error: %s", claudeResp.Error.Message)
+		return nil, fmt.Errorf("API error: %s", claudeResp.Error.Message)
 	}
 
 	if len(claudeResp.Content) == 0 {
-		return "", fmt.Errorf("empty response from API")
+		return nil, fmt.Errorf("empty response from API")
 	}
 
-	return claudeResp.Content[0].Text, nil
-}
-
-// buildEndpointExtractionPrompt はエンドポイント抽出用のプロンプトを構築する
-func buildEndpointExtractionPrompt(sourceType string, codeContent string) string {
-	frameworkHint := ""
-	switch sourceType {
-	case "express":
-		frameworkHint = "Express.js (app.get, app.post, router.get, router.post など)"
-	case "fastify":
-		frameworkHint = "Fastify (fastify.get, fastify.post など)"
-	case "go-echo":
-		frameworkHint = "Go Echo (e.GET, e.POST, g.GET など)"
-	case "go-gin":
-		frameworkHint = "Go Gin (r.GET, r.POST, group.GET など)"
-	case "rails":
-		frameworkHint = "Ruby on Rails (routes.rb, get/post/resources など)"
-	case "django":
-		frameworkHint = "Django REST Framework (path, urlpatterns など)"
-	case "graphql":
-		frameworkHint = "GraphQL (Query, Mutation, type定義)"
-	default:
-		frameworkHint = "自動検出"
+	usage := Usage{InputTokens: claudeResp.Usage.InputTokens, OutputTokens: claudeResp.Usage.OutputTokens}
+	if request.Schema != nil {
+		for _, block := range claudeResp.Content {
+			if block.Type == "tool_use" && len(block.Input) > 0 {
+				return &Completion{Text: string(block.Input), Retries: retries, Structured: true, Usage: usage}, nil
+			}
+		}
 	}
 
-	return fmt.Sprintf(`あなたはAPIエンドポイント抽出の専門家です。
-以下のコードからAPIエンドポイントを抽出してください。
-
-## フレームワーク/タイプ
-%s
-
-## コード
-%s
-
-## 抽出ルール
-1. 明確に定義されているエンドポイントのみを抽出してください
-2. 推測はしないでください
-3. GraphQLの場合は、QueryとMutationを抽出し、methodは "QUERY" または "MUTATION" としてください
-
-## 出力形式
-以下のJSON配列形式で出力してください:
-%sjson
-[
-  {
-    "method": "GET",
-    "path": "/api/users",
-    "file": "ファイル名(分かれば)",
-    "description": "簡単な説明(あれば)"
-  }
-]
-%s
-
-JSONのみを出力してください。エンドポイントが見つからない場合は空の配列 [] を返してください。`, frameworkHint, codeContent, "```", "```")
+	var text strings.Builder
+	for _, block := range claudeResp.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	return &Completion{Text: text.String(), Retries: retries, Usage: usage}, nil
 }
 
 // parseEndpointResult はClaude APIのレスポンスからエンドポイント結果を抽出する
@@ -312,71 +230,17 @@ func parseEndpointResult(text string) ([]EndpointResult, error) {
 		}
 	}
 
-	var results []EndpointResult
-	if err := json.Unmarshal([]byte(jsonStr), &results); err != nil {
-		return nil, fmt.Errorf("failed to parse endpoint result: %w", err)
+	results, err := unmarshalEndpointResult(jsonStr)
+	if err == nil {
+		return results, nil
 	}
 
-	return results, nil
-}
-
-// buildUIRouteExtractionPrompt はUIページルート抽出用のプロンプトを構築する
-func buildUIRouteExtractionPrompt(sourceType string, codeContent string) string {
-	frameworkHint := ""
-	switch sourceType {
-	case "remix":
-		frameworkHint = "Remix (ファイルベースルーティング)"
-	case "nextjs":
-		frameworkHint = "Next.js (pages または app ディレクトリルーティング)"
-	case "react-router":
-		frameworkHint = "React Router (createBrowserRouter, Route コンポーネント)"
-	case "vue-router":
-		frameworkHint = "Vue Router (routes 配列)"
-	default:
-		frameworkHint = "自動検出（ファイルベースルーティングまたはルーター設定から検出）"
+	// 途中で切れた・不正なJSONは修復して再解析する
+	if repaired, ok := repairJSON(text, "[{"); ok {
+		if fixed, rerr := unmarshalEndpointResult(repaired); rerr == nil {
+			return markRepaired(fixed, RepairFixed), nil
+		}
 	}
 
-	return fmt.Sprintf(`あなたはフロントエンドルート抽出の専門家です。
-以下のコードからページルート（画面パス）を抽出してください。
-
-## フレームワーク/タイプ
-%s
-
-## コード
-%s
-
-## 抽出ルール
-1. ファイルベースルーティングの場合:
-   - ファイル名からルートパスを推測してください
-   - 例: "src/client/routes/users.tsx" → "/users"
-   - 例: "src/client/routes/admin/settings.tsx" → "/admin/settings"
-   - 例: "app/routes/_index.tsx" → "/"
-   - 例: "app/routes/users.$id.tsx" → "/users/:id"
-   - "_" で始まるファイルはレイアウトまたはインデックスを示す場合があります
-
-2. ルーター設定ファイルの場合:
-   - path プロパティや Route コンポーネントの path 属性を抽出してください
-
-3. 明確に定義されているルートのみを抽出してください
-4. 推測は最小限にしてください
-5. コンポーネントファイル（ページではない部品）は含めないでください
-
-## 出力形式
-以下のJSON配列形式で出力してください:
-%sjson
-[
-  {
-    "method": "PAGE",
-    "path": "/users",
-    "file": "ファイル名",
-    "description": "画面の説明(あれば)"
-  }
-]
-%s
-
-注意:
-- methodは常に "PAGE" としてください
-- ルートが見つからない場合は空の配列 [] を返してください
-
-JSONのみを出力してください。`, frameworkHint, codeContent, "```", "```")
+	return nil, fmt.Errorf("failed to parse endpoint result: %w", err)
 }
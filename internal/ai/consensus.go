@@ -0,0 +1,226 @@
+package ai
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// MemberResult はアンサンブルの各メンバー（プロバイダー/モデル）の結果
+type MemberResult struct {
+	// プロバイダー名
+	Provider string `json:"provider"`
+
+	// モデル名
+	Model string `json:"model"`
+
+	// 一致度（エラーの場合は0）
+	MatchPercentage int `json:"matchPercentage"`
+
+	// エラー（検証に失敗した場合）
+	Error string `json:"error,omitempty"`
+
+	// APIが返したトークン使用量、検証にかかった時間（ミリ秒）、API呼び出しの回数
+	Usage     *Usage `json:"usage,omitempty"`
+	LatencyMs int64  `json:"latencyMs,omitempty"`
+	Attempts  int    `json:"attempts,omitempty"`
+}
+
+// ItemVote は項目と、その項目を挙げたメンバー数
+type ItemVote struct {
+	Item  string `json:"item"`
+	Votes int    `json:"votes"`
+}
+
+// Consensus は複数の検証結果を統合した際の内訳
+type Consensus struct {
+	// 各メンバーの結果
+	Members []MemberResult `json:"members"`
+
+	// 一致度の最大値と最小値の差（不一致の指標）
+	Spread int `json:"spread"`
+
+	// 一致度の標準偏差
+	StdDev float64 `json:"stdDev"`
+
+	// 一致項目の和集合と得票数（得票数の多い順）
+	MatchedVotes []ItemVote `json:"matchedVotes"`
+
+	// 不一致項目の和集合と得票数（得票数の多い順）
+	UnmatchedVotes []ItemVote `json:"unmatchedVotes"`
+
+	// 全メンバーが一致項目に挙げた項目（積集合）
+	AgreedMatched []string `json:"agreedMatched"`
+
+	// 全メンバーが不一致項目に挙げた項目（積集合）
+	AgreedUnmatched []string `json:"agreedUnmatched"`
+
+	// メンバー間の不一致が閾値を超えており、人によるレビューが必要か
+	NeedsReview bool `json:"needsReview"`
+}
+
+// CombineResults は複数の検証結果を1つに統合する
+// 一致度は中央値、一致/不一致項目は過半数のメンバーが挙げたものを採用する
+// members と results は同じ順序で、失敗したメンバーの results は nil とする
+// 失敗したメンバーの members には失敗するまでに消費した使用量を設定しておくと合計に含める
+// 一致度の差が reviewThreshold を超える場合（0以下の場合は判定しない）と、失敗したメンバーがある場合は NeedsReview を立てる
+func CombineResults(members []MemberResult, results []*VerificationResult, reviewThreshold int) (*VerificationResult, error) {
+	var succeeded []*VerificationResult
+	var scores []int
+	for i, r := range results {
+		if r == nil {
+			continue
+		}
+		members[i].MatchPercentage = r.MatchPercentage
+		members[i].Usage = r.Usage
+		members[i].LatencyMs = r.LatencyMs
+		members[i].Attempts = r.Attempts
+		succeeded = append(succeeded, r)
+		scores = append(scores, r.MatchPercentage)
+	}
+	if len(succeeded) == 0 {
+		var errs []string
+		for _, m := range members {
+			errs = append(errs, fmt.Sprintf("%s/%s: %s", m.Provider, m.Model, m.Error))
+		}
+		return nil, fmt.Errorf("all ensemble members failed: %s", strings.Join(errs, "; "))
+	}
+
+	n := len(succeeded)
+	matchedVotes := countVotes(succeeded, func(r *VerificationResult) []string { return r.MatchedItems })
+	unmatchedVotes := countVotes(succeeded, func(r *VerificationResult) []string { return r.UnmatchedItems })
+
+	consensus := &Consensus{
+		Members:         members,
+		Spread:          spread(scores),
+		StdDev:          stdDev(scores),
+		MatchedVotes:    matchedVotes,
+		UnmatchedVotes:  unmatchedVotes,
+		AgreedMatched:   itemsWithVotes(matchedVotes, n),
+		AgreedUnmatched: itemsWithVotes(unmatchedVotes, n),
+	}
+	// 一部のメンバーが失敗した場合は、残りのメンバーだけの結果のため要レビューとする
+	consensus.NeedsReview = (reviewThreshold > 0 && consensus.Spread > reviewThreshold) || n < len(members)
+
+	combined := &VerificationResult{
+		MatchPercentage: median(scores),
+		MatchedItems:    itemsWithVotes(matchedVotes, n/2+1),
+		UnmatchedItems:  itemsWithVotes(unmatchedVotes, n/2+1),
+		Consensus:       consensus,
+	}
+
+	var notes []string
+	var usage Usage
+	hasUsage := false
+	for i, r := range results {
+		if r == nil {
+			// 失敗したメンバーも失敗するまでに消費した使用量は合計に含める
+			if m := members[i]; m.Usage != nil {
+				usage.Add(*m.Usage)
+				hasUsage = true
+				combined.Attempts += m.Attempts
+				combined.LatencyMs = max(combined.LatencyMs, m.LatencyMs)
+			}
+			continue
+		}
+		if r.Notes != "" {
+			notes = append(notes, fmt.Sprintf("[%s/%s] %s", members[i].Provider, members[i].Model, r.Notes))
+		}
+		combined.Retries += r.Retries
+		combined.Attempts += r.Attempts
+		// 代替プロバイダーの結果を含む場合は統合結果も代替として扱う（キャッシュに保存しない）
+		if combined.Fallback == "" {
+			combined.Fallback = r.Fallback
+		}
+		// メンバーは並列に呼び出すため、最も遅いメンバーの時間を全体の時間とする
+		combined.LatencyMs = max(combined.LatencyMs, r.LatencyMs)
+		if r.Usage != nil {
+			usage.Add(*r.Usage)
+			hasUsage = true
+		}
+	}
+	combined.Notes = strings.Join(notes, "\n")
+	if hasUsage {
+		combined.Usage = &usage
+	}
+	return combined, nil
+}
+
+// countVotes は各結果が挙げた項目を集計する
+// 表記揺れ（大文字小文字、空白）は同じ項目として数える。表示には最初に現れた表記を使用する
+func countVotes(results []*VerificationResult, items func(*VerificationResult) []string) []ItemVote {
+	var votes []ItemVote
+	index := make(map[string]int)
+	for _, r := range results {
+		seen := make(map[string]bool)
+		for _, item := range items(r) {
+			key := normalizeItem(item)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			if i, ok := index[key]; ok {
+				votes[i].Votes++
+				continue
+			}
+			index[key] = len(votes)
+			votes = append(votes, ItemVote{Item: strings.TrimSpace(item), Votes: 1})
+		}
+	}
+	sort.SliceStable(votes, func(i, j int) bool {
+		return votes[i].Votes > votes[j].Votes
+	})
+	return votes
+}
+
+// normalizeItem は項目の比較用に表記を正規化する
+func normalizeItem(item string) string {
+	return strings.ToLower(strings.Join(strings.Fields(item), " "))
+}
+
+// itemsWithVotes は得票数が minVotes 以上の項目を返す
+func itemsWithVotes(votes []ItemVote, minVotes int) []string {
+	items := []string{}
+	for _, v := range votes {
+		if v.Votes >= minVotes {
+			items = append(items, v.Item)
+		}
+	}
+	return items
+}
+
+// median は中央値を返す（要素数が偶数の場合は中央2つの平均を四捨五入）
+func median(values []int) int {
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return int(math.Round(float64(sorted[mid-1]+sorted[mid]) / 2))
+}
+
+// spread は最大値と最小値の差を返す
+func spread(values []int) int {
+	lo, hi := values[0], values[0]
+	for _, v := range values[1:] {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+	return hi - lo
+}
+
+// stdDev は母標準偏差を返す
+func stdDev(values []int) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += float64(v)
+	}
+	mean := sum / float64(len(values))
+	var sq float64
+	for _, v := range values {
+		sq += (float64(v) - mean) * (float64(v) - mean)
+	}
+	return math.Sqrt(sq / float64(len(values)))
+}
@@ -0,0 +1,108 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCombineResults(t *testing.T) {
+	members := []MemberResult{
+		{Provider: "claude", Model: "a"},
+		{Provider: "openai", Model: "b"},
+		{Provider: "ollama", Model: "c"},
+	}
+	results := []*VerificationResult{
+		{MatchPercentage: 90, MatchedItems: []string{"ログイン", "ログアウト"}, UnmatchedItems: []string{"パスワード再設定"}, Usage: &Usage{InputTokens: 100, OutputTokens: 10}},
+		{MatchPercentage: 80, MatchedItems: []string{"ログイン", " ログアウト "}, UnmatchedItems: []string{}, Retries: 1},
+		{MatchPercentage: 50, MatchedItems: []string{"ログイン"}, UnmatchedItems: []string{"パスワード再設定", "ログアウト"}, Usage: &Usage{InputTokens: 100, OutputTokens: 20}},
+	}
+
+	got, err := CombineResults(members, results, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.MatchPercentage != 80 {
+		t.Errorf("MatchPercentage = %d, want median 80", got.MatchPercentage)
+	}
+	if !reflect.DeepEqual(got.MatchedItems, []string{"ログイン", "ログアウト"}) {
+		t.Errorf("MatchedItems = %v", got.MatchedItems)
+	}
+	if !reflect.DeepEqual(got.UnmatchedItems, []string{"パスワード再設定"}) {
+		t.Errorf("UnmatchedItems = %v", got.UnmatchedItems)
+	}
+
+	c := got.Consensus
+	if c == nil {
+		t.Fatal("Consensus is nil")
+	}
+	if c.Spread != 40 {
+		t.Errorf("Spread = %d, want 40", c.Spread)
+	}
+	if !c.NeedsReview {
+		t.Error("expected NeedsReview when spread exceeds threshold")
+	}
+	if !reflect.DeepEqual(c.AgreedMatched, []string{"ログイン"}) {
+		t.Errorf("AgreedMatched = %v", c.AgreedMatched)
+	}
+	if c.MatchedVotes[0] != (ItemVote{Item: "ログイン", Votes: 3}) {
+		t.Errorf("MatchedVotes[0] = %+v", c.MatchedVotes[0])
+	}
+	if c.Members[2].MatchPercentage != 50 {
+		t.Errorf("member score = %d, want 50", c.Members[2].MatchPercentage)
+	}
+	if got.Retries != 1 || got.Usage == nil || got.Usage.Total() != 230 {
+		t.Errorf("Retries = %d, Usage = %+v", got.Retries, got.Usage)
+	}
+}
+
+func TestCombineResults_PartialFailure(t *testing.T) {
+	members := []MemberResult{
+		{Provider: "claude", Model: "a"},
+		{Provider: "openai", Model: "b", Error: "chunk 2/3: API error", Usage: &Usage{InputTokens: 50, OutputTokens: 5}, Attempts: 2},
+	}
+	results := []*VerificationResult{
+		{MatchPercentage: 70, Usage: &Usage{InputTokens: 100, OutputTokens: 10}, Attempts: 1},
+		nil,
+	}
+
+	got, err := CombineResults(members, results, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 残りのメンバーだけで統合した結果は要レビューとする
+	if got.MatchPercentage != 70 || !got.Consensus.NeedsReview {
+		t.Errorf("unexpected result: %+v", got)
+	}
+	// 失敗したメンバーが失敗するまでに消費した使用量も合計に含める
+	if got.Usage == nil || got.Usage.InputTokens != 150 || got.Usage.OutputTokens != 15 || got.Attempts != 3 {
+		t.Errorf("Usage = %+v, Attempts = %d, want the failed member included", got.Usage, got.Attempts)
+	}
+
+	// 代替プロバイダーの結果を含む場合は統合結果にも Fallback を設定する
+	results[0].Fallback = "openai"
+	if got, _ := CombineResults(members, results, 20); got.Fallback != "openai" {
+		t.Errorf("Fallback = %q, want openai", got.Fallback)
+	}
+
+	if _, err := CombineResults(members[1:], results[1:], 20); err == nil {
+		t.Error("expected error when all members fail")
+	}
+}
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		values []int
+		want   int
+	}{
+		{[]int{50}, 50},
+		{[]int{90, 10, 50}, 50},
+		{[]int{60, 81}, 71},
+		{[]int{10, 20, 30, 40}, 25},
+	}
+	for _, tt := range tests {
+		if got := median(tt.values); got != tt.want {
+			t.Errorf("median(%v) = %d, want %d", tt.values, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k-totani/spec-verify/internal/i18n"
+)
+
+const (
+	// ExecProviderName は検証とエンドポイント抽出を外部コマンドに委ねるプロバイダーの名前
+	ExecProviderName = "exec"
+
+	// ExecProtocolVersion は外部コマンドとやり取りするJSONのバージョン（docs/exec-provider.md）
+	// 既存のフィールドの意味を変える、または削除する場合に上げる。フィールドの追加では上げない
+	ExecProtocolVersion = 1
+
+	// execDefaultModel は ai.model が未指定の場合のモデル名（モデルの選択はコマンドに任せる）
+	execDefaultModel = "default"
+
+	// execDefaultTimeout は ai.request_timeout が未指定の場合の1回の実行のタイムアウト
+	execDefaultTimeout = 5 * time.Minute
+
+	// execWaitDelay はタイムアウトでコマンドを停止した後、出力が閉じられるのを待つ時間
+	// コマンドが起動した子プロセスが標準出力を開いたままでも、この時間が過ぎれば戻る
+	execWaitDelay = 5 * time.Second
+
+	// execStderrLimit はエラーに含める標準エラー出力の最大バイト数（末尾を残す）
+	execStderrLimit = 4096
+)
+
+// 外部コマンドに依頼するタスクの種類
+const (
+	ExecTaskVerify  = "verify"
+	ExecTaskExtract = "extract"
+)
+
+// ExecRequest は外部コマンドの標準入力に渡すリクエスト
+type ExecRequest struct {
+	// プロトコルのバージョン（ExecProtocolVersion）
+	Version int `json:"version"`
+
+	// タスクの種類（verify または extract）
+	Task string `json:"task"`
+
+	// ai.model で指定したモデル名（未指定の場合は省略）
+	Model string `json:"model,omitempty"`
+
+	// spec-verify がLLMに送信する場合のプロンプト（テンプレートと検証観点を反映済み）
+	// LLMゲートウェイに転送するだけのコマンドはこれを使用できる
+	Prompt string `json:"prompt"`
+
+	// 検証するSPEC（verify のみ）
+	Spec *ExecSpec `json:"spec,omitempty"`
+
+	// 関連コード（verify のみ。パス順）
+	CodeFiles []CodeFile `json:"codeFiles,omitempty"`
+
+	// 検証観点（verify のみ）
+	Focus []string `json:"focus,omitempty"`
+
+	// エンドポイントを抽出するコード（extract のみ。ファイルごとに「=== File: パス ===」の行で始まる）
+	Code string `json:"code,omitempty"`
+
+	// 生成と抽出のオプション
+	Options ExecOptions `json:"options"`
+}
+
+// ExecSpec は verify タスクで検証するSPEC
+type ExecSpec struct {
+	// SPECの本文（セクション単位の検証ではそのセクションのみ）
+	Content string `json:"content"`
+
+	// SPECのタイトル
+	Title string `json:"title,omitempty"`
+
+	// SPECのタイプ（ui, api など）
+	Type string `json:"type,omitempty"`
+
+	// SPECの基本情報テーブル（項目名 → 内容）
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// ExecOptions は外部コマンドに渡すオプション（未指定の項目は省略する）
+type ExecOptions struct {
+	// 回答に使用する言語の名前（例: English。空の場合は日本語）
+	Language string `json:"language,omitempty"`
+
+	// ai.max_output_tokens
+	MaxOutputTokens int `json:"maxOutputTokens,omitempty"`
+
+	// ai.temperature
+	Temperature *float64 `json:"temperature,omitempty"`
+
+	// ソースタイプ（extract のみ。express, nextjs など）
+	SourceType string `json:"sourceType,omitempty"`
+
+	// カテゴリ（extract のみ。api または ui）
+	Category string `json:"category,omitempty"`
+}
+
+// execVerifyResponse は verify タスクで外部コマンドが標準出力に返す結果
+type execVerifyResponse struct {
+	MatchPercentage json.RawMessage `json:"matchPercentage"`
+	MatchedItems    []string        `json:"matchedItems"`
+	UnmatchedItems  []string        `json:"unmatchedItems"`
+	Notes           string          `json:"notes"`
+	Usage           *Usage          `json:"usage"`
+	Retries         int             `json:"retries"`
+}
+
+// ExecProvider は検証とエンドポイント抽出を外部コマンドに委ねるプロバイダー
+// リクエストごとにコマンドを起動し、標準入力に ExecRequest のJSONを書き込み、標準出力からJSONの結果を読み取る
+// 社内のLLMゲートウェイなど、独自の認証や通信が必要な接続先をspec-verifyを変更せずに利用できる
+type ExecProvider struct {
+	command []string
+	env     []string
+	config  ProviderConfig
+}
+
+// NewExecProvider は command（コマンドと引数）を実行するプロバイダーを作成する
+func NewExecProvider(command []string, opts ...ProviderOption) (*ExecProvider, error) {
+	if len(command) == 0 || command[0] == "" {
+		return nil, fmt.Errorf("command is required for exec provider (ai.exec.command)")
+	}
+	cfg := newProviderConfig(opts)
+	return &ExecProvider{
+		command: command,
+		env:     commandEnv(cfg.CommandEnv),
+		config:  cfg,
+	}, nil
+}
+
+// commandEnv は現在の環境変数に extra を追加した環境変数を返す（extra がない場合は nil で現在の環境を引き継ぐ）
+func commandEnv(extra map[string]string) []string {
+	if len(extra) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(extra))
+	for name := range extra {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	env := os.Environ()
+	for _, name := range names {
+		env = append(env, name+"="+extra[name])
+	}
+	return env
+}
+
+// Name はプロバイダー名を返す
+func (p *ExecProvider) Name() string {
+	return ExecProviderName
+}
+
+// Model は ai.model で指定したモデル名、未指定の場合は default を返す
+func (p *ExecProvider) Model() string {
+	return p.config.modelOr(execDefaultModel)
+}
+
+// Verify はSPECとコードの一致度の検証をコマンドに依頼する
+func (p *ExecProvider) Verify(ctx context.Context, specContent string, codeContents map[string]string) (*VerificationResult, error) {
+	return p.VerifyWithOptions(ctx, specContent, codeContents, nil)
+}
+
+// VerifyWithOptions は検証観点を指定してSPECとコードの一致度の検証をコマンドに依頼する
+// 結果にはプロバイダーとモデル、所要時間、呼び出し回数（コマンドが報告した再試行を含む）を設定する
+func (p *ExecProvider) VerifyWithOptions(ctx context.Context, specContent string, codeContents map[string]string, opts *VerifyOptions) (*VerificationResult, error) {
+	prompt, err := BuildVerificationPrompt(specContent, codeContents, opts)
+	if err != nil {
+		return nil, err
+	}
+	req := p.newRequest(ExecTaskVerify, prompt)
+	req.Spec = &ExecSpec{Content: specContent}
+	for _, path := range sortedPaths(codeContents) {
+		req.CodeFiles = append(req.CodeFiles, CodeFile{Path: path, Content: codeContents[path]})
+	}
+	req.Focus = DefaultVerificationFocus(i18n.New(p.config.Language))
+	if opts != nil {
+		req.Spec.Title = opts.Title
+		req.Spec.Type = opts.SpecType
+		req.Spec.Metadata = opts.Metadata
+		if len(opts.VerificationFocus) > 0 {
+			req.Focus = opts.VerificationFocus
+		}
+		req.Options.Language = opts.Language
+	}
+
+	start := time.Now()
+	out, err := p.run(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	result, err := decodeExecVerification(out)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output from %s: %w", p.commandName(), err)
+	}
+	result.Provider = p.Name()
+	result.Model = p.Model()
+	result.LatencyMs = time.Since(start).Milliseconds()
+	result.Attempts = 1 + result.Retries
+	return result, nil
+}
+
+// ExtractEndpoints はコードからのAPIエンドポイント/ページルートの抽出をコマンドに依頼する
+func (p *ExecProvider) ExtractEndpoints(ctx context.Context, opts *ExtractOptions, codeContent string) ([]EndpointResult, error) {
+	prompt, err := buildExtractionPrompt(opts, codeContent)
+	if err != nil {
+		return nil, err
+	}
+	req := p.newRequest(ExecTaskExtract, prompt)
+	req.Code = codeContent
+	req.Options.SourceType = opts.GetSourceType()
+	req.Options.Category = CategoryAPI
+	if opts.IsUICategory() {
+		req.Options.Category = CategoryUI
+	}
+
+	out, err := p.run(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, fmt.Errorf("invalid output from %s: no output", p.commandName())
+	}
+	results, err := unmarshalEndpointResult(string(out))
+	if err != nil {
+		return nil, fmt.Errorf("invalid output from %s: %w", p.commandName(), err)
+	}
+	return results, nil
+}
+
+// newRequest はタスクに共通の項目を設定したリクエストを作成する
+func (p *ExecProvider) newRequest(task, prompt string) *ExecRequest {
+	return &ExecRequest{
+		Version: ExecProtocolVersion,
+		Task:    task,
+		Model:   p.config.Model,
+		Prompt:  prompt,
+		Options: ExecOptions{
+			MaxOutputTokens: p.config.MaxOutputTokens,
+			Temperature:     p.config.Temperature,
+		},
+	}
+}
+
+// run はコマンドを起動してリクエストを渡し、標準出力を返す
+// タイムアウトした場合や終了コードが0以外の場合は、標準エラー出力の末尾を含む再試行可能な *APIError を返す
+// （ai_provider のフォールバックで次のプロバイダーに進む）
+func (p *ExecProvider) run(ctx context.Context, req *ExecRequest) ([]byte, error) {
+	if err := p.config.RateLimiter.Wait(ctx, EstimateTokens(req.Prompt)); err != nil {
+		return nil, err
+	}
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	timeout := p.config.Timeout
+	if timeout <= 0 {
+		timeout = execDefaultTimeout
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	stderr := &tailBuffer{limit: execStderrLimit}
+	cmd := exec.CommandContext(runCtx, p.command[0], p.command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = stderr
+	cmd.Env = p.env
+	cmd.WaitDelay = execWaitDelay
+
+	err = cmd.Run()
+	switch {
+	case err == nil:
+		return stdout.Bytes(), nil
+	case ctx.Err() != nil:
+		return nil, ctx.Err()
+	case runCtx.Err() != nil:
+		return nil, &APIError{
+			Provider: ExecProviderName,
+			Kind:     ErrorKindNetwork,
+			Message:  fmt.Sprintf("%s timed out after %s%s", p.commandName(), timeout, stderr.suffix()),
+			Err:      runCtx.Err(),
+		}
+	default:
+		// コマンドを起動できない場合は設定の誤りのため、フォールバックしないよう通常のエラーとする
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("%s failed: %w", p.commandName(), err)
+		}
+		return nil, &APIError{
+			Provider: ExecProviderName,
+			Kind:     ErrorKindServer,
+			Message:  fmt.Sprintf("%s failed: %v%s", p.commandName(), err, stderr.suffix()),
+			Err:      err,
+		}
+	}
+}
+
+// commandName はエラーに表示するコマンド名を返す
+func (p *ExecProvider) commandName() string {
+	return "exec provider command " + filepath.Base(p.command[0])
+}
+
+// decodeExecVerification は verify タスクの出力を検証結果に変換する
+// 一致度は他のプロバイダーと同様に数値と文字列（"85%" など）を受け付けるが、省略はエラーとする
+func decodeExecVerification(out []byte) (*VerificationResult, error) {
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, fmt.Errorf("no output")
+	}
+	var resp execVerifyResponse
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.MatchPercentage) == 0 || string(resp.MatchPercentage) == "null" {
+		return nil, fmt.Errorf("matchPercentage is required")
+	}
+	percentage, err := coercePercentage(resp.MatchPercentage)
+	if err != nil {
+		return nil, err
+	}
+	if resp.MatchedItems == nil {
+		resp.MatchedItems = []string{}
+	}
+	if resp.UnmatchedItems == nil {
+		resp.UnmatchedItems = []string{}
+	}
+	return &VerificationResult{
+		MatchPercentage: percentage,
+		MatchedItems:    resp.MatchedItems,
+		UnmatchedItems:  resp.UnmatchedItems,
+		Notes:           resp.Notes,
+		Usage:           resp.Usage,
+		Retries:         max(resp.Retries, 0),
+	}, nil
+}
+
+// tailBuffer は書き込まれた内容の末尾 limit バイトだけを保持する
+type tailBuffer struct {
+	limit int
+	buf   []byte
+}
+
+// Write は p を追加し、limit を超えた先頭部分を捨てる
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - b.limit; over > 0 {
+		b.buf = append(b.buf[:0], b.buf[over:]...)
+	}
+	return len(p), nil
+}
+
+// suffix はエラーの末尾に付ける標準エラー出力（空の場合は空文字列）を返す
+func (b *tailBuffer) suffix() string {
+	text := strings.TrimSpace(string(bytes.ToValidUTF8(b.buf, nil)))
+	if text == "" {
+		return ""
+	}
+	return "\nstderr: " + text
+}
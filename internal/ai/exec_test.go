@@ -0,0 +1,159 @@
+package ai
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k-totani/spec-verify/internal/i18n"
+)
+
+// buildExecPlugin は testdata/execplugin をビルドして実行ファイルのパスを返す
+func buildExecPlugin(t *testing.T) string {
+	t.Helper()
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command is not available")
+	}
+	bin := filepath.Join(t.TempDir(), "execplugin")
+	if out, err := exec.Command(goTool, "build", "-o", bin, "./testdata/execplugin").CombinedOutput(); err != nil {
+		t.Fatalf("failed to build execplugin: %v\n%s", err, out)
+	}
+	return bin
+}
+
+func TestExecProvider_Verify(t *testing.T) {
+	plugin := buildExecPlugin(t)
+	provider, err := NewExecProvider([]string{plugin, "-env", "GATEWAY_REGION"},
+		WithModel("gateway-large"), WithCommandEnv(map[string]string{"GATEWAY_REGION": "ap-northeast-1"}))
+	if err != nil {
+		t.Fatalf("NewExecProvider failed: %v", err)
+	}
+
+	result, err := provider.VerifyWithOptions(context.Background(), "# ログイン画面",
+		map[string]string{"src/b.ts": "b", "src/a.ts": "a"},
+		&VerifyOptions{Title: "ログイン", SpecType: "ui", VerificationFocus: []string{"画面"}, Language: "English"})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if result.MatchPercentage != 75 || strings.Join(result.MatchedItems, ",") != "src/a.ts,src/b.ts" {
+		t.Errorf("result = %+v", result)
+	}
+	wantNotes := "title=ログイン type=ui model=gateway-large language=English focus=1 prompt=true GATEWAY_REGION=ap-northeast-1"
+	if result.Notes != wantNotes {
+		t.Errorf("Notes = %q, want %q", result.Notes, wantNotes)
+	}
+	if result.Usage == nil || result.Usage.InputTokens != 120 || result.Provider != ExecProviderName || result.Model != "gateway-large" || result.Attempts != 2 {
+		t.Errorf("accounting = %+v / %s / %s / %d", result.Usage, result.Provider, result.Model, result.Attempts)
+	}
+}
+
+func TestExecProvider_DefaultFocusLanguage(t *testing.T) {
+	provider, err := NewExecProvider([]string{buildExecPlugin(t)}, WithLanguage(i18n.English))
+	if err != nil {
+		t.Fatalf("NewExecProvider failed: %v", err)
+	}
+
+	result, err := provider.Verify(context.Background(), "# Login", map[string]string{"a.ts": "a"})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	want := DefaultVerificationFocus(i18n.New(i18n.English))
+	if strings.Join(result.UnmatchedItems, "\n") != strings.Join(want, "\n") {
+		t.Errorf("focus = %q, want the English default focus", result.UnmatchedItems)
+	}
+}
+
+func TestExecProvider_ExtractEndpoints(t *testing.T) {
+	provider, err := NewExecProvider([]string{buildExecPlugin(t)})
+	if err != nil {
+		t.Fatalf("NewExecProvider failed: %v", err)
+	}
+
+	endpoints, err := provider.ExtractEndpoints(context.Background(), &ExtractOptions{SourceType: "nextjs", Category: CategoryUI}, "=== File: app/page.tsx ===\n")
+	if err != nil {
+		t.Fatalf("ExtractEndpoints failed: %v", err)
+	}
+	if len(endpoints) != 1 || endpoints[0].Path != "/ui" || endpoints[0].Source != "nextjs" {
+		t.Errorf("endpoints = %+v", endpoints)
+	}
+	if provider.Model() != execDefaultModel {
+		t.Errorf("Model() = %q, want %q without ai.model", provider.Model(), execDefaultModel)
+	}
+}
+
+func TestExecProvider_Errors(t *testing.T) {
+	plugin := buildExecPlugin(t)
+	tests := []struct {
+		name      string
+		args      []string
+		opts      []ProviderOption
+		wantErr   []string
+		retryable bool
+	}{
+		{"exit status", []string{"-fail"}, nil, []string{"execplugin failed: exit status 3", "stderr: execplugin: received verify\ngateway: 401 unauthorized"}, true},
+		{"timeout", []string{"-sleep", "5s"}, []ProviderOption{WithTimeout(200 * time.Millisecond)}, []string{"timed out after 200ms", "received verify"}, true},
+		{"invalid json", []string{"-output", "not json"}, nil, []string{"invalid output from exec provider command execplugin"}, false},
+		{"no percentage", []string{"-output", `{"notes":"ok"}`}, nil, []string{"matchPercentage is required"}, false},
+		{"no output", []string{"-output", " "}, nil, []string{"no output"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewExecProvider(append([]string{plugin}, tt.args...), tt.opts...)
+			if err != nil {
+				t.Fatalf("NewExecProvider failed: %v", err)
+			}
+			_, err = provider.Verify(context.Background(), "# spec", map[string]string{"a.ts": "a"})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not contain %q", err, want)
+				}
+			}
+			if IsRetryable(err) != tt.retryable {
+				t.Errorf("IsRetryable(%q) = %v, want %v", err, IsRetryable(err), tt.retryable)
+			}
+		})
+	}
+
+	missing, err := NewExecProvider([]string{filepath.Join(t.TempDir(), "missing")})
+	if err != nil {
+		t.Fatalf("NewExecProvider failed: %v", err)
+	}
+	if _, err := missing.Verify(context.Background(), "# spec", nil); err == nil || IsRetryable(err) {
+		t.Errorf("err = %v, a command that cannot be started should not be retryable", err)
+	}
+
+	if _, err := NewExecProvider(nil); err == nil {
+		t.Error("expected error without a command")
+	}
+}
+
+func TestExecProvider_ChainFallback(t *testing.T) {
+	plugin := buildExecPlugin(t)
+	for _, args := range [][]string{{"-fail"}, {"-sleep", "5s"}} {
+		command, err := NewExecProvider(append([]string{plugin}, args...), WithTimeout(200*time.Millisecond))
+		if err != nil {
+			t.Fatalf("NewExecProvider failed: %v", err)
+		}
+		claude := &chainStubProvider{name: "claude"}
+		chain, err := NewChainProvider(command, claude)
+		if err != nil {
+			t.Fatalf("NewChainProvider failed: %v", err)
+		}
+
+		result, err := chain.Verify(context.Background(), "# spec", map[string]string{"a.ts": "a"})
+		if err != nil {
+			t.Fatalf("%v: Verify failed: %v", args, err)
+		}
+		if result.Fallback != "claude" || claude.calls != 1 {
+			t.Errorf("%v: result = %+v, want the claude result as a fallback", args, result)
+		}
+	}
+}
@@ -5,35 +5,64 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"strings"
 )
 
-const geminiAPIURLTemplate = "https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s"
+const (
+	geminiDefaultBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+	geminiDefaultModel   = "gemini-2.0-flash"
+)
+
+// GeminiTransport はGemini APIのワイヤーフォーマットを実装した Transport
+type GeminiTransport struct {
+	baseURL string
+	apiKey  string
+	model   string
+	config  ProviderConfig
+	client  *http.Client
+}
 
-// GeminiProvider はGemini APIを使用したプロバイダー
-type GeminiProvider struct {
-	apiKey string
-	model  string
+// NewGeminiProvider はGemini APIを使用したプロバイダーを作成する
+func NewGeminiProvider(apiKey string, opts ...ProviderOption) (*TransportProvider, error) {
+	transport, err := NewGeminiTransport(apiKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewTransportProvider(transport, opts...), nil
 }
 
-// NewGeminiProvider は新しいGeminiProviderを作成する
-func NewGeminiProvider(apiKey string) (*GeminiProvider, error) {
+// NewGeminiTransport は新しいGeminiTransportを作成する
+func NewGeminiTransport(apiKey string, opts ...ProviderOption) (*GeminiTransport, error) {
 	if apiKey == "" {
 		return nil, fmt.Errorf("API key is required")
 	}
 
-	return &GeminiProvider{
-		apiKey: apiKey,
-		model:  "gemini-2.0-flash",
+	cfg := newProviderConfig(opts)
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = geminiDefaultBaseURL
+	}
+
+	return &GeminiTransport{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   cfg.modelOr(geminiDefaultModel),
+		config:  cfg,
+		client:  cfg.httpClient(),
 	}, nil
 }
 
 // Name はプロバイダー名を返す
-func (p *GeminiProvider) Name() string {
+func (p *GeminiTransport) Name() string {
 	return "gemini"
 }
 
+// Model は使用しているモデル名を返す
+func (p *GeminiTransport) Model() string {
+	return p.model
+}
+
 // geminiRequest はGemini APIへのリクエスト
 type geminiRequest struct {
 	Contents         []geminiContent         `json:"contents"`
@@ -49,8 +78,10 @@ type geminiPart struct {
 }
 
 type geminiGenerationConfig struct {
-	MaxOutputTokens int     `json:"maxOutputTokens,omitempty"`
-	Temperature     float64 `json:"temperature,omitempty"`
+	MaxOutputTokens  int            `json:"maxOutputTokens,omitempty"`
+	Temperature      *float64       `json:"temperature,omitempty"`
+	ResponseMimeType string         `json:"responseMimeType,omitempty"`
+	ResponseSchema   map[string]any `json:"responseSchema,omitempty"`
 }
 
 // geminiResponse はGemini APIからのレスポンス
@@ -62,6 +93,10 @@ type geminiResponse struct {
 			} `json:"parts"`
 		} `json:"content"`
 	} `json:"candidates"`
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata"`
 	Error *struct {
 		Code    int    `json:"code"`
 		Message string `json:"message"`
@@ -69,103 +104,65 @@ type geminiResponse struct {
 	} `json:"error,omitempty"`
 }
 
-// Verify はSPECとコードの一致度を検証する
-func (p *GeminiProvider) Verify(ctx context.Context, specContent string, codeContents map[string]string) (*VerificationResult, error) {
-	return p.VerifyWithOptions(ctx, specContent, codeContents, nil)
-}
-
-// VerifyWithOptions は検証観点を指定してSPECとコードの一致度を検証する
-func (p *GeminiProvider) VerifyWithOptions(ctx context.Context, specContent string, codeContents map[string]string, opts *VerifyOptions) (*VerificationResult, error) {
-	var prompt string
-	if opts != nil && len(opts.VerificationFocus) > 0 {
-		prompt = buildVerificationPromptWithFocus(specContent, codeContents, opts.VerificationFocus)
-	} else {
-		prompt = buildVerificationPrompt(specContent, codeContents)
-	}
-
-	text, err := p.callAPI(ctx, prompt, 2000)
-	if err != nil {
-		return nil, err
-	}
-
-	return parseVerificationResult(text)
-}
-
-// ExtractEndpoints はコードからAPIエンドポイント/ページルートを抽出する
-func (p *GeminiProvider) ExtractEndpoints(ctx context.Context, opts *ExtractOptions, codeContent string) ([]EndpointResult, error) {
-	var prompt string
-	if opts.IsUICategory() {
-		prompt = buildUIRouteExtractionPrompt(opts.GetSourceType(), codeContent)
-	} else {
-		prompt = buildEndpointExtractionPrompt(opts.GetSourceType(), codeContent)
-	}
-
-	text, err := p.callAPI(ctx, prompt, 4000)
-	if err != nil {
-		return nil, err
-	}
-
-	return parseEndpointResult(text)
-}
-
-// callAPI はGemini APIを呼び出す共通関数
-func (p *GeminiProvider) callAPI(ctx context.Context, prompt string, maxTokens int) (string, error) {
+// Complete はGemini APIにプロンプトを送信する
+func (p *GeminiTransport) Complete(ctx context.Context, request *CompletionRequest) (*Completion, error) {
+	temperature := p.config.temperatureOr(0.1)
 	req := geminiRequest{
 		Contents: []geminiContent{
 			{
 				Parts: []geminiPart{
-					{Text: prompt},
+					{Text: request.Prompt},
 				},
 			},
 		},
 		GenerationConfig: &geminiGenerationConfig{
-			MaxOutputTokens: maxTokens,
-			Temperature:     0.1,
+			MaxOutputTokens: request.MaxTokens,
+			Temperature:     &temperature,
 		},
 	}
-
-	reqBody, err := json.Marshal(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	apiURL := fmt.Sprintf(geminiAPIURLTemplate, p.model, p.apiKey)
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewReader(reqBody))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+	if request.Schema != nil {
+		req.GenerationConfig.ResponseMimeType = "application/json"
+		req.GenerationConfig.ResponseSchema = geminiSchema(request.Schema.Schema)
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(httpReq)
+	reqBody, err := json.Marshal(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	// APIキーはエラーやログに含まれるURLに残らないよう、クエリではなくヘッダーで送信する
+	apiURL := fmt.Sprintf("%s/models/%s:generateContent", p.baseURL, p.model)
+	body, retries, err := sendWithRetry(ctx, p.client, p.config.Retry, p.config.RateLimiter, EstimateTokens(request.Prompt), p.Name(), func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewReader(reqBody))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("x-goog-api-key", p.apiKey)
+		return httpReq, nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, err
 	}
 
 	var geminiResp geminiResponse
 	if err := json.Unmarshal(body, &geminiResp); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	if geminiResp.Error != nil {
-		return "", fmt.Errorf("API error: %s", geminiResp.Error.Message)
+		return nil, fmt.Errorf("API error: %s", geminiResp.Error.Message)
 	}
 
 	if len(geminiResp.Candidates) == 0 ||
 		len(geminiResp.Candidates[0].Content.Parts) == 0 {
-		return "", fmt.Errorf("empty response from API")
+		return nil, fmt.Errorf("empty response from API")
 	}
 
-	return geminiResp.Candidates[0].Content.Parts[0].Text, nil
+	return &Completion{
+		Text:       geminiResp.Candidates[0].Content.Parts[0].Text,
+		Retries:    retries,
+		Structured: request.Schema != nil,
+		Usage:      Usage{InputTokens: geminiResp.UsageMetadata.PromptTokenCount, OutputTokens: geminiResp.UsageMetadata.CandidatesTokenCount},
+	}, nil
 }
@@ -0,0 +1,385 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/k-totani/spec-verify/internal/i18n"
+)
+
+const (
+	// HeuristicProviderName はAIを使用しないヒューリスティック検証プロバイダーの名前
+	HeuristicProviderName = "heuristic"
+	// heuristicModel はヒューリスティック検証のルールのバージョン
+	// 抽出や照合のルールを変更した場合は上げる（キャッシュキーに含まれる）
+	heuristicModel = "rules-v1"
+)
+
+// 検証項目の種類（結果に表示する項目名のメッセージキー）
+const (
+	checkRoute      = "heuristic.route"
+	checkIdentifier = "heuristic.identifier"
+	checkLabel      = "heuristic.label"
+	checkMessage    = "heuristic.message"
+	checkConstraint = "heuristic.constraint"
+)
+
+// ErrNoHeuristicChecks はSPECからヒューリスティック検証で照合できる項目を抽出できなかったことを表す
+// 一致度0%として不合格にしないよう、検証できなかったSPECとしてエラーを返す
+var ErrNoHeuristicChecks = errors.New("no checkable items (routes, identifiers, labels, messages, validation rules) found in the spec for heuristic verification")
+
+// heuristicCheck はSPECから抽出した、コード中に存在すべき1項目
+type heuristicCheck struct {
+	kind  string
+	value string
+
+	// バリデーションの制約値の場合の対象フィールド
+	field string
+}
+
+// label は結果に表示する項目名を返す
+func (c heuristicCheck) label(msg *i18n.Printer) string {
+	if c.kind == checkConstraint {
+		return msg.Sprintf(c.kind, c.field, c.value)
+	}
+	return msg.Sprintf(c.kind, c.value)
+}
+
+// HeuristicProvider はAIを使わずにSPECとコードを照合するプロバイダー
+// SPECの表と箇条書きから識別子、UIラベル、ルート、バリデーションの制約値、エラーメッセージを抽出し、
+// コード中に存在するかを検索する。APIキーやネットワークは不要で、結果は常に同じになる
+type HeuristicProvider struct {
+	// 結果の項目名と備考の言語
+	msg *i18n.Printer
+}
+
+// NewHeuristicProvider は新しいHeuristicProviderを作成する
+// 結果の項目名と備考は WithLanguage で指定した言語で記述する
+func NewHeuristicProvider(opts ...ProviderOption) *HeuristicProvider {
+	cfg := newProviderConfig(opts)
+	return &HeuristicProvider{msg: i18n.New(cfg.Language)}
+}
+
+// Name はプロバイダー名を返す
+func (p *HeuristicProvider) Name() string {
+	return HeuristicProviderName
+}
+
+// Model はルールのバージョンを返す
+func (p *HeuristicProvider) Model() string {
+	return heuristicModel
+}
+
+// Verify はSPECから抽出した項目がコードに存在するかを検証する
+func (p *HeuristicProvider) Verify(ctx context.Context, specContent string, codeContents map[string]string) (*VerificationResult, error) {
+	return p.VerifyWithOptions(ctx, specContent, codeContents, nil)
+}
+
+// VerifyWithOptions はSPECから抽出した項目がコードに存在するかを検証する
+// 検証観点はAIへのヒントのため使用しない
+func (p *HeuristicProvider) VerifyWithOptions(ctx context.Context, specContent string, codeContents map[string]string, opts *VerifyOptions) (*VerificationResult, error) {
+	checks := extractHeuristicChecks(specContent)
+	if len(checks) == 0 {
+		return nil, ErrNoHeuristicChecks
+	}
+	code := joinCode(codeContents)
+
+	result := &VerificationResult{
+		MatchedItems:   []string{},
+		UnmatchedItems: []string{},
+	}
+
+	for _, c := range checks {
+		if c.foundIn(code) {
+			result.MatchedItems = append(result.MatchedItems, c.label(p.msg))
+		} else {
+			result.UnmatchedItems = append(result.UnmatchedItems, c.label(p.msg))
+		}
+	}
+	result.MatchPercentage = int(math.Round(float64(len(result.MatchedItems)) / float64(len(checks)) * 100))
+	result.Notes = p.msg.Sprintf("heuristic.notes", len(checks), len(result.MatchedItems))
+	return result, nil
+}
+
+// VerifyFallback は name の検証が cause で失敗した場合に代わりに返すヒューリスティック検証の結果を作成する
+// 結果には Fallback を設定し、備考に失敗の理由を記載する
+// アンサンブル検証や自己一貫性チェックのように、複数の結果を統合する検証の代替にも使用する
+// ヒューリスティック検証でも検証できない場合は cause を返す
+func (p *HeuristicProvider) VerifyFallback(ctx context.Context, name string, cause error, specContent string, codeContents map[string]string, opts *VerifyOptions) (*VerificationResult, error) {
+	result, err := p.VerifyWithOptions(ctx, specContent, codeContents, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w (heuristic fallback: %v)", cause, err)
+	}
+	result.Fallback = HeuristicProviderName
+	result.Notes = p.msg.Sprintf("heuristic.fallback", name, cause, result.Notes)
+	return result, nil
+}
+
+// joinCode はコードを1つの文字列に連結する（ファイル順は固定）
+func joinCode(codeContents map[string]string) string {
+	paths := make([]string, 0, len(codeContents))
+	for path := range codeContents {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var b strings.Builder
+	for _, path := range paths {
+		b.WriteString(codeContents[path])
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+var (
+	headingPattern    = regexp.MustCompile(`^#{1,6}\s+(.+)$`)
+	bulletPattern     = regexp.MustCompile(`^\s*(?:[-*+]|\d+\.)\s+(.+)$`)
+	backtickPattern   = regexp.MustCompile("`([^`]+)`")
+	quotedPattern     = regexp.MustCompile(`「([^」]+)」|『([^』]+)』|"([^"]+)"|“([^”]+)”`)
+	methodPathPattern = regexp.MustCompile(`\b(?:GET|POST|PUT|PATCH|DELETE)\s+(/[^\s` + "`" + `|]*)`)
+	numberPattern     = regexp.MustCompile(`\d+`)
+	identPattern      = regexp.MustCompile(`^[A-Za-z_$][\w$.-]*$`)
+	filePathPattern   = regexp.MustCompile(`^~/|\.(?:tsx?|jsx?|go|py|rb|java|kt|swift|vue|svelte|md|ya?ml|json)$`)
+)
+
+// extractHeuristicChecks はSPECの表と箇条書きから照合する項目を抽出する
+func extractHeuristicChecks(specContent string) []heuristicCheck {
+	var checks []heuristicCheck
+	seen := make(map[string]bool)
+	add := func(c heuristicCheck) {
+		c.value = strings.TrimSpace(c.value)
+		if c.value == "" {
+			return
+		}
+		key := c.kind + "\x00" + c.field + "\x00" + c.value
+		if !seen[key] {
+			seen[key] = true
+			checks = append(checks, c)
+		}
+	}
+
+	section := ""
+	for _, line := range strings.Split(specContent, "\n") {
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			section = m[1]
+			continue
+		}
+		// 関連ファイルはコードの選択に使用済みのため照合しない
+		if strings.Contains(section, "関連") || strings.Contains(strings.ToLower(section), "related") {
+			continue
+		}
+
+		var cells []string
+		switch {
+		case strings.HasPrefix(strings.TrimSpace(line), "|"):
+			if strings.Contains(line, "---") {
+				continue
+			}
+			for _, cell := range strings.Split(strings.Trim(strings.TrimSpace(line), "|"), "|") {
+				cells = append(cells, strings.TrimSpace(cell))
+			}
+		case bulletPattern.MatchString(line):
+			cells = []string{bulletPattern.FindStringSubmatch(line)[1]}
+		default:
+			continue
+		}
+
+		isValidation := strings.Contains(section, "バリデーション") || strings.Contains(strings.ToLower(section), "validation")
+		isError := strings.Contains(section, "エラー") || strings.Contains(strings.ToLower(section), "error")
+
+		for i, cell := range cells {
+			for _, m := range methodPathPattern.FindAllStringSubmatch(cell, -1) {
+				add(heuristicCheck{kind: checkRoute, value: m[1]})
+			}
+			for _, m := range backtickPattern.FindAllStringSubmatch(cell, -1) {
+				value := m[1]
+				switch {
+				case filePathPattern.MatchString(value):
+				case strings.HasPrefix(value, "/"):
+					add(heuristicCheck{kind: checkRoute, value: value})
+				case identPattern.MatchString(value):
+					add(heuristicCheck{kind: checkIdentifier, value: value})
+				}
+			}
+			for _, m := range quotedPattern.FindAllStringSubmatch(cell, -1) {
+				value := firstNonEmpty(m[1:])
+				kind := checkLabel
+				if isError || strings.Contains(cell, "エラー") || strings.Contains(cell, "メッセージ") {
+					kind = checkMessage
+				}
+				add(heuristicCheck{kind: kind, value: value})
+			}
+
+			// バリデーション表: 1列目のフィールド名と、ルールに含まれる数値（最大文字数など）
+			if isValidation && len(cells) >= 2 && i == 0 {
+				field := strings.Trim(cell, "`")
+				if identPattern.MatchString(field) {
+					add(heuristicCheck{kind: checkIdentifier, value: field})
+				}
+				for _, rule := range cells[1:] {
+					for _, n := range numberPattern.FindAllString(rule, -1) {
+						add(heuristicCheck{kind: checkConstraint, field: field, value: n})
+					}
+				}
+			}
+		}
+	}
+	return checks
+}
+
+// firstNonEmpty は最初の空でない文字列を返す
+func firstNonEmpty(values []string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// foundIn はコード中に項目が存在するかを返す
+func (c heuristicCheck) foundIn(code string) bool {
+	switch c.kind {
+	case checkRoute:
+		return routePattern(c.value).MatchString(code)
+	case checkIdentifier:
+		// camelCase, snake_case, kebab-case の表記揺れは同じ識別子とみなす
+		return strings.Contains(normalizeIdentifiers(code), normalizeIdentifiers(c.value))
+	case checkConstraint:
+		return regexp.MustCompile(`\b` + regexp.QuoteMeta(c.value) + `\b`).MatchString(code)
+	default:
+		return strings.Contains(code, c.value)
+	}
+}
+
+// normalizeIdentifiers は識別子の比較用に小文字化し、区切り文字を除去する
+func normalizeIdentifiers(s string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(s))
+}
+
+// routeParamPattern はルートのパラメータ部分（:id, {id}, [id], $id）
+var routeParamPattern = regexp.MustCompile(`^(?::\w+|\{\w+\}|\[\w+\]|\$\w+)$`)
+
+// routePattern はルートの照合に使用する正規表現を返す
+// パラメータの書き方はフレームワークにより異なるため、どの書き方でも一致させる
+func routePattern(route string) *regexp.Regexp {
+	if route == "/" {
+		return regexp.MustCompile(`["'` + "`" + `]/["'` + "`" + `]`)
+	}
+	segments := strings.Split(strings.TrimSuffix(route, "/"), "/")
+	parts := make([]string, len(segments))
+	for i, seg := range segments {
+		if routeParamPattern.MatchString(seg) {
+			parts[i] = `(?::\w+|\{\w+\}|\[\w+\]|\$\{?\w+\}?)`
+		} else {
+			parts[i] = regexp.QuoteMeta(seg)
+		}
+	}
+	return regexp.MustCompile(`["'` + "`" + `]` + strings.Join(parts, "/") + `/?["'` + "`" + `?]`)
+}
+
+var (
+	fileMarkerPattern  = regexp.MustCompile(`^=== File: (.+) ===$`)
+	routerCallPattern  = regexp.MustCompile(`\.(get|post|put|patch|delete|GET|POST|PUT|PATCH|DELETE)\(\s*["'` + "`" + `](/[^"'` + "`" + `]*)["'` + "`" + `]`)
+	handleFuncPattern  = regexp.MustCompile(`Handle(?:Func)?\(\s*"(?:(GET|POST|PUT|PATCH|DELETE) )?(/[^"]*)"`)
+	uiRoutePathPattern = regexp.MustCompile(`(?:<Route[^>]*\spath=|\bpath:\s*)["'](/[^"']*)["']`)
+)
+
+// ExtractEndpoints はルーター定義のパターンからエンドポイント/ページルートを抽出する
+// express/fastify 形式の router.get("/path")、echo/gin 形式の e.GET("/path")、
+// net/http の HandleFunc("GET /path")、React Router/Vue Router の path 指定に対応する
+func (p *HeuristicProvider) ExtractEndpoints(ctx context.Context, opts *ExtractOptions, codeContent string) ([]EndpointResult, error) {
+	results := []EndpointResult{}
+	seen := make(map[string]bool)
+	add := func(method, path, file string) {
+		key := method + " " + path
+		if !seen[key] {
+			seen[key] = true
+			results = append(results, EndpointResult{Method: method, Path: path, File: file})
+		}
+	}
+
+	file := ""
+	for _, line := range strings.Split(codeContent, "\n") {
+		if m := fileMarkerPattern.FindStringSubmatch(line); m != nil {
+			file = m[1]
+			continue
+		}
+		if opts.IsUICategory() {
+			for _, m := range uiRoutePathPattern.FindAllStringSubmatch(line, -1) {
+				add("PAGE", m[1], file)
+			}
+			continue
+		}
+		for _, m := range routerCallPattern.FindAllStringSubmatch(line, -1) {
+			add(strings.ToUpper(m[1]), m[2], file)
+		}
+		for _, m := range handleFuncPattern.FindAllStringSubmatch(line, -1) {
+			method := m[1]
+			if method == "" {
+				method = "ANY"
+			}
+			add(method, m[2], file)
+		}
+	}
+	return results, nil
+}
+
+// HeuristicFallbackProvider はAIプロバイダーの呼び出しに失敗した場合にヒューリスティック検証で代替するプロバイダー
+type HeuristicFallbackProvider struct {
+	primary   Provider
+	heuristic *HeuristicProvider
+}
+
+// NewHeuristicFallback は primary が失敗した場合にヒューリスティック検証の結果を返すプロバイダーを作成する
+// opts はヒューリスティック検証に使用する（WithLanguage で結果の言語を指定する）
+func NewHeuristicFallback(primary Provider, opts ...ProviderOption) *HeuristicFallbackProvider {
+	return &HeuristicFallbackProvider{primary: primary, heuristic: NewHeuristicProvider(opts...)}
+}
+
+// Name は primary のプロバイダー名を返す
+func (p *HeuristicFallbackProvider) Name() string {
+	return p.primary.Name()
+}
+
+// Model は primary のモデル名を返す
+func (p *HeuristicFallbackProvider) Model() string {
+	return p.primary.Model()
+}
+
+// Verify はSPECとコードの一致度を検証する
+func (p *HeuristicFallbackProvider) Verify(ctx context.Context, specContent string, codeContents map[string]string) (*VerificationResult, error) {
+	return p.VerifyWithOptions(ctx, specContent, codeContents, nil)
+}
+
+// VerifyWithOptions は primary で検証し、失敗した場合はヒューリスティック検証の結果を返す
+// 代替した結果には Fallback を設定し、備考に失敗の理由を記載する
+// SPECから照合できる項目がなくヒューリスティック検証でも検証できない場合は primary のエラーを返す
+func (p *HeuristicFallbackProvider) VerifyWithOptions(ctx context.Context, specContent string, codeContents map[string]string, opts *VerifyOptions) (*VerificationResult, error) {
+	var result *VerificationResult
+	var err error
+	if opts != nil {
+		result, err = p.primary.VerifyWithOptions(ctx, specContent, codeContents, opts)
+	} else {
+		result, err = p.primary.Verify(ctx, specContent, codeContents)
+	}
+	if err == nil || ctx.Err() != nil {
+		return result, err
+	}
+
+	return p.heuristic.VerifyFallback(ctx, p.primary.Name(), err, specContent, codeContents, opts)
+}
+
+// ExtractEndpoints は primary で抽出し、失敗した場合はルーター定義のパターンから抽出する
+func (p *HeuristicFallbackProvider) ExtractEndpoints(ctx context.Context, opts *ExtractOptions, codeContent string) ([]EndpointResult, error) {
+	endpoints, err := p.primary.ExtractEndpoints(ctx, opts, codeContent)
+	if err == nil || ctx.Err() != nil {
+		return endpoints, err
+	}
+	return p.heuristic.ExtractEndpoints(ctx, opts, codeContent)
+}
@@ -0,0 +1,184 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/k-totani/spec-verify/internal/i18n"
+)
+
+const heuristicSpec = "# ログイン画面\n\n" +
+	"## 基本情報\n\n" +
+	"| 項目 | 内容 |\n|------|------|\n" +
+	"| パス | `/login` |\n" +
+	"| 必要権限 | `GUEST` |\n\n" +
+	"## 画面構成\n\n" +
+	"- 「ログイン」ボタン\n" +
+	"- `rememberMe` チェックボックス\n\n" +
+	"## バリデーション\n\n" +
+	"| 項目 | ルール |\n|------|--------|\n" +
+	"| `email` | 必須、最大255文字 |\n\n" +
+	"## エラーケース\n\n" +
+	"- 認証失敗時は「メールアドレスまたはパスワードが違います」を表示\n\n" +
+	"## 関連ファイル\n\n" +
+	"- `~/pages/Login.tsx`\n"
+
+func TestExtractHeuristicChecks(t *testing.T) {
+	checks := extractHeuristicChecks(heuristicSpec)
+
+	var got []string
+	for _, c := range checks {
+		got = append(got, c.label(i18n.New(i18n.Japanese)))
+	}
+	want := []string{
+		"ルート /login",
+		"識別子 GUEST",
+		"UIラベル「ログイン」",
+		"識別子 rememberMe",
+		"識別子 email",
+		"バリデーション email: 255",
+		"エラーメッセージ「メールアドレスまたはパスワードが違います」",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("checks =\n%v\nwant\n%v", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestHeuristicProvider_Verify(t *testing.T) {
+	code := map[string]string{
+		"pages/Login.tsx": `export const route = "/login";
+const role = "guest";
+export function Login() {
+  const [remember_me, setRememberMe] = useState(false);
+  const schema = z.object({ email: z.string().max(255) });
+  return <button>ログイン</button>;
+}`,
+	}
+
+	p := NewHeuristicProvider()
+	result, err := p.Verify(context.Background(), heuristicSpec, code)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !reflect.DeepEqual(result.UnmatchedItems, []string{"エラーメッセージ「メールアドレスまたはパスワードが違います」"}) {
+		t.Errorf("UnmatchedItems = %v", result.UnmatchedItems)
+	}
+	if result.MatchPercentage != 86 {
+		t.Errorf("MatchPercentage = %d, want 86 (6/7)", result.MatchPercentage)
+	}
+
+	// 同じ入力には常に同じ結果を返す
+	again, _ := p.Verify(context.Background(), heuristicSpec, code)
+	if !reflect.DeepEqual(result, again) {
+		t.Error("heuristic verification is not deterministic")
+	}
+
+	// 項目名と備考は指定した言語で記述する
+	english, _ := NewHeuristicProvider(WithLanguage("en")).Verify(context.Background(), heuristicSpec, code)
+	if !reflect.DeepEqual(english.UnmatchedItems, []string{`error message "メールアドレスまたはパスワードが違います"`}) ||
+		!strings.HasPrefix(english.Notes, "Heuristic verification (no AI): of 7 items, 6 were found") {
+		t.Errorf("english result = %+v", english)
+	}
+
+	// 照合できる項目がないSPECは0%ではなく検証できないものとしてエラーを返す
+	if empty, err := p.Verify(context.Background(), "# タイトルのみ\n", code); !errors.Is(err, ErrNoHeuristicChecks) {
+		t.Errorf("Verify = %+v, %v; want ErrNoHeuristicChecks", empty, err)
+	}
+}
+
+func TestRoutePattern(t *testing.T) {
+	tests := []struct {
+		route string
+		code  string
+		want  bool
+	}{
+		{"/users", `router.get("/users", h)`, true},
+		{"/users/:id", `e.GET("/users/{id}", h)`, true},
+		{"/users/{id}", "app.get(`/users/:userId`, h)", true},
+		{"/users", `router.get("/users/:id", h)`, false},
+		{"/", `path: "/"`, true},
+	}
+	for _, tt := range tests {
+		if got := routePattern(tt.route).MatchString(tt.code); got != tt.want {
+			t.Errorf("routePattern(%q).MatchString(%q) = %v, want %v", tt.route, tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestHeuristicProvider_ExtractEndpoints(t *testing.T) {
+	code := "=== File: src/routes/users.ts ===\n" +
+		"router.get('/users', list)\nrouter.post(\"/users\", create)\n" +
+		"=== File: main.go ===\n" +
+		"mux.HandleFunc(\"DELETE /users/{id}\", remove)\ne.GET(\"/health\", ok)\n"
+
+	p := NewHeuristicProvider()
+	got, err := p.ExtractEndpoints(context.Background(), &ExtractOptions{Category: CategoryAPI}, code)
+	if err != nil {
+		t.Fatalf("ExtractEndpoints failed: %v", err)
+	}
+	want := []EndpointResult{
+		{Method: "GET", Path: "/users", File: "src/routes/users.ts"},
+		{Method: "POST", Path: "/users", File: "src/routes/users.ts"},
+		{Method: "DELETE", Path: "/users/{id}", File: "main.go"},
+		{Method: "GET", Path: "/health", File: "main.go"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("endpoints = %+v", got)
+	}
+
+	ui, _ := p.ExtractEndpoints(context.Background(), &ExtractOptions{Category: CategoryUI}, `<Route path="/settings" element={<Settings />} />`)
+	if len(ui) != 1 || ui[0].Method != "PAGE" || ui[0].Path != "/settings" {
+		t.Errorf("ui routes = %+v", ui)
+	}
+}
+
+// failingProvider は常にエラーを返すテスト用プロバイダー
+type failingProvider struct{}
+
+func (failingProvider) Verify(ctx context.Context, specContent string, codeContents map[string]string) (*VerificationResult, error) {
+	return nil, errors.New("API error (status 503)")
+}
+
+func (failingProvider) VerifyWithOptions(ctx context.Context, specContent string, codeContents map[string]string, opts *VerifyOptions) (*VerificationResult, error) {
+	return nil, errors.New("API error (status 503)")
+}
+
+func (failingProvider) ExtractEndpoints(ctx context.Context, opts *ExtractOptions, codeContent string) ([]EndpointResult, error) {
+	return nil, errors.New("API error (status 503)")
+}
+
+func (failingProvider) Name() string  { return "claude" }
+func (failingProvider) Model() string { return "test-model" }
+
+func TestHeuristicFallback(t *testing.T) {
+	p := NewHeuristicFallback(failingProvider{})
+	if p.Name() != "claude" {
+		t.Errorf("Name = %q, want primary name", p.Name())
+	}
+
+	result, err := p.Verify(context.Background(), heuristicSpec, map[string]string{"a.ts": `"/login"`})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if result.Fallback != HeuristicProviderName || !strings.Contains(result.Notes, "status 503") {
+		t.Errorf("unexpected fallback result: %+v", result)
+	}
+
+	english, err := NewHeuristicFallback(failingProvider{}, WithLanguage("en")).Verify(context.Background(), heuristicSpec, map[string]string{"a.ts": `"/login"`})
+	if err != nil || !strings.HasPrefix(english.Notes, "This is the result of heuristic verification because the call to claude failed") {
+		t.Errorf("english fallback = %+v, %v", english, err)
+	}
+
+	// ヒューリスティック検証でも検証できない場合は primary のエラーを返す
+	if _, err := p.Verify(context.Background(), "# タイトルのみ\n", nil); err == nil || !strings.Contains(err.Error(), "status 503") {
+		t.Errorf("err = %v, want the primary error", err)
+	}
+
+	endpoints, err := p.ExtractEndpoints(context.Background(), nil, `router.get("/users", h)`)
+	if err != nil || len(endpoints) != 1 {
+		t.Errorf("ExtractEndpoints = %+v, %v", endpoints, err)
+	}
+}
@@ -0,0 +1,136 @@
+package ai
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// HTTPクライアントのデフォルトのタイムアウト
+const (
+	// DefaultConnectTimeout は接続（TCP接続とTLSハンドシェイク）のタイムアウト
+	DefaultConnectTimeout = 30 * time.Second
+
+	// DefaultResponseTimeout はリクエスト送信後、レスポンスヘッダーを受け取るまでのタイムアウト
+	// ストリーミングしないため、生成が終わるまでヘッダーが返らないことを考慮して長めにしている
+	DefaultResponseTimeout = 5 * time.Minute
+)
+
+// HTTPSettings はAIプロバイダーとの通信に使用するHTTPクライアントの設定
+type HTTPSettings struct {
+	// 接続のタイムアウト（0の場合は DefaultConnectTimeout）
+	ConnectTimeout time.Duration
+
+	// レスポンスヘッダーを受け取るまでのタイムアウト（0の場合は DefaultResponseTimeout）
+	ResponseTimeout time.Duration
+
+	// プロキシのURL（空の場合は HTTPS_PROXY / HTTP_PROXY / NO_PROXY 環境変数に従う）
+	Proxy string
+
+	// システムの証明書に加えて信頼するCA証明書（PEM形式）のファイルパス
+	CABundle string
+
+	// 全てのリクエストに付与するヘッダー（APIゲートウェイの認証など）
+	// プロバイダーが設定するヘッダーと同じ名前の場合はこちらを優先する
+	Headers map[string]string
+}
+
+var (
+	sharedClientsMu sync.Mutex
+	sharedClients   = map[string]*http.Client{}
+)
+
+// SharedHTTPClient は設定ごとに1つのHTTPクライアントを返す
+// 同じ設定のプロバイダーは接続プールを共有し、全てのgoroutineから同じクライアントを使用する
+func SharedHTTPClient(settings HTTPSettings) (*http.Client, error) {
+	key := fmt.Sprintf("%+v", settings)
+
+	sharedClientsMu.Lock()
+	defer sharedClientsMu.Unlock()
+	if client, ok := sharedClients[key]; ok {
+		return client, nil
+	}
+	client, err := NewHTTPClient(settings)
+	if err != nil {
+		return nil, err
+	}
+	sharedClients[key] = client
+	return client, nil
+}
+
+// NewHTTPClient は設定に基づいたHTTPクライアントを作成する
+// リクエスト全体のタイムアウトはプロバイダーごとに設定するため、ここでは設定しない
+func NewHTTPClient(settings HTTPSettings) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	connectTimeout := settings.ConnectTimeout
+	if connectTimeout <= 0 {
+		connectTimeout = DefaultConnectTimeout
+	}
+	transport.DialContext = (&net.Dialer{Timeout: connectTimeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = connectTimeout
+
+	transport.ResponseHeaderTimeout = settings.ResponseTimeout
+	if transport.ResponseHeaderTimeout <= 0 {
+		transport.ResponseHeaderTimeout = DefaultResponseTimeout
+	}
+
+	if settings.Proxy != "" {
+		proxyURL, err := url.Parse(settings.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", settings.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if settings.CABundle != "" {
+		pool, err := loadCABundle(settings.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	var rt http.RoundTripper = transport
+	if len(settings.Headers) > 0 {
+		rt = &headerTransport{base: transport, headers: settings.Headers}
+	}
+	return &http.Client{Transport: rt}, nil
+}
+
+// loadCABundle はシステムの証明書にCA証明書ファイルを追加した証明書プールを返す
+func loadCABundle(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in CA bundle %s", path)
+	}
+	return pool, nil
+}
+
+// headerTransport は全てのリクエストに固定のヘッダーを付与する
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+// RoundTrip はヘッダーを付与したリクエストを送信する（元のリクエストは変更しない）
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+	return t.base.RoundTrip(req)
+}
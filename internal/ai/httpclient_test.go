@@ -0,0 +1,159 @@
+package ai
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPClient_Headers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Gateway-Key"); got != "gw-secret" {
+			t.Errorf("X-Gateway-Key = %q, want %q", got, "gw-secret")
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer k" {
+			t.Errorf("Authorization = %q, want the provider header", got)
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"[]"}}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewHTTPClient(HTTPSettings{Headers: map[string]string{"X-Gateway-Key": "gw-secret"}})
+	if err != nil {
+		t.Fatalf("NewHTTPClient failed: %v", err)
+	}
+	p, err := NewOpenAIProvider("k", WithBaseURL(server.URL), WithHTTPClient(client))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := p.ExtractEndpoints(context.Background(), nil, "code"); err != nil {
+		t.Fatalf("ExtractEndpoints failed: %v", err)
+	}
+}
+
+func TestNewHTTPClient_CABundle(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	// 証明書を信頼しない場合のハンドシェイクエラーをログに出さない
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	// CA証明書を指定しない場合は自己署名の証明書を信頼しない
+	client, err := NewHTTPClient(HTTPSettings{})
+	if err != nil {
+		t.Fatalf("NewHTTPClient failed: %v", err)
+	}
+	if resp, err := client.Get(server.URL); err == nil {
+		resp.Body.Close()
+		t.Fatal("expected a certificate error without the CA bundle")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, certPEM, 0644); err != nil {
+		t.Fatalf("failed to write CA bundle: %v", err)
+	}
+	client, err = NewHTTPClient(HTTPSettings{CABundle: caFile})
+	if err != nil {
+		t.Fatalf("NewHTTPClient failed: %v", err)
+	}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request with the CA bundle failed: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestNewHTTPClient_Proxy(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		w.Write([]byte("ok"))
+	}))
+	defer proxy.Close()
+
+	client, err := NewHTTPClient(HTTPSettings{Proxy: proxy.URL})
+	if err != nil {
+		t.Fatalf("NewHTTPClient failed: %v", err)
+	}
+	resp, err := client.Get("http://llm.internal.example/v1/chat/completions")
+	if err != nil {
+		t.Fatalf("request through the proxy failed: %v", err)
+	}
+	resp.Body.Close()
+	if proxiedHost != "llm.internal.example" {
+		t.Errorf("proxied host = %q, want %q", proxiedHost, "llm.internal.example")
+	}
+}
+
+func TestNewHTTPClient_ResponseTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := NewHTTPClient(HTTPSettings{ResponseTimeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewHTTPClient failed: %v", err)
+	}
+	p, _ := NewOllamaProvider(server.URL, WithHTTPClient(client), WithMaxAttempts(1))
+	_, err = p.Verify(context.Background(), "# spec", map[string]string{"a.ts": "code"})
+	if err == nil {
+		t.Fatal("expected a timeout error but got nil")
+	}
+	if !IsRetryable(err) {
+		t.Errorf("a response timeout should be retryable: %v", err)
+	}
+}
+
+func TestNewHTTPClient_InvalidSettings(t *testing.T) {
+	if _, err := NewHTTPClient(HTTPSettings{Proxy: "://bad"}); err == nil {
+		t.Error("expected error for an invalid proxy URL")
+	}
+	if _, err := NewHTTPClient(HTTPSettings{CABundle: filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+		t.Error("expected error for a missing CA bundle")
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	os.WriteFile(empty, []byte("not a certificate"), 0644)
+	if _, err := NewHTTPClient(HTTPSettings{CABundle: empty}); err == nil {
+		t.Error("expected error for a CA bundle without certificates")
+	}
+}
+
+func TestSharedHTTPClient(t *testing.T) {
+	settings := HTTPSettings{ConnectTimeout: 7 * time.Second, Headers: map[string]string{"X-Team": "qa"}}
+	a, err := SharedHTTPClient(settings)
+	if err != nil {
+		t.Fatalf("SharedHTTPClient failed: %v", err)
+	}
+	b, _ := SharedHTTPClient(settings)
+	if a != b {
+		t.Error("the same settings should share one client")
+	}
+	c, _ := SharedHTTPClient(HTTPSettings{ConnectTimeout: 8 * time.Second})
+	if a == c {
+		t.Error("different settings should not share a client")
+	}
+
+	// プロバイダーごとのタイムアウトを設定してもトランスポートは共有する
+	cfg := newProviderConfig([]ProviderOption{WithHTTPClient(a), WithTimeout(time.Minute)})
+	client := cfg.httpClient()
+	if client.Transport != a.Transport || client.Timeout != time.Minute {
+		t.Errorf("httpClient() = %+v, want the shared transport with a 1m timeout", client)
+	}
+	if a.Timeout != 0 {
+		t.Error("the shared client should not be modified")
+	}
+}
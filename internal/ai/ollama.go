@@ -0,0 +1,151 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// ollamaDefaultBaseURL はOllamaのデフォルトのベースURL
+	ollamaDefaultBaseURL = "http://localhost:11434"
+	// ollamaDefaultModel はOllamaで使用するデフォルトのモデル
+	ollamaDefaultModel = "llama3.1"
+)
+
+// OllamaTransport はOllamaの /api/chat エンドポイントのワイヤーフォーマットを実装した Transport
+// ローカル/セルフホスト環境で動作し、APIキーを必要としない
+type OllamaTransport struct {
+	apiURL string
+	model  string
+	config ProviderConfig
+	client *http.Client
+}
+
+// NewOllamaProvider はOllamaを使用したプロバイダーを作成する
+// baseURL が空の場合は http://localhost:11434 を使用する
+func NewOllamaProvider(baseURL string, opts ...ProviderOption) (*TransportProvider, error) {
+	transport, err := NewOllamaTransport(baseURL, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewTransportProvider(transport, opts...), nil
+}
+
+// NewOllamaTransport は新しいOllamaTransportを作成する
+func NewOllamaTransport(baseURL string, opts ...ProviderOption) (*OllamaTransport, error) {
+	if baseURL == "" {
+		baseURL = ollamaDefaultBaseURL
+	}
+
+	cfg := newProviderConfig(opts)
+
+	return &OllamaTransport{
+		apiURL: strings.TrimSuffix(baseURL, "/") + "/api/chat",
+		model:  cfg.modelOr(ollamaDefaultModel),
+		config: cfg,
+		client: cfg.httpClient(),
+	}, nil
+}
+
+// Name はプロバイダー名を返す
+func (p *OllamaTransport) Name() string {
+	return "ollama"
+}
+
+// Model は使用しているモデル名を返す
+func (p *OllamaTransport) Model() string {
+	return p.model
+}
+
+// ollamaRequest はOllama /api/chat へのリクエスト
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Format   map[string]any  `json:"format,omitempty"`
+	Options  *ollamaOptions  `json:"options,omitempty"`
+}
+
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ollamaOptions struct {
+	Temperature float64 `json:"temperature"`
+	NumPredict  int     `json:"num_predict,omitempty"`
+	NumCtx      int     `json:"num_ctx,omitempty"`
+}
+
+// ollamaResponse はOllama /api/chat からのレスポンス
+type ollamaResponse struct {
+	Message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	} `json:"message"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
+	Error           string `json:"error,omitempty"`
+}
+
+// Complete はOllama APIにプロンプトを送信する
+func (p *OllamaTransport) Complete(ctx context.Context, request *CompletionRequest) (*Completion, error) {
+	req := ollamaRequest{
+		Model: p.model,
+		Messages: []ollamaMessage{
+			{Role: "user", Content: request.Prompt},
+		},
+		Stream: false,
+		Options: &ollamaOptions{
+			Temperature: p.config.temperatureOr(0.1),
+			NumPredict:  request.MaxTokens,
+			// 指定がない場合はサーバーのデフォルト（モデルより小さいことが多い）で入力が切り詰められる
+			NumCtx: p.config.contextWindowFor(p.model),
+		},
+	}
+	// Ollama 0.5以降は format にJSON Schemaを指定すると構造化出力になる
+	if request.Schema != nil {
+		req.Format = request.Schema.Schema
+	}
+
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	body, retries, err := sendWithRetry(ctx, p.client, p.config.Retry, p.config.RateLimiter, EstimateTokens(request.Prompt), p.Name(), func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", p.apiURL, bytes.NewReader(reqBody))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		return httpReq, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var ollamaResp ollamaResponse
+	if err := json.Unmarshal(body, &ollamaResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if ollamaResp.Error != "" {
+		return nil, fmt.Errorf("API error: %s", ollamaResp.Error)
+	}
+
+	if ollamaResp.Message.Content == "" {
+		return nil, fmt.Errorf("empty response from API")
+	}
+
+	return &Completion{
+		Text:       ollamaResp.Message.Content,
+		Retries:    retries,
+		Structured: request.Schema != nil,
+		Usage:      Usage{InputTokens: ollamaResp.PromptEvalCount, OutputTokens: ollamaResp.EvalCount},
+	}, nil
+}
@@ -0,0 +1,87 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOllamaProvider_Verify(t *testing.T) {
+	var gotReq ollamaRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("path = %q, want %q", r.URL.Path, "/api/chat")
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("unexpected Authorization header: %q", auth)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&gotReq); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Write([]byte(`{"message":{"role":"assistant","content":"{\"matchPercentage\": 80, \"matchedItems\": [\"a\"], \"unmatchedItems\": [], \"notes\": \"ok\"}"},"done":true}`))
+	}))
+	defer server.Close()
+
+	p, err := NewOllamaProvider(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := p.Verify(context.Background(), "# spec", map[string]string{"a.ts": "code"})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if result.MatchPercentage != 80 {
+		t.Errorf("MatchPercentage = %d, want 80", result.MatchPercentage)
+	}
+	if gotReq.Stream {
+		t.Error("expected stream to be false")
+	}
+	if gotReq.Model != ollamaDefaultModel {
+		t.Errorf("model = %q, want %q", gotReq.Model, ollamaDefaultModel)
+	}
+	if len(gotReq.Messages) != 1 || gotReq.Messages[0].Role != "user" {
+		t.Errorf("unexpected messages: %+v", gotReq.Messages)
+	}
+}
+
+func TestOllamaProvider_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"model 'llama3.1' not found"}`))
+	}))
+	defer server.Close()
+
+	p, _ := NewOllamaProvider(server.URL)
+	if _, err := p.ExtractEndpoints(context.Background(), nil, "code"); err == nil {
+		t.Error("expected error but got nil")
+	}
+}
+
+func TestOpenAICompatibleProvider_ExtractEndpoints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("path = %q, want %q", r.URL.Path, "/v1/chat/completions")
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("unexpected Authorization header: %q", auth)
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"[{\"method\":\"GET\",\"path\":\"/users\"}]"}}]}`))
+	}))
+	defer server.Close()
+
+	p, err := NewOpenAICompatibleProvider(server.URL+"/v1/", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results, err := p.ExtractEndpoints(context.Background(), &ExtractOptions{SourceType: "express"}, "code")
+	if err != nil {
+		t.Fatalf("ExtractEndpoints failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Path != "/users" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
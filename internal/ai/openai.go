@@ -5,41 +5,171 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
-const openaiAPIURL = "https://api.openai.com/v1/chat/completions"
+const (
+	openaiDefaultBaseURL = "https://api.openai.com/v1"
+	openaiDefaultModel   = "gpt-4o"
+)
+
+// openaiCompatibleDefaultModel はOpenAI互換エンドポイントで使用するデフォルトのモデル名
+// llama.cpp server や LM Studio のようにモデル名を無視するサーバーを想定している
+const openaiCompatibleDefaultModel = "local-model"
+
+// AzureOpenAIProviderName はAzure OpenAI Serviceを使用するプロバイダー名
+const AzureOpenAIProviderName = "azure-openai"
 
-// OpenAIProvider はOpenAI APIを使用したプロバイダー
-type OpenAIProvider struct {
+// azureOpenAIDefaultAPIVersion は api_version が未指定の場合に使用するAzure OpenAIのAPIバージョン
+const azureOpenAIDefaultAPIVersion = "2024-10-21"
+
+// OpenAITransport はOpenAI Chat Completions APIのワイヤーフォーマットを実装した Transport
+// OpenAI互換のエンドポイント（vLLM, LM Studio, llama.cpp server など）やAzure OpenAIにも使用する
+type OpenAITransport struct {
+	name   string
+	apiURL string
 	apiKey string
 	model  string
+	config ProviderConfig
+	client *http.Client
+
+	// APIキーを送信するヘッダー（空の場合は Authorization: Bearer）
+	apiKeyHeader string
+}
+
+// NewOpenAIProvider はOpenAI APIを使用したプロバイダーを作成する
+func NewOpenAIProvider(apiKey string, opts ...ProviderOption) (*TransportProvider, error) {
+	transport, err := NewOpenAITransport(apiKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewTransportProvider(transport, opts...), nil
 }
 
-// NewOpenAIProvider は新しいOpenAIProviderを作成する
-func NewOpenAIProvider(apiKey string) (*OpenAIProvider, error) {
+// NewOpenAITransport はOpenAI APIを使用するTransportを作成する
+func NewOpenAITransport(apiKey string, opts ...ProviderOption) (*OpenAITransport, error) {
 	if apiKey == "" {
 		return nil, fmt.Errorf("API key is required")
 	}
 
-	return &OpenAIProvider{
+	cfg := newProviderConfig(opts)
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = openaiDefaultBaseURL
+	}
+
+	return &OpenAITransport{
+		name:   "openai",
+		apiURL: strings.TrimSuffix(baseURL, "/") + "/chat/completions",
+		apiKey: apiKey,
+		model:  cfg.modelOr(openaiDefaultModel),
+		config: cfg,
+		client: cfg.httpClient(),
+	}, nil
+}
+
+// NewOpenAICompatibleProvider はOpenAI互換のエンドポイントを使用するプロバイダーを作成する
+func NewOpenAICompatibleProvider(baseURL string, apiKey string, opts ...ProviderOption) (*TransportProvider, error) {
+	transport, err := NewOpenAICompatibleTransport(baseURL, apiKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewTransportProvider(transport, opts...), nil
+}
+
+// NewOpenAICompatibleTransport はOpenAI互換のエンドポイントを使用するTransportを作成する
+// baseURL には "/chat/completions" を除いたURL（例: http://localhost:8000/v1）を指定する
+// APIキーは任意で、空の場合はAuthorizationヘッダーを送信しない
+func NewOpenAICompatibleTransport(baseURL string, apiKey string, opts ...ProviderOption) (*OpenAITransport, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("base_url is required for openai-compatible provider")
+	}
+
+	cfg := newProviderConfig(opts)
+
+	return &OpenAITransport{
+		name:   "openai-compatible",
+		apiURL: strings.TrimSuffix(baseURL, "/") + "/chat/completions",
 		apiKey: apiKey,
-		model:  "gpt-4o",
+		model:  cfg.modelOr(openaiCompatibleDefaultModel),
+		config: cfg,
+		client: cfg.httpClient(),
+	}, nil
+}
+
+// NewAzureOpenAIProvider はAzure OpenAI Serviceを使用するプロバイダーを作成する
+func NewAzureOpenAIProvider(endpoint string, deployment string, apiKey string, opts ...ProviderOption) (*TransportProvider, error) {
+	transport, err := NewAzureOpenAITransport(endpoint, deployment, apiKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewTransportProvider(transport, opts...), nil
+}
+
+// NewAzureOpenAITransport はAzure OpenAI Serviceを使用するTransportを作成する
+// endpoint にはリソースのURL（例: https://my-resource.openai.azure.com）、deployment にはデプロイ名を指定する
+// モデルはデプロイで決まるため、モデル名が未指定の場合はデプロイ名をモデル名として扱う
+func NewAzureOpenAITransport(endpoint string, deployment string, apiKey string, opts ...ProviderOption) (*OpenAITransport, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("endpoint is required for azure-openai provider")
+	}
+	if deployment == "" {
+		return nil, fmt.Errorf("deployment is required for azure-openai provider")
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required")
+	}
+
+	cfg := newProviderConfig(opts)
+	apiVersion := cfg.APIVersion
+	if apiVersion == "" {
+		apiVersion = azureOpenAIDefaultAPIVersion
+	}
+	query := url.Values{"api-version": {apiVersion}}
+
+	return &OpenAITransport{
+		name:         AzureOpenAIProviderName,
+		apiURL:       strings.TrimSuffix(endpoint, "/") + "/openai/deployments/" + url.PathEscape(deployment) + "/chat/completions?" + query.Encode(),
+		apiKey:       apiKey,
+		model:        cfg.modelOr(deployment),
+		config:       cfg,
+		client:       cfg.httpClient(),
+		apiKeyHeader: "api-key",
 	}, nil
 }
 
 // Name はプロバイダー名を返す
-func (p *OpenAIProvider) Name() string {
-	return "openai"
+func (p *OpenAITransport) Name() string {
+	return p.name
+}
+
+// Model は使用しているモデル名を返す
+func (p *OpenAITransport) Model() string {
+	return p.model
 }
 
 // openaiRequest はOpenAI APIへのリクエスト
 type openaiRequest struct {
-	Model       string          `json:"model"`
-	Messages    []openaiMessage `json:"messages"`
-	MaxTokens   int             `json:"max_tokens,omitempty"`
-	Temperature float64         `json:"temperature,omitempty"`
+	Model          string                `json:"model"`
+	Messages       []openaiMessage       `json:"messages"`
+	MaxTokens      int                   `json:"max_tokens,omitempty"`
+	Temperature    *float64              `json:"temperature,omitempty"`
+	ResponseFormat *openaiResponseFormat `json:"response_format,omitempty"`
+}
+
+// openaiResponseFormat は構造化出力の指定
+type openaiResponseFormat struct {
+	Type       string            `json:"type"`
+	JSONSchema *openaiJSONSchema `json:"json_schema,omitempty"`
+}
+
+type openaiJSONSchema struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Schema      map[string]any `json:"schema"`
+	Strict      bool           `json:"strict"`
 }
 
 type openaiMessage struct {
@@ -54,103 +184,80 @@ type openaiResponse struct {
 			Content string `json:"content"`
 		} `json:"message"`
 	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
 	Error *struct {
 		Message string `json:"message"`
 		Type    string `json:"type"`
 	} `json:"error,omitempty"`
 }
 
-// Verify はSPECとコードの一致度を検証する
-func (p *OpenAIProvider) Verify(ctx context.Context, specContent string, codeContents map[string]string) (*VerificationResult, error) {
-	return p.VerifyWithOptions(ctx, specContent, codeContents, nil)
-}
-
-// VerifyWithOptions は検証観点を指定してSPECとコードの一致度を検証する
-func (p *OpenAIProvider) VerifyWithOptions(ctx context.Context, specContent string, codeContents map[string]string, opts *VerifyOptions) (*VerificationResult, error) {
-	var prompt string
-	if opts != nil && len(opts.VerificationFocus) > 0 {
-		prompt = buildVerificationPromptWithFocus(specContent, codeContents, opts.VerificationFocus)
-	} else {
-		prompt = buildVerificationPrompt(specContent, codeContents)
-	}
-
-	text, err := p.callAPI(ctx, prompt, 2000)
-	if err != nil {
-		return nil, err
-	}
-
-	return parseVerificationResult(text)
-}
-
-// ExtractEndpoints はコードからAPIエンドポイント/ページルートを抽出する
-func (p *OpenAIProvider) ExtractEndpoints(ctx context.Context, opts *ExtractOptions, codeContent string) ([]EndpointResult, error) {
-	var prompt string
-	if opts.IsUICategory() {
-		prompt = buildUIRouteExtractionPrompt(opts.GetSourceType(), codeContent)
-	} else {
-		prompt = buildEndpointExtractionPrompt(opts.GetSourceType(), codeContent)
-	}
-
-	text, err := p.callAPI(ctx, prompt, 4000)
-	if err != nil {
-		return nil, err
-	}
-
-	return parseEndpointResult(text)
-}
-
-// callAPI はOpenAI APIを呼び出す共通関数
-func (p *OpenAIProvider) callAPI(ctx context.Context, prompt string, maxTokens int) (string, error) {
+// Complete はOpenAI互換のChat Completions APIにプロンプトを送信する
+func (p *OpenAITransport) Complete(ctx context.Context, request *CompletionRequest) (*Completion, error) {
+	temperature := p.config.temperatureOr(0.1)
 	req := openaiRequest{
 		Model:       p.model,
-		MaxTokens:   maxTokens,
-		Temperature: 0.1,
+		MaxTokens:   request.MaxTokens,
+		Temperature: &temperature,
 		Messages: []openaiMessage{
-			{Role: "user", Content: prompt},
+			{Role: "user", Content: request.Prompt},
 		},
 	}
-
-	reqBody, err := json.Marshal(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+	if request.Schema != nil {
+		req.ResponseFormat = &openaiResponseFormat{
+			Type: "json_schema",
+			JSONSchema: &openaiJSONSchema{
+				Name:        request.Schema.Name,
+				Description: request.Schema.Description,
+				Schema:      request.Schema.Schema,
+				Strict:      true,
+			},
+		}
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", openaiAPIURL, bytes.NewReader(reqBody))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
-
-	client := &http.Client{}
-	resp, err := client.Do(httpReq)
+	reqBody, err := json.Marshal(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	body, retries, err := sendWithRetry(ctx, p.client, p.config.Retry, p.config.RateLimiter, EstimateTokens(request.Prompt), p.Name(), func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", p.apiURL, bytes.NewReader(reqBody))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		switch {
+		case p.apiKey == "":
+		case p.apiKeyHeader != "":
+			httpReq.Header.Set(p.apiKeyHeader, p.apiKey)
+		default:
+			httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+		}
+		return httpReq, nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, err
 	}
 
 	var openaiResp openaiResponse
 	if err := json.Unmarshal(body, &openaiResp); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	if openaiResp.Error != nil {
-		return "", fmt.Errorf("API error: %s", openaiResp.Error.Message)
+		return nil, fmt.Errorf("API error: %s", openaiResp.Error.Message)
 	}
 
 	if len(openaiResp.Choices) == 0 {
-		return "", fmt.Errorf("empty response from API")
+		return nil, fmt.Errorf("empty response from API")
 	}
 
-	return openaiResp.Choices[0].Message.Content, nil
+	return &Completion{
+		Text:       openaiResp.Choices[0].Message.Content,
+		Retries:    retries,
+		Structured: request.Schema != nil,
+		Usage:      Usage{InputTokens: openaiResp.Usage.PromptTokens, OutputTokens: openaiResp.Usage.CompletionTokens},
+	}, nil
 }
@@ -0,0 +1,121 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAzureOpenAIProvider_Verify(t *testing.T) {
+	var gotReq openaiRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/deployments/gpt-4o-prod/chat/completions" {
+			t.Errorf("path = %q, want %q", r.URL.Path, "/openai/deployments/gpt-4o-prod/chat/completions")
+		}
+		if v := r.URL.Query().Get("api-version"); v != "2024-06-01" {
+			t.Errorf("api-version = %q, want %q", v, "2024-06-01")
+		}
+		if key := r.Header.Get("api-key"); key != "azure-key" {
+			t.Errorf("api-key header = %q, want %q", key, "azure-key")
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("unexpected Authorization header: %q", auth)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&gotReq); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"{\"matchPercentage\": 75, \"matchedItems\": [\"a\"], \"unmatchedItems\": [], \"notes\": \"ok\"}"}}],"usage":{"prompt_tokens":100,"completion_tokens":20}}`))
+	}))
+	defer server.Close()
+
+	p, err := NewProvider("azure-openai", "azure-key",
+		WithEndpoint(server.URL+"/"),
+		WithDeployment("gpt-4o-prod"),
+		WithAPIVersion("2024-06-01"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Name() != AzureOpenAIProviderName {
+		t.Errorf("name = %q, want %q", p.Name(), AzureOpenAIProviderName)
+	}
+	if p.Model() != "gpt-4o-prod" {
+		t.Errorf("model = %q, want the deployment name", p.Model())
+	}
+
+	result, err := p.Verify(context.Background(), "# spec", map[string]string{"a.ts": "code"})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if result.MatchPercentage != 75 {
+		t.Errorf("MatchPercentage = %d, want 75", result.MatchPercentage)
+	}
+	if result.Usage == nil || result.Usage.InputTokens != 100 || result.Usage.OutputTokens != 20 {
+		t.Errorf("Usage = %+v", result.Usage)
+	}
+	if gotReq.ResponseFormat == nil || gotReq.ResponseFormat.Type != "json_schema" {
+		t.Errorf("expected json_schema response format, got %+v", gotReq.ResponseFormat)
+	}
+}
+
+func TestAzureOpenAIProvider_DefaultAPIVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if v := r.URL.Query().Get("api-version"); v != azureOpenAIDefaultAPIVersion {
+			t.Errorf("api-version = %q, want %q", v, azureOpenAIDefaultAPIVersion)
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"[{\"method\":\"GET\",\"path\":\"/users\"}]"}}]}`))
+	}))
+	defer server.Close()
+
+	p, err := NewAzureOpenAIProvider(server.URL, "prod", "azure-key", WithModel("gpt-4o"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Model() != "gpt-4o" {
+		t.Errorf("model = %q, want %q", p.Model(), "gpt-4o")
+	}
+
+	results, err := p.ExtractEndpoints(context.Background(), nil, "code")
+	if err != nil {
+		t.Fatalf("ExtractEndpoints failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Path != "/users" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
+
+func TestAzureOpenAIProvider_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"code":"DeploymentNotFound","message":"The API deployment for this resource does not exist."}}`))
+	}))
+	defer server.Close()
+
+	p, _ := NewAzureOpenAIProvider(server.URL, "missing", "azure-key")
+	if _, err := p.Verify(context.Background(), "# spec", map[string]string{"a.ts": "code"}); err == nil {
+		t.Error("expected error but got nil")
+	}
+}
+
+func TestNewAzureOpenAIProvider_RequiredSettings(t *testing.T) {
+	tests := []struct {
+		name       string
+		endpoint   string
+		deployment string
+		apiKey     string
+	}{
+		{"without endpoint", "", "prod", "key"},
+		{"without deployment", "https://example.openai.azure.com", "", "key"},
+		{"without api key", "https://example.openai.azure.com", "prod", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAzureOpenAIProvider(tt.endpoint, tt.deployment, tt.apiKey); err == nil {
+				t.Error("expected error but got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package ai
+
+// EstimatedVerificationOutputTokens は1回の検証で想定する出力トークン数
+// 検証結果のJSONは通常この程度に収まるため、見積もりにはこの値を使用する
+const EstimatedVerificationOutputTokens = 500
+
+// ModelPrice はモデルの料金（USD / 100万トークン）
+type ModelPrice struct {
+	// 入力100万トークンあたりの料金
+	InputPerMTok float64
+
+	// 出力100万トークンあたりの料金
+	OutputPerMTok float64
+}
+
+// Cost は入力・出力トークン数から料金（USD）を計算する
+func (p ModelPrice) Cost(inputTokens, outputTokens int) float64 {
+	return (float64(inputTokens)*p.InputPerMTok + float64(outputTokens)*p.OutputPerMTok) / 1_000_000
+}
+
+// defaultPricing は各プロバイダーのデフォルトモデルの参考料金
+// 料金は改定されることがあるため、正確な見積もりには設定ファイルの ai.pricing で上書きする
+var defaultPricing = map[string]ModelPrice{
+	claudeDefaultModel: {InputPerMTok: 3, OutputPerMTok: 15},
+	openaiDefaultModel: {InputPerMTok: 2.5, OutputPerMTok: 10},
+	geminiDefaultModel: {InputPerMTok: 0.1, OutputPerMTok: 0.4},
+}
+
+// LookupPrice はモデルの料金を返す
+// overrides（設定ファイルの料金表）を優先し、なければ組み込みの参考料金を使用する
+// ローカル/セルフホストのプロバイダーは料金0とする
+func LookupPrice(providerName, model string, overrides map[string]ModelPrice) (ModelPrice, bool) {
+	if price, ok := overrides[model]; ok {
+		return price, true
+	}
+	if !RequiresAPIKey(providerName) {
+		return ModelPrice{}, true
+	}
+	price, ok := defaultPricing[model]
+	return price, ok
+}
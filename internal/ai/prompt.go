@@ -0,0 +1,310 @@
+package ai
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/k-totani/spec-verify/internal/i18n"
+)
+
+// promptFiles は組み込みのプロンプトテンプレート
+//
+//go:embed prompts/*.tmpl
+var promptFiles embed.FS
+
+// promptFuncs はプロンプトテンプレートで使用できる関数
+var promptFuncs = template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}
+
+// 組み込みのプロンプトテンプレート
+var (
+	defaultVerificationTemplate = mustEmbeddedTemplate("verify.tmpl")
+	defaultEvidenceTemplate     = mustEmbeddedTemplate("evidence.tmpl")
+	defaultReduceTemplate       = mustEmbeddedTemplate("reduce.tmpl")
+	endpointsTemplate           = mustEmbeddedTemplate("endpoints.tmpl")
+	uiRoutesTemplate            = mustEmbeddedTemplate("ui_routes.tmpl")
+)
+
+// PromptTemplate はプロンプトのテンプレート（Go の text/template 形式）
+type PromptTemplate struct {
+	name   string
+	source string
+	tmpl   *template.Template
+}
+
+// VerifyPromptData は検証プロンプトのテンプレートに渡す変数
+type VerifyPromptData struct {
+	// SPECのタイトル
+	Title string
+
+	// SPECのタイプ（ui, api など）
+	SpecType string
+
+	// SPECの基本情報テーブル（項目名 → 内容）
+	Metadata map[string]string
+
+	// SPECの本文（セクション単位の検証ではそのセクションのみ）
+	Spec string
+
+	// 関連コードファイル（パス順）
+	Files []CodeFile
+
+	// 関連コードを「### パス」とコードブロックで連結したもの
+	Code string
+
+	// 検証観点
+	Focus []string
+
+	// 回答に使用する言語の名前（空の場合は指示しない）
+	Language string
+}
+
+// ChunkPromptData はコードを分割して検証する場合のプロンプトのテンプレートに渡す変数
+// 根拠を抽出するプロンプトでは Files と Code はその断片のコードのみ、最終的な検証のプロンプトでは空になる
+type ChunkPromptData struct {
+	VerifyPromptData
+
+	// 断片の番号（1から。根拠の抽出のみ）
+	Index int
+
+	// 断片の数
+	Total int
+
+	// 断片ごとに見つかった実装の根拠（最終的な検証のみ）
+	Evidence []ChunkEvidence
+}
+
+// CodeFile はプロンプトに含めるコードファイル
+type CodeFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// extractPromptData はエンドポイント/ページルート抽出プロンプトのテンプレートに渡す変数
+type extractPromptData struct {
+	// ソースタイプ（express, nextjs など）
+	SourceType string
+
+	// ソースタイプに応じたフレームワークの説明
+	Framework string
+
+	// 「=== File: パス ===」で区切ったコード
+	Code string
+}
+
+// ParsePromptTemplate はテキストからプロンプトのテンプレートを作成する
+func ParsePromptTemplate(name, text string) (*PromptTemplate, error) {
+	tmpl, err := template.New(name).Funcs(promptFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prompt template %s: %w", name, err)
+	}
+	return &PromptTemplate{name: name, source: text, tmpl: tmpl}, nil
+}
+
+// samplePromptData はテンプレートの確認に使用するサンプルの値
+var samplePromptData = VerifyPromptData{
+	Title:    "title",
+	SpecType: "ui",
+	Metadata: map[string]string{"パス": "/"},
+	Spec:     "# title",
+	Files:    []CodeFile{{Path: "a.ts", Content: "code"}},
+	Code:     buildCodeSection(map[string]string{"a.ts": "code"}),
+	Focus:    getDefaultVerificationFocus(),
+	Language: "English",
+}
+
+// LoadVerificationTemplate はファイルから検証プロンプトのテンプレートを読み込む
+// 存在しない変数の参照などを早期に検出するため、サンプルの値で一度描画して確認する
+func LoadVerificationTemplate(path string) (*PromptTemplate, error) {
+	return loadPromptTemplate(path, samplePromptData)
+}
+
+// LoadEvidenceTemplate はファイルからコードの断片ごとに根拠を抽出するプロンプトのテンプレートを読み込む
+func LoadEvidenceTemplate(path string) (*PromptTemplate, error) {
+	return loadPromptTemplate(path, ChunkPromptData{VerifyPromptData: samplePromptData, Index: 1, Total: 2})
+}
+
+// LoadReduceTemplate はファイルから断片ごとの根拠で最終的な検証を行うプロンプトのテンプレートを読み込む
+func LoadReduceTemplate(path string) (*PromptTemplate, error) {
+	data := ChunkPromptData{VerifyPromptData: samplePromptData, Total: 2}
+	data.Files, data.Code = nil, ""
+	data.Evidence = []ChunkEvidence{{Item: "item", File: "a.ts", Detail: "detail"}}
+	return loadPromptTemplate(path, data)
+}
+
+// loadPromptTemplate はファイルからテンプレートを読み込み、sample で一度描画して確認する
+func loadPromptTemplate(path string, sample any) (*PromptTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompt template: %w", err)
+	}
+	t, err := ParsePromptTemplate(path, string(data))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := t.render(sample); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// DefaultVerificationTemplate は組み込みの検証プロンプトのテンプレートを返す
+func DefaultVerificationTemplate() *PromptTemplate {
+	return defaultVerificationTemplate
+}
+
+// Name はテンプレートの名前（ファイルパス）を返す
+func (t *PromptTemplate) Name() string {
+	return t.name
+}
+
+// Source はテンプレートの本文を返す
+func (t *PromptTemplate) Source() string {
+	return t.source
+}
+
+// render はテンプレートを描画する
+// テンプレートファイル末尾の改行はプロンプトに含めない
+func (t *PromptTemplate) render(data any) (string, error) {
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt template %s: %w", t.name, err)
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+// mustEmbeddedTemplate は組み込みのテンプレートを読み込む
+func mustEmbeddedTemplate(name string) *PromptTemplate {
+	data, err := promptFiles.ReadFile("prompts/" + name)
+	if err != nil {
+		panic(err)
+	}
+	t, err := ParsePromptTemplate(name, string(data))
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// sortedPaths はコードファイルのパスをソートして返す
+// 同じ入力から常に同じプロンプトになるよう、ファイルはパス順に並べる
+func sortedPaths(codeContents map[string]string) []string {
+	filePaths := make([]string, 0, len(codeContents))
+	for filePath := range codeContents {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Strings(filePaths)
+	return filePaths
+}
+
+// buildCodeSection はコードセクションを構築する共通関数
+func buildCodeSection(codeContents map[string]string) string {
+	var codeSection strings.Builder
+	for _, filePath := range sortedPaths(codeContents) {
+		codeSection.WriteString(fmt.Sprintf("\n### %s\n```\n%s\n```\n", filePath, codeContents[filePath]))
+	}
+	return codeSection.String()
+}
+
+// DefaultVerificationFocus は検証観点が設定されていない場合の観点を msg の言語で返す
+func DefaultVerificationFocus(msg *i18n.Printer) []string {
+	return []string{
+		msg.Sprintf("focus.layout"),
+		msg.Sprintf("focus.state"),
+		msg.Sprintf("focus.flow"),
+		msg.Sprintf("focus.validation"),
+		msg.Sprintf("focus.error_handling"),
+	}
+}
+
+// getDefaultVerificationFocus は組み込みのプロンプトと同じ言語（日本語）のデフォルトの検証観点を返す
+func getDefaultVerificationFocus() []string {
+	return DefaultVerificationFocus(i18n.New(i18n.Japanese))
+}
+
+// BuildVerificationPrompt は検証に使用するプロンプトを構築する
+// opts.Template が指定されていない場合は組み込みのテンプレート、検証観点が指定されていない場合はデフォルトの観点を使用する
+func BuildVerificationPrompt(specContent string, codeContents map[string]string, opts *VerifyOptions) (string, error) {
+	tmpl := defaultVerificationTemplate
+	if opts != nil && opts.Template != nil {
+		tmpl = opts.Template
+	}
+	return tmpl.render(verifyPromptData(specContent, codeContents, opts))
+}
+
+// verifyPromptData は検証プロンプトのテンプレートに渡す変数を作成する
+func verifyPromptData(specContent string, codeContents map[string]string, opts *VerifyOptions) VerifyPromptData {
+	data := VerifyPromptData{
+		Spec:  specContent,
+		Code:  buildCodeSection(codeContents),
+		Focus: getDefaultVerificationFocus(),
+	}
+	for _, filePath := range sortedPaths(codeContents) {
+		data.Files = append(data.Files, CodeFile{Path: filePath, Content: codeContents[filePath]})
+	}
+
+	if opts != nil {
+		if len(opts.VerificationFocus) > 0 {
+			data.Focus = opts.VerificationFocus
+		}
+		data.Title = opts.Title
+		data.SpecType = opts.SpecType
+		data.Metadata = opts.Metadata
+		data.Language = opts.Language
+	}
+	return data
+}
+
+// buildExtractionPrompt はカテゴリに応じたエンドポイント/ページルート抽出プロンプトを構築する
+func buildExtractionPrompt(opts *ExtractOptions, codeContent string) (string, error) {
+	data := extractPromptData{SourceType: opts.GetSourceType(), Code: codeContent}
+	if opts.IsUICategory() {
+		data.Framework = uiFrameworkHint(data.SourceType)
+		return uiRoutesTemplate.render(data)
+	}
+	data.Framework = apiFrameworkHint(data.SourceType)
+	return endpointsTemplate.render(data)
+}
+
+// apiFrameworkHint はAPIのソースタイプに応じたフレームワークの説明を返す
+func apiFrameworkHint(sourceType string) string {
+	switch sourceType {
+	case "express":
+		return "Express.js (app.get, app.post, router.get, router.post など)"
+	case "fastify":
+		return "Fastify (fastify.get, fastify.post など)"
+	case "go-echo":
+		return "Go Echo (e.GET, e.POST, g.GET など)"
+	case "go-gin":
+		return "Go Gin (r.GET, r.POST, group.GET など)"
+	case "rails":
+		return "Ruby on Rails (routes.rb, get/post/resources など)"
+	case "django":
+		return "Django REST Framework (path, urlpatterns など)"
+	case "graphql":
+		return "GraphQL (Query, Mutation, type定義)"
+	default:
+		return "自動検出"
+	}
+}
+
+// uiFrameworkHint はUIのソースタイプに応じたフレームワークの説明を返す
+func uiFrameworkHint(sourceType string) string {
+	switch sourceType {
+	case "remix":
+		return "Remix (ファイルベースルーティング)"
+	case "nextjs":
+		return "Next.js (pages または app ディレクトリルーティング)"
+	case "react-router":
+		return "React Router (createBrowserRouter, Route コンポーネント)"
+	case "vue-router":
+		return "Vue Router (routes 配列)"
+	default:
+		return "自動検出（ファイルベースルーティングまたはルーター設定から検出）"
+	}
+}
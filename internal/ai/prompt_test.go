@@ -0,0 +1,99 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildVerificationPrompt_CustomTemplate(t *testing.T) {
+	tmpl, err := ParsePromptTemplate("custom.tmpl", `{{.SpecType}}: {{.Title}} ({{index .Metadata "パス"}})
+{{range $i, $f := .Focus}}{{inc $i}}. {{$f}}
+{{end}}{{range .Files}}[{{.Path}}] {{.Content}}
+{{end}}{{.Spec}}
+`)
+	if err != nil {
+		t.Fatalf("ParsePromptTemplate failed: %v", err)
+	}
+
+	prompt, err := BuildVerificationPrompt("# ログイン", map[string]string{"b.ts": "B", "a.ts": "A"}, &VerifyOptions{
+		Template:          tmpl,
+		Title:             "ログイン",
+		SpecType:          "ui",
+		Metadata:          map[string]string{"パス": "/login"},
+		VerificationFocus: []string{"画面構成", "状態管理"},
+	})
+	if err != nil {
+		t.Fatalf("BuildVerificationPrompt failed: %v", err)
+	}
+
+	want := "ui: ログイン (/login)\n1. 画面構成\n2. 状態管理\n[a.ts] A\n[b.ts] B\n# ログイン"
+	if prompt != want {
+		t.Errorf("prompt = %q, want %q", prompt, want)
+	}
+}
+
+func TestBuildVerificationPrompt_DefaultTemplate(t *testing.T) {
+	prompt, err := BuildVerificationPrompt("# ログイン", map[string]string{"a.ts": "code"}, nil)
+	if err != nil {
+		t.Fatalf("BuildVerificationPrompt failed: %v", err)
+	}
+	for _, want := range []string{"# ログイン", "### a.ts\n```\ncode\n```", "1. 画面構成"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt does not contain %q:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestLoadVerificationTemplate(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.tmpl")
+	if err := os.WriteFile(valid, []byte("{{.Title}}\n{{.Code}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := LoadVerificationTemplate(valid)
+	if err != nil {
+		t.Fatalf("LoadVerificationTemplate failed: %v", err)
+	}
+	if tmpl.Name() != valid {
+		t.Errorf("Name() = %q, want %q", tmpl.Name(), valid)
+	}
+
+	tests := map[string]string{
+		"syntax.tmpl":  "{{.Title",
+		"unknown.tmpl": "{{.Unknown}}",
+	}
+	for name, text := range tests {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadVerificationTemplate(path); err == nil {
+			t.Errorf("LoadVerificationTemplate(%s) should fail", name)
+		}
+	}
+
+	if _, err := LoadVerificationTemplate(filepath.Join(dir, "missing.tmpl")); err == nil {
+		t.Error("LoadVerificationTemplate should fail for a missing file")
+	}
+}
+
+func TestBuildVerificationPrompt_Language(t *testing.T) {
+	code := map[string]string{"a.ts": "code"}
+	japanese, err := BuildVerificationPrompt("# spec", code, nil)
+	if err != nil {
+		t.Fatalf("BuildVerificationPrompt failed: %v", err)
+	}
+	english, err := BuildVerificationPrompt("# spec", code, &VerifyOptions{Language: "English"})
+	if err != nil {
+		t.Fatalf("BuildVerificationPrompt failed: %v", err)
+	}
+
+	if strings.Contains(japanese, "で記述してください") {
+		t.Errorf("the Japanese prompt should not include a language instruction:\n%s", japanese)
+	}
+	if !strings.HasPrefix(english, japanese+"\n") || !strings.HasSuffix(english, "Englishで記述してください。") {
+		t.Errorf("the English prompt should append a language instruction:\n%s", english)
+	}
+}
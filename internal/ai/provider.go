@@ -2,6 +2,8 @@ package ai
 
 import (
 	"context"
+	"net/http"
+	"time"
 )
 
 // VerificationResult は検証結果を表す
@@ -17,6 +19,75 @@ type VerificationResult struct {
 
 	// 補足コメント
 	Notes string `json:"notes"`
+
+	// API呼び出しの再試行回数（レート制限や過負荷による）
+	Retries int `json:"retries,omitempty"`
+
+	// 応答の解析に修復（repaired）または再質問（reasked）が必要だった場合にその方法
+	Repair string `json:"repair,omitempty"`
+
+	// APIが返したトークン使用量（再質問を含む合計）
+	Usage *Usage `json:"usage,omitempty"`
+
+	// 複数の結果を統合した場合の内訳（アンサンブル検証時のみ）
+	Consensus *Consensus `json:"consensus,omitempty"`
+
+	// 同じプロンプトを複数回実行した場合のばらつき（options.samples 指定時のみ）
+	Samples *SampleStats `json:"samples,omitempty"`
+
+	// AIプロバイダーの呼び出しに失敗し、代わりに結果を返したプロバイダー（heuristic など）
+	Fallback string `json:"fallback,omitempty"`
+
+	// 結果を返したプロバイダーとモデル
+	Provider string `json:"provider,omitempty"`
+	Model    string `json:"model,omitempty"`
+
+	// 検証にかかった時間（ミリ秒。再試行の待ち時間、再質問、分割したチャンクの検証を含む）
+	LatencyMs int64 `json:"latencyMs,omitempty"`
+
+	// API呼び出しの回数（再試行、再質問、分割したチャンクの呼び出しを含む）
+	Attempts int `json:"attempts,omitempty"`
+
+	// コンテキストウィンドウに収まらないためコードを分割して検証した場合のチャンク数
+	Chunks int `json:"chunks,omitempty"`
+}
+
+// LowerBound は一致度の下限を返す
+// 複数回のサンプリングやアンサンブル検証の場合は最小値、それ以外は MatchPercentage を返す
+func (r *VerificationResult) LowerBound() int {
+	if r.Samples != nil {
+		return r.Samples.Min
+	}
+	if r.Consensus != nil {
+		lowest := r.MatchPercentage
+		for _, m := range r.Consensus.Members {
+			if m.Error == "" {
+				lowest = min(lowest, m.MatchPercentage)
+			}
+		}
+		return lowest
+	}
+	return r.MatchPercentage
+}
+
+// Usage はAPI呼び出しのトークン使用量
+type Usage struct {
+	// 入力トークン数
+	InputTokens int `json:"inputTokens"`
+
+	// 出力トークン数
+	OutputTokens int `json:"outputTokens"`
+}
+
+// Add は使用量を加算する
+func (u *Usage) Add(other Usage) {
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+}
+
+// Total は入力と出力の合計トークン数を返す
+func (u Usage) Total() int {
+	return u.InputTokens + u.OutputTokens
 }
 
 // EndpointResult はエンドポイント抽出結果を表す
@@ -26,6 +97,9 @@ type EndpointResult struct {
 	Source      string `json:"source,omitempty"`
 	File        string `json:"file,omitempty"`
 	Description string `json:"description,omitempty"`
+
+	// 応答の解析に修復（repaired）または再質問（reasked）が必要だった場合にその方法
+	Repair string `json:"repair,omitempty"`
 }
 
 // カテゴリ定数
@@ -59,6 +133,22 @@ func (o *ExtractOptions) GetSourceType() string {
 type VerifyOptions struct {
 	// 検証観点（AIへのヒント）
 	VerificationFocus []string
+
+	// 検証プロンプトのテンプレート（nilの場合は組み込みのテンプレート）
+	Template *PromptTemplate
+
+	// コードを分割して検証する場合の、断片ごとに根拠を抽出するプロンプトと
+	// 集めた根拠で最終的な検証を行うプロンプトのテンプレート（nilの場合は組み込みのテンプレート）
+	EvidenceTemplate *PromptTemplate
+	ReduceTemplate   *PromptTemplate
+
+	// テンプレートに渡すSPECのタイトル、タイプ、基本情報
+	Title    string
+	SpecType string
+	Metadata map[string]string
+
+	// 回答に使用する言語の名前（例: English）。空の場合はプロンプトの言語（日本語）で回答する
+	Language string
 }
 
 // Provider はAIプロバイダーのインターフェース
@@ -74,18 +164,353 @@ type Provider interface {
 
 	// Name はプロバイダー名を返す
 	Name() string
+
+	// Model は使用しているモデル名を返す
+	Model() string
+}
+
+// ProviderConfig はプロバイダー生成時の追加設定
+type ProviderConfig struct {
+	// APIのベースURL（空の場合は各プロバイダーのデフォルト）
+	BaseURL string
+
+	// 使用するモデル（空の場合は各プロバイダーのデフォルト）
+	Model string
+
+	// 生成時のtemperature（nilの場合は各プロバイダーのデフォルト）
+	Temperature *float64
+
+	// 最大出力トークン数（0の場合は用途ごとのデフォルト）
+	MaxOutputTokens int
+
+	// 1リクエストあたりのタイムアウト（0の場合は無制限）
+	Timeout time.Duration
+
+	// 再試行の方針
+	Retry RetryPolicy
+
+	// 構造化出力（Claudeのtool use、OpenAIのjson_schema、GeminiのresponseSchema）を使用するか
+	StructuredOutput bool
+
+	// クライアント側のレートリミッター（nilの場合は制限しない）
+	// 同じインスタンスを共有する全てのgoroutineでスループットが制御される
+	RateLimiter *RateLimiter
+
+	// Transport に適用するミドルウェア（先頭が最も外側）
+	Middlewares []Middleware
+
+	// モデルごとのコンテキストウィンドウのトークン数（組み込みの値を上書きする）
+	// プロンプトが収まらない場合はコードを分割して検証する
+	ContextWindows map[string]int
+
+	// 通信に使用するHTTPクライアント（nilの場合はデフォルトのトランスポート）
+	// 接続プールを共有するため、タイムアウトを除いてプロバイダー間で同じクライアントを使用する
+	HTTPClient *http.Client
+
+	// Azure OpenAIのリソースのURL
+	Endpoint string
+
+	// Azure OpenAIのデプロイ名
+	Deployment string
+
+	// Azure OpenAIのAPIバージョン（空の場合はデフォルト）
+	APIVersion string
+
+	// exec プロバイダーで実行するコマンドと引数
+	Command []string
+
+	// exec プロバイダーのコマンドに追加する環境変数
+	CommandEnv map[string]string
+
+	// ヒューリスティック検証の結果と exec プロバイダーに渡すデフォルトの検証観点の言語（ja, en。空の場合は日本語）
+	Language string
+}
+
+// ProviderOption はプロバイダー生成時のオプション
+type ProviderOption func(*ProviderConfig)
+
+// WithBaseURL はAPIのベースURLを指定するオプション
+func WithBaseURL(baseURL string) ProviderOption {
+	return func(c *ProviderConfig) {
+		if baseURL != "" {
+			c.BaseURL = baseURL
+		}
+	}
+}
+
+// WithModel は使用するモデルを指定するオプション
+func WithModel(model string) ProviderOption {
+	return func(c *ProviderConfig) {
+		if model != "" {
+			c.Model = model
+		}
+	}
+}
+
+// WithTemperature は生成時のtemperatureを指定するオプション
+func WithTemperature(temperature float64) ProviderOption {
+	return func(c *ProviderConfig) {
+		c.Temperature = &temperature
+	}
+}
+
+// WithMaxOutputTokens は最大出力トークン数を指定するオプション
+func WithMaxOutputTokens(maxTokens int) ProviderOption {
+	return func(c *ProviderConfig) {
+		if maxTokens > 0 {
+			c.MaxOutputTokens = maxTokens
+		}
+	}
+}
+
+// WithTimeout は1リクエストあたりのタイムアウトを指定するオプション
+func WithTimeout(timeout time.Duration) ProviderOption {
+	return func(c *ProviderConfig) {
+		if timeout > 0 {
+			c.Timeout = timeout
+		}
+	}
+}
+
+// WithMaxAttempts は再試行を含む最大試行回数を指定するオプション
+func WithMaxAttempts(attempts int) ProviderOption {
+	return func(c *ProviderConfig) {
+		if attempts > 0 {
+			c.Retry.MaxAttempts = attempts
+		}
+	}
+}
+
+// WithRetryPolicy は再試行の方針を指定するオプション
+func WithRetryPolicy(policy RetryPolicy) ProviderOption {
+	return func(c *ProviderConfig) {
+		c.Retry = policy
+	}
+}
+
+// WithStructuredOutput は構造化出力を使用するかを指定するオプション
+// 構造化出力に対応していないOpenAI互換サーバーなどでは無効にする
+func WithStructuredOutput(enabled bool) ProviderOption {
+	return func(c *ProviderConfig) {
+		c.StructuredOutput = enabled
+	}
+}
+
+// WithRateLimiter はクライアント側のレートリミッターを指定するオプション
+func WithRateLimiter(limiter *RateLimiter) ProviderOption {
+	return func(c *ProviderConfig) {
+		c.RateLimiter = limiter
+	}
+}
+
+// WithContextWindows はモデルごとのコンテキストウィンドウのトークン数を指定するオプション
+func WithContextWindows(windows map[string]int) ProviderOption {
+	return func(c *ProviderConfig) {
+		c.ContextWindows = windows
+	}
+}
+
+// WithMiddleware は Transport に適用するミドルウェアを追加するオプション
+// 先に追加したミドルウェアほど外側（呼び出し元に近い側）になる
+func WithMiddleware(middlewares ...Middleware) ProviderOption {
+	return func(c *ProviderConfig) {
+		c.Middlewares = append(c.Middlewares, middlewares...)
+	}
+}
+
+// WithHTTPClient は通信に使用するHTTPクライアントを指定するオプション
+func WithHTTPClient(client *http.Client) ProviderOption {
+	return func(c *ProviderConfig) {
+		c.HTTPClient = client
+	}
+}
+
+// WithEndpoint はAzure OpenAIのリソースのURLを指定するオプション
+func WithEndpoint(endpoint string) ProviderOption {
+	return func(c *ProviderConfig) {
+		if endpoint != "" {
+			c.Endpoint = endpoint
+		}
+	}
+}
+
+// WithDeployment はAzure OpenAIのデプロイ名を指定するオプション
+func WithDeployment(deployment string) ProviderOption {
+	return func(c *ProviderConfig) {
+		if deployment != "" {
+			c.Deployment = deployment
+		}
+	}
+}
+
+// WithAPIVersion はAzure OpenAIのAPIバージョンを指定するオプション
+func WithAPIVersion(apiVersion string) ProviderOption {
+	return func(c *ProviderConfig) {
+		if apiVersion != "" {
+			c.APIVersion = apiVersion
+		}
+	}
+}
+
+// WithCommand は exec プロバイダーで実行するコマンドと引数を指定するオプション
+func WithCommand(command []string) ProviderOption {
+	return func(c *ProviderConfig) {
+		if len(command) > 0 {
+			c.Command = command
+		}
+	}
+}
+
+// WithCommandEnv は exec プロバイダーのコマンドに追加する環境変数を指定するオプション
+func WithCommandEnv(env map[string]string) ProviderOption {
+	return func(c *ProviderConfig) {
+		c.CommandEnv = env
+	}
+}
+
+// WithLanguage はヒューリスティック検証の結果と exec プロバイダーのデフォルトの検証観点の言語（ja, en）を指定するオプション
+func WithLanguage(language string) ProviderOption {
+	return func(c *ProviderConfig) {
+		c.Language = language
+	}
+}
+
+func newProviderConfig(opts []ProviderOption) ProviderConfig {
+	cfg := ProviderConfig{
+		Retry:            DefaultRetryPolicy(),
+		StructuredOutput: true,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// modelOr は設定されたモデル、未設定の場合はデフォルトを返す
+func (c ProviderConfig) modelOr(defaultModel string) string {
+	if c.Model != "" {
+		return c.Model
+	}
+	return defaultModel
+}
+
+// maxTokensOr は設定された最大出力トークン数、未設定の場合はデフォルトを返す
+func (c ProviderConfig) maxTokensOr(defaultMaxTokens int) int {
+	if c.MaxOutputTokens > 0 {
+		return c.MaxOutputTokens
+	}
+	return defaultMaxTokens
+}
+
+// temperatureOr は設定されたtemperature、未設定の場合はデフォルトを返す
+func (c ProviderConfig) temperatureOr(defaultTemperature float64) float64 {
+	if c.Temperature != nil {
+		return *c.Temperature
+	}
+	return defaultTemperature
+}
+
+// schemaFor は構造化出力が有効な場合にスキーマを返す
+func (c ProviderConfig) schemaFor(schema *ResponseSchema) *ResponseSchema {
+	if !c.StructuredOutput {
+		return nil
+	}
+	return schema
+}
+
+// httpClient は設定に基づいたHTTPクライアントを返す
+// HTTPClient が指定されている場合はそのトランスポートを共有し、タイムアウトのみプロバイダーの設定を使用する
+func (c ProviderConfig) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return &http.Client{Timeout: c.Timeout}
+	}
+	client := *c.HTTPClient
+	client.Timeout = c.Timeout
+	return &client
+}
+
+// RequiresAPIKey はプロバイダーの利用にAPIキーが必須かを返す
+// ローカル/セルフホストのプロバイダーはAPIキーなしで利用できる
+func RequiresAPIKey(providerName string) bool {
+	switch providerName {
+	case "ollama", "openai-compatible", "openai_compatible", "local", ReplayProviderName, HeuristicProviderName, ExecProviderName:
+		return false
+	default:
+		return true
+	}
+}
+
+// CanonicalProviderName はエイリアスを含むプロバイダー名を Provider.Name() と同じ正式名に変換する
+func CanonicalProviderName(providerName string) string {
+	switch providerName {
+	case "openai", "gpt":
+		return "openai"
+	case "gemini", "google":
+		return "gemini"
+	case "ollama":
+		return "ollama"
+	case "openai-compatible", "openai_compatible", "local":
+		return "openai-compatible"
+	case AzureOpenAIProviderName, "azure":
+		return AzureOpenAIProviderName
+	case ReplayProviderName:
+		return ReplayProviderName
+	case HeuristicProviderName:
+		return HeuristicProviderName
+	case ExecProviderName:
+		return ExecProviderName
+	default:
+		return "claude"
+	}
+}
+
+// DefaultModel はプロバイダーのデフォルトのモデル名を返す
+func DefaultModel(providerName string) string {
+	switch CanonicalProviderName(providerName) {
+	case "openai":
+		return openaiDefaultModel
+	case "gemini":
+		return geminiDefaultModel
+	case "ollama":
+		return ollamaDefaultModel
+	case "openai-compatible":
+		return openaiCompatibleDefaultModel
+	case AzureOpenAIProviderName:
+		// デプロイ名がモデル名になるため固定のデフォルトはない
+		return ""
+	case ReplayProviderName:
+		return replayModel
+	case HeuristicProviderName:
+		return heuristicModel
+	case ExecProviderName:
+		return execDefaultModel
+	default:
+		return claudeDefaultModel
+	}
 }
 
 // NewProvider は指定されたプロバイダーのインスタンスを作成する
-func NewProvider(providerName string, apiKey string) (Provider, error) {
+func NewProvider(providerName string, apiKey string, opts ...ProviderOption) (Provider, error) {
+	cfg := newProviderConfig(opts)
+
 	switch providerName {
 	case "claude", "anthropic":
-		return NewClaudeProvider(apiKey)
+		return NewClaudeProvider(apiKey, opts...)
 	case "openai", "gpt":
-		return NewOpenAIProvider(apiKey)
+		return NewOpenAIProvider(apiKey, opts...)
 	case "gemini", "google":
-		return NewGeminiProvider(apiKey)
+		return NewGeminiProvider(apiKey, opts...)
+	case "ollama":
+		return NewOllamaProvider(cfg.BaseURL, opts...)
+	case "openai-compatible", "openai_compatible", "local":
+		return NewOpenAICompatibleProvider(cfg.BaseURL, apiKey, opts...)
+	case AzureOpenAIProviderName, "azure":
+		return NewAzureOpenAIProvider(cfg.Endpoint, cfg.Deployment, apiKey, opts...)
+	case HeuristicProviderName:
+		return NewHeuristicProvider(opts...), nil
+	case ExecProviderName:
+		return NewExecProvider(cfg.Command, opts...)
 	default:
-		return NewClaudeProvider(apiKey)
+		return NewClaudeProvider(apiKey, opts...)
 	}
 }
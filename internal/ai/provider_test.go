@@ -1,7 +1,13 @@
 package ai
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewProvider(t *testing.T) {
@@ -54,6 +60,20 @@ func TestNewProvider(t *testing.T) {
 			wantName:     "gemini",
 			wantErr:      false,
 		},
+		{
+			name:         "ollama provider without api key",
+			providerName: "ollama",
+			apiKey:       "",
+			wantName:     "ollama",
+			wantErr:      false,
+		},
+		{
+			name:         "exec provider without command",
+			providerName: "exec",
+			apiKey:       "",
+			wantName:     "",
+			wantErr:      true,
+		},
 		{
 			name:         "default to claude",
 			providerName: "unknown",
@@ -149,3 +169,194 @@ func TestNewGeminiProvider(t *testing.T) {
 		}
 	})
 }
+
+func TestNewProvider_OpenAICompatible(t *testing.T) {
+	t.Run("with base url and no api key", func(t *testing.T) {
+		p, err := NewProvider("openai-compatible", "", WithBaseURL("http://localhost:8000/v1"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if p.Name() != "openai-compatible" {
+			t.Errorf("name = %q, want %q", p.Name(), "openai-compatible")
+		}
+	})
+
+	t.Run("without base url", func(t *testing.T) {
+		_, err := NewProvider("openai-compatible", "")
+		if err == nil {
+			t.Error("expected error but got nil")
+		}
+	})
+}
+
+func TestRequiresAPIKey(t *testing.T) {
+	tests := []struct {
+		providerName string
+		want         bool
+	}{
+		{"claude", true},
+		{"openai", true},
+		{"gemini", true},
+		{"ollama", false},
+		{"openai-compatible", false},
+		{"azure-openai", true},
+		{"exec", false},
+		{"unknown", true},
+	}
+
+	for _, tt := range tests {
+		if got := RequiresAPIKey(tt.providerName); got != tt.want {
+			t.Errorf("RequiresAPIKey(%q) = %v, want %v", tt.providerName, got, tt.want)
+		}
+	}
+}
+
+func TestProviderModel(t *testing.T) {
+	tests := []struct {
+		name         string
+		providerName string
+		opts         []ProviderOption
+		wantModel    string
+	}{
+		{"claude default", "claude", nil, claudeDefaultModel},
+		{"openai default", "openai", nil, openaiDefaultModel},
+		{"gemini default", "gemini", nil, geminiDefaultModel},
+		{"ollama default", "ollama", nil, ollamaDefaultModel},
+		{"claude override", "claude", []ProviderOption{WithModel("claude-opus-4-20250514")}, "claude-opus-4-20250514"},
+		{"openai override", "openai", []ProviderOption{WithModel("gpt-4.1")}, "gpt-4.1"},
+		{"empty model keeps default", "gemini", []ProviderOption{WithModel("")}, geminiDefaultModel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewProvider(tt.providerName, "test-key", tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if p.Model() != tt.wantModel {
+				t.Errorf("model = %q, want %q", p.Model(), tt.wantModel)
+			}
+		})
+	}
+}
+
+func TestClaudeProvider_RequestSettings(t *testing.T) {
+	var gotReq claudeRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("path = %q, want %q", r.URL.Path, "/v1/messages")
+		}
+		if err := json.NewDecoder(r.Body).Decode(&gotReq); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Write([]byte(`{"content":[{"type":"text","text":"{\"matchPercentage\": 70}"}]}`))
+	}))
+	defer server.Close()
+
+	p, err := NewClaudeProvider("test-key",
+		WithBaseURL(server.URL),
+		WithModel("claude-test"),
+		WithTemperature(0),
+		WithMaxOutputTokens(1234),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := p.Verify(context.Background(), "# spec", map[string]string{"a.ts": "code"}); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if gotReq.Model != "claude-test" {
+		t.Errorf("model = %q, want %q", gotReq.Model, "claude-test")
+	}
+	if gotReq.MaxTokens != 1234 {
+		t.Errorf("max_tokens = %d, want 1234", gotReq.MaxTokens)
+	}
+	if gotReq.Temperature == nil || *gotReq.Temperature != 0 {
+		t.Errorf("temperature = %v, want 0", gotReq.Temperature)
+	}
+}
+
+func TestProviderTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte(`{"choices":[{"message":{"content":"[]"}}]}`))
+	}))
+	defer server.Close()
+
+	p, err := NewOpenAIProvider("test-key", WithBaseURL(server.URL), WithTimeout(20*time.Millisecond), WithMaxAttempts(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := p.ExtractEndpoints(context.Background(), nil, "code"); err == nil {
+		t.Error("expected timeout error but got nil")
+	}
+}
+
+func TestBuildVerificationPrompt_Deterministic(t *testing.T) {
+	code := map[string]string{"b.ts": "B", "a.ts": "A", "c.ts": "C"}
+	first, err := BuildVerificationPrompt("# spec", code, nil)
+	if err != nil {
+		t.Fatalf("BuildVerificationPrompt failed: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		if got, _ := BuildVerificationPrompt("# spec", code, nil); got != first {
+			t.Fatal("prompt should not depend on map iteration order")
+		}
+	}
+	if strings.Index(first, "### a.ts") > strings.Index(first, "### b.ts") {
+		t.Error("code files should be sorted by path")
+	}
+}
+
+func TestProviderUsage(t *testing.T) {
+	const result = `{\"matchPercentage\": 80}`
+	tests := []struct {
+		name     string
+		response string
+		newFunc  func(url string) (Provider, error)
+	}{
+		{
+			name:     "claude",
+			response: `{"content":[{"type":"text","text":"` + result + `"}],"usage":{"input_tokens":120,"output_tokens":30}}`,
+			newFunc:  func(url string) (Provider, error) { return NewClaudeProvider("k", WithBaseURL(url)) },
+		},
+		{
+			name:     "openai",
+			response: `{"choices":[{"message":{"content":"` + result + `"}}],"usage":{"prompt_tokens":120,"completion_tokens":30}}`,
+			newFunc:  func(url string) (Provider, error) { return NewOpenAIProvider("k", WithBaseURL(url)) },
+		},
+		{
+			name:     "gemini",
+			response: `{"candidates":[{"content":{"parts":[{"text":"` + result + `"}]}}],"usageMetadata":{"promptTokenCount":120,"candidatesTokenCount":30}}`,
+			newFunc:  func(url string) (Provider, error) { return NewGeminiProvider("k", WithBaseURL(url)) },
+		},
+		{
+			name:     "ollama",
+			response: `{"message":{"role":"assistant","content":"` + result + `"},"prompt_eval_count":120,"eval_count":30}`,
+			newFunc:  func(url string) (Provider, error) { return NewOllamaProvider(url) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			p, err := tt.newFunc(server.URL)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := p.Verify(context.Background(), "# spec", map[string]string{"a.ts": "code"})
+			if err != nil {
+				t.Fatalf("Verify failed: %v", err)
+			}
+			if got.Usage == nil || got.Usage.InputTokens != 120 || got.Usage.OutputTokens != 30 {
+				t.Errorf("Usage = %+v, want 120/30", got.Usage)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package ai
+
+import (
+	"context"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// RateLimit はプロバイダーごとのレート制限の設定
+type RateLimit struct {
+	// 1分あたりの最大リクエスト数（0の場合は無制限）
+	RequestsPerMinute int
+
+	// 1分あたりの最大入力トークン数（0の場合は無制限）
+	InputTokensPerMinute int
+}
+
+// RateLimiter はトークンバケット方式のクライアント側レートリミッター
+// 1つのインスタンスを複数のgoroutineで共有して全体のスループットを制御する
+type RateLimiter struct {
+	mu       sync.Mutex
+	requests *tokenBucket
+	tokens   *tokenBucket
+	now      func() time.Time
+}
+
+// NewRateLimiter は新しいRateLimiterを作成する
+// 制限が1つも設定されていない場合はnilを返す（nilのRateLimiterは待機しない）
+func NewRateLimiter(limit RateLimit) *RateLimiter {
+	if limit.RequestsPerMinute <= 0 && limit.InputTokensPerMinute <= 0 {
+		return nil
+	}
+
+	l := &RateLimiter{now: time.Now}
+	start := l.now()
+	if limit.RequestsPerMinute > 0 {
+		l.requests = newTokenBucket(limit.RequestsPerMinute, start)
+	}
+	if limit.InputTokensPerMinute > 0 {
+		l.tokens = newTokenBucket(limit.InputTokensPerMinute, start)
+	}
+	return l
+}
+
+// Wait は1リクエスト分（入力トークン数 inputTokens）の枠が空くまで待機する
+// 枠は先に予約されるため、待機中の他のgoroutineに追い越されることはない
+func (l *RateLimiter) Wait(ctx context.Context, inputTokens int) error {
+	if l == nil {
+		return nil
+	}
+
+	delay := l.reserve(inputTokens)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve は枠を予約し、利用可能になるまでの待機時間を返す
+func (l *RateLimiter) reserve(inputTokens int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var delay time.Duration
+	if l.requests != nil {
+		delay = max(delay, l.requests.reserve(1, now))
+	}
+	if l.tokens != nil {
+		delay = max(delay, l.tokens.reserve(inputTokens, now))
+	}
+	return delay
+}
+
+// tokenBucket は1分あたり perMinute 個のトークンが補充されるバケット
+type tokenBucket struct {
+	capacity float64
+	perSec   float64
+	// 利用可能なトークン数（予約により負になることがある）
+	available float64
+	last      time.Time
+}
+
+func newTokenBucket(perMinute int, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		capacity:  float64(perMinute),
+		perSec:    float64(perMinute) / 60,
+		available: float64(perMinute),
+		last:      now,
+	}
+}
+
+// reserve は n 個のトークンを予約し、利用可能になるまでの待機時間を返す
+// 1回の要求が容量を超える場合は容量分として扱う（永久に待たないようにするため）
+func (b *tokenBucket) reserve(n int, now time.Time) time.Duration {
+	b.available = min(b.capacity, b.available+now.Sub(b.last).Seconds()*b.perSec)
+	b.last = now
+
+	need := min(float64(n), b.capacity)
+	b.available -= need
+	if b.available >= 0 {
+		return 0
+	}
+	return time.Duration(-b.available / b.perSec * float64(time.Second))
+}
+
+// EstimateTokens はテキストのトークン数を概算する
+// ASCII文字は約4文字で1トークン、それ以外（日本語など）は1文字1トークンとして数える
+func EstimateTokens(text string) int {
+	var ascii, other int
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+		i += size
+	}
+	return (ascii+3)/4 + other
+}
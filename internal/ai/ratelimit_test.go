@@ -0,0 +1,137 @@
+package ai
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewRateLimiter_Unlimited(t *testing.T) {
+	l := NewRateLimiter(RateLimit{})
+	if l != nil {
+		t.Fatal("expected nil limiter when no limits are set")
+	}
+	// nilのRateLimiterは待機しない
+	if err := l.Wait(context.Background(), 1000000); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRateLimiter_RequestsPerMinute(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(RateLimit{RequestsPerMinute: 60})
+	l.now = func() time.Time { return now }
+	l.requests.last = now
+
+	// バケット容量（60リクエスト）までは待機しない
+	for i := 0; i < 60; i++ {
+		if d := l.reserve(0); d != 0 {
+			t.Fatalf("request %d: delay = %v, want 0", i, d)
+		}
+	}
+
+	// 61件目は1秒（60rpm = 1req/s）待つ
+	if d := l.reserve(0); d != time.Second {
+		t.Errorf("delay = %v, want 1s", d)
+	}
+	// 62件目は予約済みの分を含めて2秒待つ
+	if d := l.reserve(0); d != 2*time.Second {
+		t.Errorf("delay = %v, want 2s", d)
+	}
+
+	// 時間が経過すると補充される
+	now = now.Add(10 * time.Second)
+	if d := l.reserve(0); d != 0 {
+		t.Errorf("delay after refill = %v, want 0", d)
+	}
+}
+
+func TestRateLimiter_InputTokensPerMinute(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(RateLimit{InputTokensPerMinute: 6000})
+	l.now = func() time.Time { return now }
+	l.tokens.last = now
+
+	if d := l.reserve(5000); d != 0 {
+		t.Errorf("delay = %v, want 0", d)
+	}
+	// 残り1000トークンに対して3000トークン要求 → 2000トークン不足 = 20秒（100 tokens/s）
+	if d := l.reserve(3000); d != 20*time.Second {
+		t.Errorf("delay = %v, want 20s", d)
+	}
+}
+
+func TestRateLimiter_OversizedRequestIsCapped(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(RateLimit{InputTokensPerMinute: 600})
+	l.now = func() time.Time { return now }
+	l.tokens.last = now
+
+	// 容量を超える要求は容量分として扱い、満タンなら即時に通す
+	if d := l.reserve(10000); d != 0 {
+		t.Errorf("delay = %v, want 0", d)
+	}
+	// 次の要求はバケットが満タンになるまで（1分）待つ
+	if d := l.reserve(10000); d != time.Minute {
+		t.Errorf("delay = %v, want 1m", d)
+	}
+}
+
+func TestRateLimiter_SharedAcrossGoroutines(t *testing.T) {
+	l := NewRateLimiter(RateLimit{RequestsPerMinute: 6000}) // 100 req/s
+	ctx := context.Background()
+
+	// 容量を使い切る
+	for i := 0; i < 6000; i++ {
+		l.reserve(0)
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := l.Wait(ctx, 0); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 5リクエスト分の枠（50ms）が空くまで待たされる
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("elapsed = %v, want >= 40ms", elapsed)
+	}
+}
+
+func TestRateLimiter_WaitCanceled(t *testing.T) {
+	l := NewRateLimiter(RateLimit{RequestsPerMinute: 1})
+	l.reserve(0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx, 0); err == nil {
+		t.Error("expected context error but got nil")
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"abcd", 1},
+		{"abcde", 2},
+		{"ログイン", 4},
+		{"ログイン form", 6},
+	}
+
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.text); got != tt.want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
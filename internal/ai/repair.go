@@ -0,0 +1,344 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// 応答の復旧方法（VerificationResult.Repair, EndpointResult.Repair）
+const (
+	// RepairFixed は不正なJSONを修復して解析したことを表す
+	RepairFixed = "repaired"
+	// RepairReasked は解析に失敗したため再質問して正しいJSONを得たことを表す
+	RepairReasked = "reasked"
+)
+
+// requestVerification は検証プロンプトを送信して結果を解析する
+// 解析できない場合は不正な出力とエラー内容を送り返して1回だけ再質問する
+func requestVerification(ctx context.Context, transport Transport, cfg ProviderConfig, prompt string) (*VerificationResult, error) {
+	schema := cfg.schemaFor(verificationResponseSchema)
+	resp, err := complete(ctx, transport, cfg, prompt, verificationMaxTokens, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	result, parseErr := decodeVerificationResult(resp)
+	if parseErr == nil {
+		result.Retries = resp.Retries
+		result.Usage = &resp.Usage
+		return result, nil
+	}
+
+	// 解析できなかった応答と再質問もトークンを消費しているため、失敗した場合も使用量を返す
+	reask, err := complete(ctx, transport, cfg, buildReaskPrompt(resp.Text, parseErr, verificationFormatHint), verificationMaxTokens, schema)
+	if err != nil {
+		return nil, withUsage(fmt.Errorf("%w (re-ask failed: %v)", parseErr, err), resp.Usage)
+	}
+	usage := resp.Usage
+	usage.Add(reask.Usage)
+	result, err = decodeVerificationResult(reask)
+	if err != nil {
+		return nil, withUsage(fmt.Errorf("%w (re-ask also failed: %v)", parseErr, err), usage)
+	}
+	result.Repair = RepairReasked
+	result.Retries = resp.Retries + reask.Retries
+	result.Usage = &usage
+	return result, nil
+}
+
+// requestEndpoints はエンドポイント抽出プロンプトを送信して結果を解析する
+// 解析できない場合は requestVerification と同様に1回だけ再質問する
+func requestEndpoints(ctx context.Context, transport Transport, cfg ProviderConfig, prompt string) ([]EndpointResult, error) {
+	schema := cfg.schemaFor(endpointResponseSchema)
+	resp, err := complete(ctx, transport, cfg, prompt, 4000, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	results, parseErr := decodeEndpointResult(resp)
+	if parseErr == nil {
+		return results, nil
+	}
+
+	reask, err := complete(ctx, transport, cfg, buildReaskPrompt(resp.Text, parseErr, endpointFormatHint), 4000, schema)
+	if err != nil {
+		return nil, fmt.Errorf("%w (re-ask failed: %v)", parseErr, err)
+	}
+	results, err = decodeEndpointResult(reask)
+	if err != nil {
+		return nil, fmt.Errorf("%w (re-ask also failed: %v)", parseErr, err)
+	}
+	return markRepaired(results, RepairReasked), nil
+}
+
+// markRepaired は応答の復旧方法を全ての抽出結果に設定する
+func markRepaired(results []EndpointResult, repair string) []EndpointResult {
+	for i := range results {
+		results[i].Repair = repair
+	}
+	return results
+}
+
+const verificationFormatHint = `{
+  "matchPercentage": <0-100の数値>,
+  "matchedItems": ["一致している項目"],
+  "unmatchedItems": ["一致していない項目"],
+  "notes": "補足コメント"
+}`
+
+const endpointFormatHint = `[
+  {"method": "GET", "path": "/api/users", "file": "ファイルパス", "description": "説明"}
+]`
+
+// buildReaskPrompt は不正な出力を修正させる再質問プロンプトを構築する
+func buildReaskPrompt(invalidOutput string, parseErr error, formatHint string) string {
+	return fmt.Sprintf(`あなたが先ほど返した出力はJSONとして解析できませんでした。
+
+## 解析エラー
+%s
+
+## あなたの出力
+%s
+
+内容は変えずに、以下の形式の正しいJSONのみを出力してください。説明文やコードブロックは不要です。
+
+%s
+`, parseErr, invalidOutput, formatHint)
+}
+
+// unmarshalVerificationResult は検証結果のJSONを寛容に解析する
+// matchPercentage が "85%" のような文字列や小数の場合も数値に変換する
+func unmarshalVerificationResult(jsonStr string) (*VerificationResult, error) {
+	var raw struct {
+		MatchPercentage json.RawMessage `json:"matchPercentage"`
+		MatchedItems    []string        `json:"matchedItems"`
+		UnmatchedItems  []string        `json:"unmatchedItems"`
+		Notes           string          `json:"notes"`
+	}
+	if err := json.Unmarshal([]byte(jsonStr), &raw); err != nil {
+		return nil, err
+	}
+
+	percentage, err := coercePercentage(raw.MatchPercentage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VerificationResult{
+		MatchPercentage: percentage,
+		MatchedItems:    raw.MatchedItems,
+		UnmatchedItems:  raw.UnmatchedItems,
+		Notes:           raw.Notes,
+	}, nil
+}
+
+// coercePercentage は 85, 85.5, "85", "85%" のいずれの形式も0-100の整数に変換する
+func coercePercentage(raw json.RawMessage) (int, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return 0, nil
+	}
+
+	var value float64
+	if err := json.Unmarshal(raw, &value); err != nil {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return 0, fmt.Errorf("invalid matchPercentage: %s", raw)
+		}
+		s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "%"))
+		value, err = strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid matchPercentage: %s", raw)
+		}
+	}
+
+	return int(math.Round(math.Max(0, math.Min(100, value)))), nil
+}
+
+// unmarshalEndpointResult はエンドポイント抽出結果のJSONを解析する
+// 配列と {"endpoints": [...]} の両方の形式を受け付ける
+func unmarshalEndpointResult(jsonStr string) ([]EndpointResult, error) {
+	var results []EndpointResult
+	err := json.Unmarshal([]byte(jsonStr), &results)
+	if err == nil {
+		return results, nil
+	}
+
+	var wrapped struct {
+		Endpoints []EndpointResult `json:"endpoints"`
+	}
+	if werr := json.Unmarshal([]byte(jsonStr), &wrapped); werr == nil && wrapped.Endpoints != nil {
+		return wrapped.Endpoints, nil
+	}
+	return nil, err
+}
+
+// repairJSON はテキスト中の最初のJSON値（opens のいずれかの文字で始まるもの）を取り出して修復する
+// 末尾カンマの除去と、途中で切れたJSONの文字列・配列・オブジェクトの補完を行う
+// JSONの開始文字が見つからない場合は false を返す
+func repairJSON(text string, opens string) (string, bool) {
+	start := strings.IndexAny(text, opens)
+	if start < 0 {
+		return "", false
+	}
+
+	var out strings.Builder
+	var stack []byte
+	inString := false
+	escaped := false
+
+	for i := start; i < len(text); i++ {
+		c := text[i]
+
+		if inString {
+			out.WriteByte(c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			stack = append(stack, '}')
+		case '[':
+			stack = append(stack, ']')
+		case '}', ']':
+			if len(stack) == 0 || stack[len(stack)-1] != c {
+				// 対応しない閉じ括弧は無視する
+				continue
+			}
+			stack = stack[:len(stack)-1]
+			trimTrailingComma(&out)
+			out.WriteByte(c)
+			if len(stack) == 0 {
+				// 最初のバランスしたJSON値で打ち切る
+				return out.String(), true
+			}
+			continue
+		}
+		out.WriteByte(c)
+	}
+
+	// 途中で切れている場合は閉じる
+	s := out.String()
+	if inString {
+		if escaped {
+			s = s[:len(s)-1]
+		}
+		s += `"`
+	}
+	s = trimDanglingValue(s)
+	for i := len(stack) - 1; i >= 0; i-- {
+		s += string(stack[i])
+	}
+	return s, true
+}
+
+// trimTrailingComma は出力末尾の空白とカンマを取り除く
+func trimTrailingComma(out *strings.Builder) {
+	s := strings.TrimRight(out.String(), " \t\r\n")
+	if !strings.HasSuffix(s, ",") {
+		return
+	}
+	s = strings.TrimRight(strings.TrimSuffix(s, ","), " \t\r\n")
+	out.Reset()
+	out.WriteString(s)
+}
+
+// trimDanglingValue は途中で切れたJSONの末尾から、完結していない要素（末尾カンマ、値のないキー、
+// 途中で切れたリテラル）を取り除く
+func trimDanglingValue(s string) string {
+	s = strings.TrimRight(s, " \t\r\n")
+
+	// 途中で切れた true/false/null/数値
+	if i := strings.LastIndexAny(s, `,:[{"`); i >= 0 && i < len(s)-1 {
+		tail := strings.TrimSpace(s[i+1:])
+		if !isCompleteLiteral(tail) {
+			s = strings.TrimRight(s[:i+1], " \t\r\n")
+		}
+	}
+
+	s = strings.TrimSuffix(s, ",")
+	switch {
+	case strings.HasSuffix(s, ":"):
+		// 値のないキー（{"a": 1, "b":）はキーごと取り除く
+		s = trimLastString(strings.TrimRight(strings.TrimSuffix(s, ":"), " \t\r\n"))
+	case strings.HasSuffix(s, `"`) && isDanglingKey(s):
+		// 区切りのないキー（{"a": 1, "b"）も取り除く
+		s = trimLastString(s)
+	}
+	return s
+}
+
+// trimLastString は末尾の文字列リテラルと、その直前のカンマを取り除く
+func trimLastString(s string) string {
+	if !strings.HasSuffix(s, `"`) {
+		return s
+	}
+	j := strings.LastIndex(s[:len(s)-1], `"`)
+	if j < 0 {
+		return s
+	}
+	return strings.TrimSuffix(strings.TrimRight(s[:j], " \t\r\n"), ",")
+}
+
+// isCompleteLiteral はJSONのリテラル（true/false/null/数値）として完結しているかを返す
+func isCompleteLiteral(s string) bool {
+	switch s {
+	case "true", "false", "null":
+		return true
+	}
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+// isDanglingKey は末尾の文字列がオブジェクトのキー位置にあるかを返す
+func isDanglingKey(s string) bool {
+	j := strings.LastIndex(s[:len(s)-1], `"`)
+	if j < 0 {
+		return false
+	}
+	prev := strings.TrimRight(s[:j], " \t\r\n")
+	if prev == "" {
+		return false
+	}
+	switch prev[len(prev)-1] {
+	case '{':
+		return true
+	case ',':
+		// カンマの前を遡り、直近の未閉じ括弧がオブジェクトならキー位置
+		depth := 0
+		inString := false
+		for i := len(prev) - 1; i >= 0; i-- {
+			c := prev[i]
+			if c == '"' && (i == 0 || prev[i-1] != '\\') {
+				inString = !inString
+				continue
+			}
+			if inString {
+				continue
+			}
+			switch c {
+			case '}', ']':
+				depth++
+			case '{', '[':
+				if depth == 0 {
+					return c == '{'
+				}
+				depth--
+			}
+		}
+	}
+	return false
+}
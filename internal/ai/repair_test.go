@@ -0,0 +1,222 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRepairJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "trailing commas",
+			input: `{"a": [1, 2,], "b": "x",}`,
+			want:  `{"a": [1, 2], "b": "x"}`,
+		},
+		{
+			name:  "first balanced object",
+			input: `結果は以下です {"a": 1} 補足: {"b": 2}`,
+			want:  `{"a": 1}`,
+		},
+		{
+			name:  "braces inside strings",
+			input: `{"notes": "use {id} and ]"}`,
+			want:  `{"notes": "use {id} and ]"}`,
+		},
+		{
+			name:  "truncated array",
+			input: `{"matchedItems": ["a", "b"`,
+			want:  `{"matchedItems": ["a", "b"]}`,
+		},
+		{
+			name:  "truncated string",
+			input: `{"matchedItems": ["a", "ログイ`,
+			want:  `{"matchedItems": ["a", "ログイ"]}`,
+		},
+		{
+			name:  "truncated after key",
+			input: `{"matchPercentage": 80, "notes":`,
+			want:  `{"matchPercentage": 80}`,
+		},
+		{
+			name:  "truncated key",
+			input: `{"matchPercentage": 80, "notes"`,
+			want:  `{"matchPercentage": 80}`,
+		},
+		{
+			name:  "truncated literal",
+			input: `{"matchPercentage": 80, "ok": tr`,
+			want:  `{"matchPercentage": 80}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := repairJSON(tt.input, "[{")
+			if !ok {
+				t.Fatal("repairJSON returned false")
+			}
+			if got != tt.want {
+				t.Errorf("repairJSON() = %s, want %s", got, tt.want)
+			}
+			if !json.Valid([]byte(got)) {
+				t.Errorf("repaired JSON is invalid: %s", got)
+			}
+		})
+	}
+
+	if _, ok := repairJSON("JSONなし", "{"); ok {
+		t.Error("expected false when no JSON is present")
+	}
+}
+
+func TestCoercePercentage(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want int
+	}{
+		{`85`, 85},
+		{`85.6`, 86},
+		{`"85"`, 85},
+		{`"85%"`, 85},
+		{`" 70 % "`, 70},
+		{`120`, 100},
+		{`null`, 0},
+	}
+
+	for _, tt := range tests {
+		got, err := coercePercentage(json.RawMessage(tt.raw))
+		if err != nil {
+			t.Errorf("coercePercentage(%s) error: %v", tt.raw, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("coercePercentage(%s) = %d, want %d", tt.raw, got, tt.want)
+		}
+	}
+
+	if _, err := coercePercentage(json.RawMessage(`"high"`)); err == nil {
+		t.Error("expected error for non-numeric percentage")
+	}
+}
+
+func TestParseVerificationResult_Repair(t *testing.T) {
+	t.Run("valid JSON is not marked", func(t *testing.T) {
+		result, err := parseVerificationResult(`{"matchPercentage": 90}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Repair != "" {
+			t.Errorf("Repair = %q, want empty", result.Repair)
+		}
+	})
+
+	t.Run("truncated code block", func(t *testing.T) {
+		result, err := parseVerificationResult("```json\n{\"matchPercentage\": \"85%\", \"matchedItems\": [\"ログイン\", \"ログア")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.MatchPercentage != 85 {
+			t.Errorf("MatchPercentage = %d, want 85", result.MatchPercentage)
+		}
+		if len(result.MatchedItems) != 2 {
+			t.Errorf("MatchedItems = %v, want 2 items", result.MatchedItems)
+		}
+		if result.Repair != RepairFixed {
+			t.Errorf("Repair = %q, want %q", result.Repair, RepairFixed)
+		}
+	})
+}
+
+func TestParseEndpointResult_Repair(t *testing.T) {
+	results, err := parseEndpointResult(`[{"method": "GET", "path": "/users",}, {"method": "POST", "pa`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[0].Path != "/users" || results[1].Method != "POST" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+	if results[0].Repair != RepairFixed || results[1].Repair != RepairFixed {
+		t.Errorf("Repair = %q / %q, want %q", results[0].Repair, results[1].Repair, RepairFixed)
+	}
+}
+
+func TestRequestEndpoints_Reask(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Write([]byte(`{"choices":[{"message":{"content":"エンドポイントは GET /users です"}}]}`))
+			return
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"[{\"method\": \"GET\", \"path\": \"/users\"}]"}}]}`))
+	}))
+	defer server.Close()
+
+	p, _ := NewOpenAIProvider("test-key", WithBaseURL(server.URL))
+	results, err := p.ExtractEndpoints(context.Background(), &ExtractOptions{SourceType: "express"}, "app.get('/users')")
+	if err != nil {
+		t.Fatalf("ExtractEndpoints failed: %v", err)
+	}
+	if calls.Load() != 2 || len(results) != 1 || results[0].Repair != RepairReasked {
+		t.Errorf("calls = %d, results = %+v, want one re-asked result", calls.Load(), results)
+	}
+}
+
+func TestRequestVerification_Reask(t *testing.T) {
+	var calls atomic.Int32
+	var reaskPrompt string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openaiRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if calls.Add(1) == 1 {
+			w.Write([]byte(`{"choices":[{"message":{"content":"一致度は高いです"}}]}`))
+			return
+		}
+		reaskPrompt = req.Messages[0].Content
+		w.Write([]byte(`{"choices":[{"message":{"content":"{\"matchPercentage\": 75}"}}]}`))
+	}))
+	defer server.Close()
+
+	p, _ := NewOpenAIProvider("test-key", WithBaseURL(server.URL))
+	result, err := p.Verify(context.Background(), "# spec", map[string]string{"a.ts": "code"})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, want 2", calls.Load())
+	}
+	if result.MatchPercentage != 75 {
+		t.Errorf("MatchPercentage = %d, want 75", result.MatchPercentage)
+	}
+	if result.Repair != RepairReasked {
+		t.Errorf("Repair = %q, want %q", result.Repair, RepairReasked)
+	}
+	if !strings.Contains(reaskPrompt, "一致度は高いです") {
+		t.Error("re-ask prompt should contain the invalid output")
+	}
+}
+
+func TestRequestVerification_ReaskFails(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{"choices":[{"message":{"content":"解析できない応答"}}]}`))
+	}))
+	defer server.Close()
+
+	p, _ := NewOpenAIProvider("test-key", WithBaseURL(server.URL))
+	if _, err := p.Verify(context.Background(), "# spec", map[string]string{"a.ts": "code"}); err == nil {
+		t.Fatal("expected error but got nil")
+	}
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, want 2 (only one re-ask)", calls.Load())
+	}
+}
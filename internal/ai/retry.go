@@ -0,0 +1,380 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrorKind はAPIエラーの分類
+type ErrorKind string
+
+const (
+	// ErrorKindRateLimit はレート制限（429）
+	ErrorKindRateLimit ErrorKind = "rate_limit"
+	// ErrorKindOverloaded はプロバイダー側の過負荷（529, 503）
+	ErrorKindOverloaded ErrorKind = "overloaded"
+	// ErrorKindServer は一時的なサーバーエラー（500, 502, 504）
+	ErrorKindServer ErrorKind = "server"
+	// ErrorKindNetwork は接続断などの一時的なネットワークエラー
+	ErrorKindNetwork ErrorKind = "network"
+	// ErrorKindAuth は認証・認可エラー（401, 403）
+	ErrorKindAuth ErrorKind = "auth"
+	// ErrorKindBadRequest はリクエスト内容の誤り（400, 404, 413, 422 など）
+	ErrorKindBadRequest ErrorKind = "bad_request"
+	// ErrorKindUnknown は上記以外
+	ErrorKindUnknown ErrorKind = "unknown"
+)
+
+// APIError はAIプロバイダーAPIの呼び出しエラー
+type APIError struct {
+	// プロバイダー名
+	Provider string
+
+	// HTTPステータスコード（ネットワークエラーの場合は0）
+	StatusCode int
+
+	// エラーの分類
+	Kind ErrorKind
+
+	// エラーメッセージ（レスポンスボディなど）
+	Message string
+
+	// サーバーから指示された待機時間（なければ0）
+	RetryAfter time.Duration
+
+	// 試行回数
+	Attempts int
+
+	// 元のエラー（ネットワークエラーの場合）
+	Err error
+}
+
+// Error はエラーメッセージを返す
+func (e *APIError) Error() string {
+	var msg string
+	if e.StatusCode > 0 {
+		msg = fmt.Sprintf("API error (status %d, %s): %s", e.StatusCode, e.Kind, e.Message)
+	} else {
+		msg = fmt.Sprintf("API error (%s): %s", e.Kind, e.Message)
+	}
+	if e.Attempts > 1 {
+		msg += fmt.Sprintf(" (after %d attempts)", e.Attempts)
+	}
+	return msg
+}
+
+// Unwrap は元のエラーを返す
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable は再試行で回復する可能性があるエラーかを返す
+func (e *APIError) Retryable() bool {
+	switch e.Kind {
+	case ErrorKindRateLimit, ErrorKindOverloaded, ErrorKindServer, ErrorKindNetwork:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsRetryable はエラーが再試行可能なAPIエラーかを返す
+func IsRetryable(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Retryable()
+}
+
+// UsageError は一部のAPI呼び出しがトークンを消費した後に失敗した検証のエラー
+// 分割したチャンクの途中や再質問で失敗した場合に、それまでの使用量を集計や予算に含めるために使用する
+type UsageError struct {
+	// 失敗の原因
+	Err error
+
+	// 失敗するまでに消費したトークン使用量
+	Usage Usage
+
+	// 失敗するまでのAPI呼び出しの回数と所要時間（ミリ秒。プロバイダーが設定する）
+	Attempts  int
+	LatencyMs int64
+}
+
+// Error は失敗の原因のメッセージを返す
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap は失敗の原因を返す
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+// AsUsageError は err に含まれる UsageError を返す（トークンを消費する前に失敗した場合は nil）
+func AsUsageError(err error) *UsageError {
+	var usageErr *UsageError
+	if errors.As(err, &usageErr) {
+		return usageErr
+	}
+	return nil
+}
+
+// withUsage は err に失敗するまでに消費した usage を加える
+// err が既に UsageError の場合は使用量を合算する。使用量が0の場合は err をそのまま返す
+func withUsage(err error, usage Usage) error {
+	if usageErr, ok := err.(*UsageError); ok {
+		usage.Add(usageErr.Usage)
+		err = usageErr.Err
+	}
+	if usage.Total() == 0 {
+		return err
+	}
+	return &UsageError{Err: err, Usage: usage}
+}
+
+// RetryPolicy は再試行の方針
+type RetryPolicy struct {
+	// 最大試行回数（初回を含む。1以下の場合は再試行しない）
+	MaxAttempts int
+
+	// バックオフの初期待機時間
+	BaseDelay time.Duration
+
+	// バックオフの最大待機時間
+	MaxDelay time.Duration
+
+	// Retry-After がこの値を超える場合は待たずに失敗とする
+	MaxRetryAfter time.Duration
+}
+
+// DefaultRetryPolicy はデフォルトの再試行方針を返す
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:   4,
+		BaseDelay:     time.Second,
+		MaxDelay:      30 * time.Second,
+		MaxRetryAfter: 2 * time.Minute,
+	}
+}
+
+// backoff は試行回数に応じたジッター付き指数バックオフの待機時間を返す
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	// equal jitter: delay/2 〜 delay の範囲でばらつかせる
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + rand.N(half+1)
+}
+
+// sendWithRetry はHTTPリクエストを送信し、再試行可能なエラーの場合はバックオフして再送する
+// newRequest は試行ごとに新しいリクエストを生成する（ボディを再利用できないため）
+// limiter が指定されている場合は各試行の前に inputTokens 分の枠を待機する
+// 成功時はレスポンスボディと再試行回数（初回成功なら0）を返す
+func sendWithRetry(ctx context.Context, client *http.Client, policy RetryPolicy, limiter *RateLimiter, inputTokens int, provider string, newRequest func() (*http.Request, error)) ([]byte, int, error) {
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := limiter.Wait(ctx, inputTokens); err != nil {
+			return nil, attempt - 1, err
+		}
+
+		body, apiErr, err := sendOnce(ctx, client, provider, newRequest)
+		if err != nil {
+			return nil, attempt - 1, err
+		}
+		if apiErr == nil {
+			return body, attempt - 1, nil
+		}
+
+		apiErr.Attempts = attempt
+		if !apiErr.Retryable() || attempt >= maxAttempts {
+			return nil, attempt - 1, apiErr
+		}
+
+		delay := policy.backoff(attempt)
+		if apiErr.RetryAfter > 0 {
+			if policy.MaxRetryAfter > 0 && apiErr.RetryAfter > policy.MaxRetryAfter {
+				return nil, attempt - 1, apiErr
+			}
+			delay = apiErr.RetryAfter
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, attempt - 1, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// sendOnce はHTTPリクエストを1回送信する
+// APIエラー（再試行判定の対象）は2番目、それ以外の致命的なエラーは3番目の戻り値で返す
+func sendOnce(ctx context.Context, client *http.Client, provider string, newRequest func() (*http.Request, error)) ([]byte, *APIError, error) {
+	httpReq, err := newRequest()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		return nil, &APIError{
+			Provider: provider,
+			Kind:     ErrorKindNetwork,
+			Message:  fmt.Sprintf("failed to send request: %v", err),
+			Err:      err,
+		}, nil
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		return nil, &APIError{
+			Provider:   provider,
+			StatusCode: resp.StatusCode,
+			Kind:       ErrorKindNetwork,
+			Message:    fmt.Sprintf("failed to read response: %v", err),
+			Err:        err,
+		}, nil
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		return body, nil, nil
+	}
+
+	return nil, &APIError{
+		Provider:   provider,
+		StatusCode: resp.StatusCode,
+		Kind:       classifyStatus(resp.StatusCode),
+		Message:    string(body),
+		RetryAfter: parseRetryAfter(resp.Header, body, time.Now()),
+	}, nil
+}
+
+// classifyStatus はHTTPステータスコードからエラーを分類する
+func classifyStatus(status int) ErrorKind {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return ErrorKindRateLimit
+	case status == 529 || status == http.StatusServiceUnavailable:
+		return ErrorKindOverloaded
+	case status == http.StatusInternalServerError ||
+		status == http.StatusBadGateway ||
+		status == http.StatusGatewayTimeout ||
+		status == http.StatusRequestTimeout:
+		return ErrorKindServer
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrorKindAuth
+	case status >= 400 && status < 500:
+		return ErrorKindBadRequest
+	default:
+		return ErrorKindUnknown
+	}
+}
+
+// parseRetryAfter はレスポンスから待機時間を取得する
+// 標準の Retry-After に加え、各プロバイダー固有のヘッダー/ボディも参照する
+func parseRetryAfter(header http.Header, body []byte, now time.Time) time.Duration {
+	// 標準の Retry-After（秒数 または HTTP-date）
+	if v := header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.ParseFloat(v, 64); err == nil && secs >= 0 {
+			return time.Duration(secs * float64(time.Second))
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return positiveDuration(t.Sub(now))
+		}
+	}
+
+	// OpenAI: retry-after-ms
+	if v := header.Get("Retry-After-Ms"); v != "" {
+		if ms, err := strconv.ParseFloat(v, 64); err == nil && ms >= 0 {
+			return time.Duration(ms * float64(time.Millisecond))
+		}
+	}
+
+	// Anthropic: anthropic-ratelimit-*-reset（RFC 3339）
+	var wait time.Duration
+	for _, name := range []string{
+		"Anthropic-Ratelimit-Requests-Reset",
+		"Anthropic-Ratelimit-Tokens-Reset",
+		"Anthropic-Ratelimit-Input-Tokens-Reset",
+		"Anthropic-Ratelimit-Output-Tokens-Reset",
+	} {
+		if v := header.Get(name); v != "" {
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				wait = max(wait, positiveDuration(t.Sub(now)))
+			}
+		}
+	}
+
+	// OpenAI: x-ratelimit-reset-*（"1s", "6m0s", "20ms" 形式）
+	for _, name := range []string{
+		"X-Ratelimit-Reset-Requests",
+		"X-Ratelimit-Reset-Tokens",
+	} {
+		if v := header.Get(name); v != "" {
+			if d, err := time.ParseDuration(v); err == nil {
+				wait = max(wait, d)
+			}
+		}
+	}
+	if wait > 0 {
+		return wait
+	}
+
+	// Gemini: ボディの google.rpc.RetryInfo
+	return parseGeminiRetryDelay(body)
+}
+
+// parseGeminiRetryDelay はGeminiのエラーボディから retryDelay を取得する
+func parseGeminiRetryDelay(body []byte) time.Duration {
+	var errBody struct {
+		Error struct {
+			Details []struct {
+				Type       string `json:"@type"`
+				RetryDelay string `json:"retryDelay"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errBody); err != nil {
+		return 0
+	}
+	for _, d := range errBody.Error.Details {
+		if !strings.HasSuffix(d.Type, "google.rpc.RetryInfo") || d.RetryDelay == "" {
+			continue
+		}
+		if delay, err := time.ParseDuration(d.RetryDelay); err == nil {
+			return delay
+		}
+	}
+	return 0
+}
+
+// positiveDuration は負の値を0に丸める
+func positiveDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
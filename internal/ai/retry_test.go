@@ -0,0 +1,235 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetryPolicy はテスト用に待機時間を短くした再試行方針
+func fastRetryPolicy(attempts int) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:   attempts,
+		BaseDelay:     time.Millisecond,
+		MaxDelay:      5 * time.Millisecond,
+		MaxRetryAfter: time.Second,
+	}
+}
+
+func TestClassifyStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   ErrorKind
+	}{
+		{429, ErrorKindRateLimit},
+		{529, ErrorKindOverloaded},
+		{503, ErrorKindOverloaded},
+		{500, ErrorKindServer},
+		{502, ErrorKindServer},
+		{504, ErrorKindServer},
+		{401, ErrorKindAuth},
+		{403, ErrorKindAuth},
+		{400, ErrorKindBadRequest},
+		{404, ErrorKindBadRequest},
+		{413, ErrorKindBadRequest},
+		{302, ErrorKindUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := classifyStatus(tt.status); got != tt.want {
+			t.Errorf("classifyStatus(%d) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		body   string
+		want   time.Duration
+	}{
+		{
+			name:   "retry-after seconds",
+			header: map[string]string{"Retry-After": "7"},
+			want:   7 * time.Second,
+		},
+		{
+			name:   "retry-after http date",
+			header: map[string]string{"Retry-After": now.Add(30 * time.Second).Format(http.TimeFormat)},
+			want:   30 * time.Second,
+		},
+		{
+			name:   "retry-after-ms",
+			header: map[string]string{"Retry-After-Ms": "1500"},
+			want:   1500 * time.Millisecond,
+		},
+		{
+			name: "anthropic reset headers use the latest reset",
+			header: map[string]string{
+				"Anthropic-Ratelimit-Requests-Reset": now.Add(5 * time.Second).Format(time.RFC3339),
+				"Anthropic-Ratelimit-Tokens-Reset":   now.Add(20 * time.Second).Format(time.RFC3339),
+			},
+			want: 20 * time.Second,
+		},
+		{
+			name:   "openai reset header",
+			header: map[string]string{"X-Ratelimit-Reset-Tokens": "6m0s"},
+			want:   6 * time.Minute,
+		},
+		{
+			name: "gemini retry info",
+			body: `{"error":{"code":429,"details":[{"@type":"type.googleapis.com/google.rpc.RetryInfo","retryDelay":"12s"}]}}`,
+			want: 12 * time.Second,
+		},
+		{
+			name: "no hint",
+			body: `{"error":{"message":"too many requests"}}`,
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for k, v := range tt.header {
+				header.Set(k, v)
+			}
+			if got := parseRetryAfter(header, []byte(tt.body), now); got != tt.want {
+				t.Errorf("parseRetryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt := 1; attempt <= 6; attempt++ {
+		want := min(100*time.Millisecond<<(attempt-1), time.Second)
+		got := policy.backoff(attempt)
+		if got < want/2 || got > want {
+			t.Errorf("backoff(%d) = %v, want between %v and %v", attempt, got, want/2, want)
+		}
+	}
+}
+
+func TestSendWithRetry(t *testing.T) {
+	t.Run("retries rate limit then succeeds", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Write([]byte("ok"))
+		}))
+		defer server.Close()
+
+		body, retries, err := sendWithRetry(context.Background(), server.Client(), fastRetryPolicy(4), nil, 0, "test", newTestRequest(server.URL))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(body) != "ok" {
+			t.Errorf("body = %q, want %q", body, "ok")
+		}
+		if retries != 2 {
+			t.Errorf("retries = %d, want 2", retries)
+		}
+	})
+
+	t.Run("does not retry auth errors", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		_, _, err := sendWithRetry(context.Background(), server.Client(), fastRetryPolicy(4), nil, 0, "test", newTestRequest(server.URL))
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Kind != ErrorKindAuth {
+			t.Fatalf("expected auth APIError, got %v", err)
+		}
+		if calls.Load() != 1 {
+			t.Errorf("calls = %d, want 1", calls.Load())
+		}
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(529)
+		}))
+		defer server.Close()
+
+		_, retries, err := sendWithRetry(context.Background(), server.Client(), fastRetryPolicy(3), nil, 0, "test", newTestRequest(server.URL))
+		if !IsRetryable(err) {
+			t.Fatalf("expected retryable error, got %v", err)
+		}
+		if calls.Load() != 3 {
+			t.Errorf("calls = %d, want 3", calls.Load())
+		}
+		if retries != 2 {
+			t.Errorf("retries = %d, want 2", retries)
+		}
+	})
+
+	t.Run("gives up when retry-after exceeds limit", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		_, _, err := sendWithRetry(context.Background(), server.Client(), fastRetryPolicy(4), nil, 0, "test", newTestRequest(server.URL))
+		if err == nil {
+			t.Fatal("expected error but got nil")
+		}
+		if calls.Load() != 1 {
+			t.Errorf("calls = %d, want 1", calls.Load())
+		}
+	})
+}
+
+func TestProviderRetriesAreReported(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(529)
+			w.Write([]byte(`{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`))
+			return
+		}
+		w.Write([]byte(`{"content":[{"type":"text","text":"{\"matchPercentage\": 90}"}]}`))
+	}))
+	defer server.Close()
+
+	p, err := NewClaudeProvider("test-key", WithBaseURL(server.URL), WithRetryPolicy(fastRetryPolicy(3)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := p.Verify(context.Background(), "# spec", map[string]string{"a.ts": "code"})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if result.Retries != 1 {
+		t.Errorf("Retries = %d, want 1", result.Retries)
+	}
+}
+
+func newTestRequest(url string) func() (*http.Request, error) {
+	return func() (*http.Request, error) {
+		return http.NewRequest("POST", url, nil)
+	}
+}
@@ -0,0 +1,120 @@
+package ai
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SampleStats は同じプロンプトを複数回実行した際の一致度のばらつき
+type SampleStats struct {
+	// 各サンプルの一致度（失敗したサンプルは含まない）
+	Scores []int `json:"scores"`
+
+	// 失敗したサンプル数
+	Failed int `json:"failed,omitempty"`
+
+	// 一致度の最小値
+	Min int `json:"min"`
+
+	// 一致度の最大値
+	Max int `json:"max"`
+
+	// 一致度の標準偏差
+	StdDev float64 `json:"stdDev"`
+
+	// 一部のサンプルにのみ現れた一致/不一致項目と出現回数
+	UnstableMatched   []ItemVote `json:"unstableMatched,omitempty"`
+	UnstableUnmatched []ItemVote `json:"unstableUnmatched,omitempty"`
+
+	// 標準偏差が閾値を超えており、結果が安定していないか
+	Unstable bool `json:"unstable"`
+}
+
+// CombineSamples は同じプロバイダーで複数回検証した結果を1つに統合する
+// 一致度は中央値、一致/不一致項目は過半数のサンプルに現れたものを採用する
+// 失敗したサンプルの results は nil とし、errs に同じ位置のエラーを渡す
+// 標準偏差が unstableThreshold を超える場合は Unstable を立てる（0以下の場合は判定しない）
+func CombineSamples(results []*VerificationResult, errs []error, unstableThreshold float64) (*VerificationResult, error) {
+	var succeeded []*VerificationResult
+	var scores []int
+	var failures []string
+	for i, r := range results {
+		if r == nil {
+			if i < len(errs) && errs[i] != nil {
+				failures = append(failures, errs[i].Error())
+			}
+			continue
+		}
+		succeeded = append(succeeded, r)
+		scores = append(scores, r.MatchPercentage)
+	}
+	if len(succeeded) == 0 {
+		return nil, fmt.Errorf("all %d samples failed: %s", len(results), strings.Join(failures, "; "))
+	}
+
+	n := len(succeeded)
+	matchedVotes := countVotes(succeeded, func(r *VerificationResult) []string { return r.MatchedItems })
+	unmatchedVotes := countVotes(succeeded, func(r *VerificationResult) []string { return r.UnmatchedItems })
+
+	stats := &SampleStats{
+		Scores:            scores,
+		Failed:            len(results) - n,
+		Min:               slices.Min(scores),
+		Max:               slices.Max(scores),
+		StdDev:            stdDev(scores),
+		UnstableMatched:   partialVotes(matchedVotes, n),
+		UnstableUnmatched: partialVotes(unmatchedVotes, n),
+	}
+	stats.Unstable = unstableThreshold > 0 && stats.StdDev > unstableThreshold
+
+	combined := &VerificationResult{
+		MatchPercentage: median(scores),
+		MatchedItems:    itemsWithVotes(matchedVotes, n/2+1),
+		UnmatchedItems:  itemsWithVotes(unmatchedVotes, n/2+1),
+		Samples:         stats,
+	}
+
+	// 備考はサンプルごとに異なる表現になりやすいため、重複を除いて連結する
+	seen := make(map[string]bool)
+	var notes []string
+	var usage Usage
+	hasUsage := false
+	for _, r := range succeeded {
+		if note := strings.TrimSpace(r.Notes); note != "" && !seen[note] {
+			seen[note] = true
+			notes = append(notes, note)
+		}
+		combined.Retries += r.Retries
+		combined.Attempts += r.Attempts
+		// 代替プロバイダーの結果を含む場合は統合結果も代替として扱う（キャッシュに保存しない）
+		if combined.Fallback == "" {
+			combined.Fallback = r.Fallback
+		}
+		// サンプルは並列に呼び出すため、最も遅いサンプルの時間を全体の時間とする
+		combined.LatencyMs = max(combined.LatencyMs, r.LatencyMs)
+		if combined.Provider == "" {
+			combined.Provider, combined.Model = r.Provider, r.Model
+		}
+		if r.Usage != nil {
+			usage.Add(*r.Usage)
+			hasUsage = true
+		}
+	}
+	combined.Notes = strings.Join(notes, "\n")
+	if hasUsage {
+		combined.Usage = &usage
+	}
+	return combined, nil
+}
+
+// partialVotes は全サンプルには現れなかった項目を返す
+func partialVotes(votes []ItemVote, n int) []ItemVote {
+	var partial []ItemVote
+	for _, v := range votes {
+		if v.Votes < n {
+			partial = append(partial, v)
+		}
+	}
+	return partial
+}
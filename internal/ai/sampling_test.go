@@ -0,0 +1,98 @@
+package ai
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCombineSamples(t *testing.T) {
+	results := []*VerificationResult{
+		{MatchPercentage: 90, MatchedItems: []string{"ログイン", "ログアウト"}, Notes: "概ね一致", Usage: &Usage{InputTokens: 100, OutputTokens: 10}},
+		{MatchPercentage: 70, MatchedItems: []string{"ログイン"}, UnmatchedItems: []string{"ログアウト"}, Notes: "概ね一致"},
+		{MatchPercentage: 85, MatchedItems: []string{"ログイン", "ログアウト"}, Retries: 2, Usage: &Usage{InputTokens: 100, OutputTokens: 20}},
+	}
+
+	got, err := CombineSamples(results, nil, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.MatchPercentage != 85 {
+		t.Errorf("MatchPercentage = %d, want median 85", got.MatchPercentage)
+	}
+	if !reflect.DeepEqual(got.MatchedItems, []string{"ログイン", "ログアウト"}) {
+		t.Errorf("MatchedItems = %v", got.MatchedItems)
+	}
+	if len(got.UnmatchedItems) != 0 {
+		t.Errorf("UnmatchedItems = %v, want none", got.UnmatchedItems)
+	}
+	if got.Notes != "概ね一致" {
+		t.Errorf("Notes = %q, want deduplicated note", got.Notes)
+	}
+	if got.Retries != 2 || got.Usage == nil || got.Usage.Total() != 230 {
+		t.Errorf("Retries = %d, Usage = %+v", got.Retries, got.Usage)
+	}
+
+	s := got.Samples
+	if s == nil {
+		t.Fatal("Samples is nil")
+	}
+	if s.Min != 70 || s.Max != 90 || !reflect.DeepEqual(s.Scores, []int{90, 70, 85}) {
+		t.Errorf("Samples = %+v", s)
+	}
+	if !s.Unstable {
+		t.Errorf("expected unstable with stddev %.1f", s.StdDev)
+	}
+	if !reflect.DeepEqual(s.UnstableMatched, []ItemVote{{Item: "ログアウト", Votes: 2}}) {
+		t.Errorf("UnstableMatched = %+v", s.UnstableMatched)
+	}
+	if !reflect.DeepEqual(s.UnstableUnmatched, []ItemVote{{Item: "ログアウト", Votes: 1}}) {
+		t.Errorf("UnstableUnmatched = %+v", s.UnstableUnmatched)
+	}
+	if got.LowerBound() != 70 {
+		t.Errorf("LowerBound = %d, want 70", got.LowerBound())
+	}
+}
+
+func TestCombineSamples_Failures(t *testing.T) {
+	results := []*VerificationResult{nil, {MatchPercentage: 60}}
+	errs := []error{errors.New("timeout"), nil}
+
+	got, err := CombineSamples(results, errs, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Samples.Failed != 1 || got.Samples.Unstable {
+		t.Errorf("Samples = %+v", got.Samples)
+	}
+
+	// 代替プロバイダーの結果を含む場合は統合結果にも Fallback を設定する
+	results[1].Fallback = "openai"
+	if got, _ := CombineSamples(results, errs, 10); got.Fallback != "openai" {
+		t.Errorf("Fallback = %q, want openai", got.Fallback)
+	}
+
+	if _, err := CombineSamples([]*VerificationResult{nil}, []error{errors.New("timeout")}, 10); err == nil {
+		t.Error("expected error when all samples fail")
+	}
+}
+
+func TestLowerBound(t *testing.T) {
+	single := &VerificationResult{MatchPercentage: 80}
+	if single.LowerBound() != 80 {
+		t.Errorf("LowerBound = %d, want 80", single.LowerBound())
+	}
+
+	ensemble := &VerificationResult{
+		MatchPercentage: 80,
+		Consensus: &Consensus{Members: []MemberResult{
+			{MatchPercentage: 80},
+			{MatchPercentage: 65},
+			{Error: "failed"},
+		}},
+	}
+	if ensemble.LowerBound() != 65 {
+		t.Errorf("LowerBound = %d, want 65 (failed members ignored)", ensemble.LowerBound())
+	}
+}
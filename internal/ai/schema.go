@@ -0,0 +1,137 @@
+package ai
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ResponseSchema はプロバイダーの構造化出力機能に渡すレスポンススキーマ
+type ResponseSchema struct {
+	// スキーマ名（Claudeのツール名、OpenAIのjson_schema名）
+	Name string
+
+	// スキーマの説明
+	Description string
+
+	// JSON Schema
+	Schema map[string]any
+}
+
+// verificationResponseSchema は VerificationResult のスキーマ
+var verificationResponseSchema = &ResponseSchema{
+	Name:        "report_verification",
+	Description: "SPECとコードの一致度の評価結果を報告する",
+	Schema: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"matchPercentage": map[string]any{
+				"type":        "integer",
+				"description": "一致度（0-100）",
+				"minimum":     0,
+				"maximum":     100,
+			},
+			"matchedItems": map[string]any{
+				"type":        "array",
+				"description": "一致している項目",
+				"items":       map[string]any{"type": "string"},
+			},
+			"unmatchedItems": map[string]any{
+				"type":        "array",
+				"description": "一致していない項目",
+				"items":       map[string]any{"type": "string"},
+			},
+			"notes": map[string]any{
+				"type":        "string",
+				"description": "補足コメント（未実装の機能や改善点など）",
+			},
+		},
+		"required":             []string{"matchPercentage", "matchedItems", "unmatchedItems", "notes"},
+		"additionalProperties": false,
+	},
+}
+
+// endpointResponseSchema は []EndpointResult のスキーマ
+// 構造化出力はトップレベルにオブジェクトを要求するプロバイダーがあるため endpoints キーで包む
+var endpointResponseSchema = &ResponseSchema{
+	Name:        "report_endpoints",
+	Description: "コードから抽出したAPIエンドポイント/ページルートを報告する",
+	Schema: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"endpoints": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"method":      map[string]any{"type": "string"},
+						"path":        map[string]any{"type": "string"},
+						"file":        map[string]any{"type": "string"},
+						"description": map[string]any{"type": "string"},
+					},
+					"required":             []string{"method", "path", "file", "description"},
+					"additionalProperties": false,
+				},
+			},
+		},
+		"required":             []string{"endpoints"},
+		"additionalProperties": false,
+	},
+}
+
+// geminiSchema はJSON SchemaをGeminiの responseSchema（OpenAPIサブセット）形式に変換する
+// 型名を大文字にし、Geminiが受け付けないキーワードを除去する
+func geminiSchema(schema map[string]any) map[string]any {
+	out := make(map[string]any, len(schema))
+	for k, v := range schema {
+		switch k {
+		case "additionalProperties":
+			continue
+		case "type":
+			if s, ok := v.(string); ok {
+				out[k] = strings.ToUpper(s)
+				continue
+			}
+		case "properties":
+			if props, ok := v.(map[string]any); ok {
+				converted := make(map[string]any, len(props))
+				for name, prop := range props {
+					if m, ok := prop.(map[string]any); ok {
+						converted[name] = geminiSchema(m)
+					}
+				}
+				out[k] = converted
+				continue
+			}
+		case "items":
+			if m, ok := v.(map[string]any); ok {
+				out[k] = geminiSchema(m)
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// decodeVerificationResult はAPIレスポンスから検証結果を取り出す
+// 構造化出力で得たJSONを優先し、失敗した場合はテキスト解析にフォールバックする
+func decodeVerificationResult(resp *Completion) (*VerificationResult, error) {
+	if resp.Structured {
+		var result VerificationResult
+		if err := json.Unmarshal([]byte(resp.Text), &result); err == nil {
+			return &result, nil
+		}
+	}
+	return parseVerificationResult(resp.Text)
+}
+
+// decodeEndpointResult はAPIレスポンスからエンドポイント抽出結果を取り出す
+// 構造化出力で得たJSONを優先し、失敗した場合はテキスト解析にフォールバックする
+func decodeEndpointResult(resp *Completion) ([]EndpointResult, error) {
+	if resp.Structured {
+		if results, err := unmarshalEndpointResult(resp.Text); err == nil {
+			return results, nil
+		}
+	}
+	return parseEndpointResult(resp.Text)
+}
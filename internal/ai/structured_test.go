@@ -0,0 +1,164 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeServer はリクエストボディを記録して固定のレスポンスを返すテスト用サーバー
+func fakeServer(t *testing.T, response string, gotBody *map[string]any) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(gotBody); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClaudeProvider_StructuredOutput(t *testing.T) {
+	var got map[string]any
+	server := fakeServer(t, `{"content":[{"type":"tool_use","id":"toolu_1","name":"report_verification","input":{"matchPercentage":85,"matchedItems":["a"],"unmatchedItems":["b"],"notes":"n"}}]}`, &got)
+
+	p, _ := NewClaudeProvider("test-key", WithBaseURL(server.URL))
+	result, err := p.Verify(context.Background(), "# spec", map[string]string{"a.ts": "code"})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if result.MatchPercentage != 85 || len(result.UnmatchedItems) != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	tools, _ := got["tools"].([]any)
+	if len(tools) != 1 {
+		t.Fatalf("tools = %v, want 1 tool", got["tools"])
+	}
+	tool := tools[0].(map[string]any)
+	if tool["name"] != "report_verification" {
+		t.Errorf("tool name = %v", tool["name"])
+	}
+	if schema, _ := tool["input_schema"].(map[string]any); schema["type"] != "object" {
+		t.Errorf("input_schema = %v", tool["input_schema"])
+	}
+	choice, _ := got["tool_choice"].(map[string]any)
+	if choice["type"] != "tool" || choice["name"] != "report_verification" {
+		t.Errorf("tool_choice = %v", got["tool_choice"])
+	}
+}
+
+func TestOpenAIProvider_StructuredOutput(t *testing.T) {
+	var got map[string]any
+	server := fakeServer(t, `{"choices":[{"message":{"content":"{\"endpoints\":[{\"method\":\"GET\",\"path\":\"/users\",\"file\":\"a.ts\",\"description\":\"\"}]}"}}]}`, &got)
+
+	p, _ := NewOpenAIProvider("test-key", WithBaseURL(server.URL))
+	results, err := p.ExtractEndpoints(context.Background(), &ExtractOptions{SourceType: "express"}, "code")
+	if err != nil {
+		t.Fatalf("ExtractEndpoints failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Path != "/users" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+
+	format, _ := got["response_format"].(map[string]any)
+	if format["type"] != "json_schema" {
+		t.Fatalf("response_format = %v", got["response_format"])
+	}
+	jsonSchema, _ := format["json_schema"].(map[string]any)
+	if jsonSchema["name"] != "report_endpoints" || jsonSchema["strict"] != true {
+		t.Errorf("json_schema = %v", jsonSchema)
+	}
+}
+
+func TestGeminiProvider_StructuredOutput(t *testing.T) {
+	var got map[string]any
+	server := fakeServer(t, `{"candidates":[{"content":{"parts":[{"text":"{\"matchPercentage\":70,\"matchedItems\":[],\"unmatchedItems\":[],\"notes\":\"\"}"}]}}]}`, &got)
+
+	p, _ := NewGeminiProvider("test-key", WithBaseURL(server.URL))
+	result, err := p.Verify(context.Background(), "# spec", map[string]string{"a.ts": "code"})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if result.MatchPercentage != 70 {
+		t.Errorf("MatchPercentage = %d, want 70", result.MatchPercentage)
+	}
+
+	genConfig, _ := got["generationConfig"].(map[string]any)
+	if genConfig["responseMimeType"] != "application/json" {
+		t.Errorf("responseMimeType = %v", genConfig["responseMimeType"])
+	}
+	schema, _ := genConfig["responseSchema"].(map[string]any)
+	if schema["type"] != "OBJECT" {
+		t.Errorf("responseSchema.type = %v, want OBJECT", schema["type"])
+	}
+	if _, ok := schema["additionalProperties"]; ok {
+		t.Error("responseSchema must not contain additionalProperties")
+	}
+}
+
+func TestOllamaProvider_StructuredOutput(t *testing.T) {
+	var got map[string]any
+	server := fakeServer(t, `{"message":{"role":"assistant","content":"{\"endpoints\":[]}"},"done":true}`, &got)
+
+	p, _ := NewOllamaProvider(server.URL)
+	results, err := p.ExtractEndpoints(context.Background(), nil, "code")
+	if err != nil {
+		t.Fatalf("ExtractEndpoints failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("unexpected results: %+v", results)
+	}
+
+	format, _ := got["format"].(map[string]any)
+	if format["type"] != "object" {
+		t.Errorf("format = %v", got["format"])
+	}
+}
+
+func TestStructuredOutputDisabled(t *testing.T) {
+	var got map[string]any
+	content, _ := json.Marshal("```json\n{\"matchPercentage\": 60}\n```")
+	server := fakeServer(t, `{"choices":[{"message":{"content":`+string(content)+`}}]}`, &got)
+
+	p, _ := NewOpenAICompatibleProvider(server.URL, "", WithStructuredOutput(false))
+	result, err := p.Verify(context.Background(), "# spec", map[string]string{"a.ts": "code"})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if result.MatchPercentage != 60 {
+		t.Errorf("MatchPercentage = %d, want 60", result.MatchPercentage)
+	}
+	if _, ok := got["response_format"]; ok {
+		t.Error("response_format must be omitted when structured output is disabled")
+	}
+}
+
+func TestDecodeVerificationResult_FallsBackToText(t *testing.T) {
+	// 構造化出力を要求してもモデルがテキストで返した場合はテキスト解析にフォールバックする
+	resp := &Completion{Text: "```json\n{\"matchPercentage\": 55}\n```", Structured: true}
+	result, err := decodeVerificationResult(resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.MatchPercentage != 55 {
+		t.Errorf("MatchPercentage = %d, want 55", result.MatchPercentage)
+	}
+}
+
+func TestClaudeProvider_FallsBackToTextBlock(t *testing.T) {
+	var got map[string]any
+	server := fakeServer(t, `{"content":[{"type":"text","text":"{\"matchPercentage\": 40}"}]}`, &got)
+
+	p, _ := NewClaudeProvider("test-key", WithBaseURL(server.URL))
+	result, err := p.Verify(context.Background(), "# spec", map[string]string{"a.ts": "code"})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if result.MatchPercentage != 40 {
+		t.Errorf("MatchPercentage = %d, want 40", result.MatchPercentage)
+	}
+}
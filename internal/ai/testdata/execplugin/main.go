@@ -0,0 +1,96 @@
+// execplugin は exec プロバイダーのテストに使用するプラグイン
+// 標準入力のリクエストを検証し、受け取った内容が分かる結果を標準出力に返す
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+type request struct {
+	Version int    `json:"version"`
+	Task    string `json:"task"`
+	Model   string `json:"model"`
+	Prompt  string `json:"prompt"`
+	Spec    *struct {
+		Content string `json:"content"`
+		Title   string `json:"title"`
+		Type    string `json:"type"`
+	} `json:"spec"`
+	CodeFiles []struct {
+		Path    string `json:"path"`
+		Content string `json:"content"`
+	} `json:"codeFiles"`
+	Focus   []string `json:"focus"`
+	Code    string   `json:"code"`
+	Options struct {
+		Language        string `json:"language"`
+		MaxOutputTokens int    `json:"maxOutputTokens"`
+		SourceType      string `json:"sourceType"`
+		Category        string `json:"category"`
+	} `json:"options"`
+}
+
+func main() {
+	fail := flag.Bool("fail", false, "exit with status 3 after writing to stderr")
+	sleep := flag.Duration("sleep", 0, "wait before answering")
+	output := flag.String("output", "", "write this text instead of a result")
+	env := flag.String("env", "", "report the value of this environment variable in notes")
+	flag.Parse()
+
+	var req request
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintf(os.Stderr, "execplugin: invalid request: %v\n", err)
+		os.Exit(2)
+	}
+	if req.Version != 1 {
+		fmt.Fprintf(os.Stderr, "execplugin: unsupported protocol version %d\n", req.Version)
+		os.Exit(2)
+	}
+	fmt.Fprintln(os.Stderr, "execplugin: received", req.Task)
+
+	if *sleep > 0 {
+		time.Sleep(*sleep)
+	}
+	if *fail {
+		fmt.Fprintln(os.Stderr, "gateway: 401 unauthorized")
+		os.Exit(3)
+	}
+	if *output != "" {
+		fmt.Print(*output)
+		return
+	}
+
+	switch req.Task {
+	case "verify":
+		var matched []string
+		for _, f := range req.CodeFiles {
+			matched = append(matched, f.Path)
+		}
+		notes := fmt.Sprintf("title=%s type=%s model=%s language=%s focus=%d prompt=%t",
+			req.Spec.Title, req.Spec.Type, req.Model, req.Options.Language, len(req.Focus),
+			strings.Contains(req.Prompt, req.Spec.Content))
+		if *env != "" {
+			notes += fmt.Sprintf(" %s=%s", *env, os.Getenv(*env))
+		}
+		json.NewEncoder(os.Stdout).Encode(map[string]any{
+			"matchPercentage": 75,
+			"matchedItems":    matched,
+			"unmatchedItems":  append([]string{}, req.Focus...),
+			"notes":           notes,
+			"usage":           map[string]int{"inputTokens": 120, "outputTokens": 30},
+			"retries":         1,
+		})
+	case "extract":
+		json.NewEncoder(os.Stdout).Encode([]map[string]string{
+			{"method": "GET", "path": "/" + req.Options.Category, "source": req.Options.SourceType, "file": "plugin.ts"},
+		})
+	default:
+		fmt.Fprintf(os.Stderr, "execplugin: unknown task %q\n", req.Task)
+		os.Exit(2)
+	}
+}
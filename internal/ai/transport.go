@@ -0,0 +1,187 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// CompletionRequest は Transport に送信する1回分のリクエスト
+type CompletionRequest struct {
+	// モデルに送信するプロンプト
+	Prompt string
+
+	// 最大出力トークン数（ai.max_output_tokens を反映済み）
+	MaxTokens int
+
+	// 構造化出力に使用するスキーマ（nilの場合はテキストで応答させる）
+	Schema *ResponseSchema
+}
+
+// Completion はAPI呼び出しの結果
+type Completion struct {
+	// 生成されたテキスト
+	Text string
+
+	// 再試行回数
+	Retries int
+
+	// 構造化出力（スキーマ指定）で得られたJSONかどうか
+	Structured bool
+
+	// APIが返したトークン使用量（返さないプロバイダーでは0）
+	Usage Usage
+}
+
+// Transport はプロンプトを送信して生成されたテキストと使用量を受け取る低レベルのインターフェース
+// 各プロバイダーはAPIのワイヤーフォーマット（リクエストの組み立てとレスポンスの解析）だけを実装し、
+// 検証やエンドポイント抽出は TransportProvider が共通で行う
+type Transport interface {
+	// Complete はプロンプトを送信して結果を返す
+	Complete(ctx context.Context, req *CompletionRequest) (*Completion, error)
+
+	// Name はプロバイダー名を返す
+	Name() string
+
+	// Model は使用しているモデル名を返す
+	Model() string
+}
+
+// Middleware は Transport を包んで横断的な処理（ログ、計測、リクエストの加工など）を追加する
+type Middleware func(next Transport) Transport
+
+// Chain は transport に middlewares を適用した Transport を返す
+// 先頭のミドルウェアが最も外側になり、最初にリクエストを受け取る
+func Chain(transport Transport, middlewares ...Middleware) Transport {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		transport = middlewares[i](transport)
+	}
+	return transport
+}
+
+// CompleteFunc は Transport.Complete と同じシグネチャの関数
+type CompleteFunc func(ctx context.Context, req *CompletionRequest) (*Completion, error)
+
+// WrapTransport は next の名前とモデルを引き継ぎ、Complete を complete に置き換えた Transport を返す
+// ミドルウェアの実装に使用する
+func WrapTransport(next Transport, complete CompleteFunc) Transport {
+	return &wrappedTransport{Transport: next, complete: complete}
+}
+
+type wrappedTransport struct {
+	Transport
+	complete CompleteFunc
+}
+
+// Complete は置き換えた関数を呼び出す
+func (t *wrappedTransport) Complete(ctx context.Context, req *CompletionRequest) (*Completion, error) {
+	return t.complete(ctx, req)
+}
+
+// LogMiddleware はリクエストごとにプロバイダー、トークン使用量、再試行回数、所要時間を w に1行で出力するミドルウェア
+// 並列に呼び出されても行が混ざらないよう書き込みを直列化する
+func LogMiddleware(w io.Writer) Middleware {
+	var mu sync.Mutex
+	return func(next Transport) Transport {
+		return WrapTransport(next, func(ctx context.Context, req *CompletionRequest) (*Completion, error) {
+			start := time.Now()
+			resp, err := next.Complete(ctx, req)
+			elapsed := time.Since(start).Round(time.Millisecond)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(w, "[%s/%s] error after %s: %v\n", next.Name(), next.Model(), elapsed, err)
+				return resp, err
+			}
+			fmt.Fprintf(w, "[%s/%s] %d input + %d output tokens, %d retries, %s\n",
+				next.Name(), next.Model(), resp.Usage.InputTokens, resp.Usage.OutputTokens, resp.Retries, elapsed)
+			return resp, nil
+		})
+	}
+}
+
+// TransportProvider は Transport の上に検証とエンドポイント抽出を実装した Provider
+// プロンプトの構築、コンテキストウィンドウに応じた分割、応答の解析と再質問はここで行う
+type TransportProvider struct {
+	transport Transport
+	config    ProviderConfig
+}
+
+// NewTransportProvider は transport を使用する Provider を作成する
+// WithMiddleware で指定したミドルウェアを transport に適用する
+func NewTransportProvider(transport Transport, opts ...ProviderOption) *TransportProvider {
+	cfg := newProviderConfig(opts)
+	return &TransportProvider{
+		transport: Chain(transport, cfg.Middlewares...),
+		config:    cfg,
+	}
+}
+
+// Name はプロバイダー名を返す
+func (p *TransportProvider) Name() string {
+	return p.transport.Name()
+}
+
+// Model は使用しているモデル名を返す
+func (p *TransportProvider) Model() string {
+	return p.transport.Model()
+}
+
+// Verify はSPECとコードの一致度を検証する
+func (p *TransportProvider) Verify(ctx context.Context, specContent string, codeContents map[string]string) (*VerificationResult, error) {
+	return p.VerifyWithOptions(ctx, specContent, codeContents, nil)
+}
+
+// VerifyWithOptions は検証観点を指定してSPECとコードの一致度を検証する
+// 結果にはプロバイダーとモデル、所要時間、API呼び出しの回数を設定する
+// トークンを消費した後に失敗した場合は、エラーの UsageError にAPI呼び出しの回数と所要時間を設定する
+func (p *TransportProvider) VerifyWithOptions(ctx context.Context, specContent string, codeContents map[string]string, opts *VerifyOptions) (*VerificationResult, error) {
+	start := time.Now()
+	counter := &countingTransport{Transport: p.transport}
+	result, err := verifyInContext(ctx, counter, p.config, specContent, codeContents, opts)
+	if err != nil {
+		if billed := AsUsageError(err); billed != nil {
+			billed.Attempts = counter.requests
+			billed.LatencyMs = time.Since(start).Milliseconds()
+		}
+		return nil, err
+	}
+	result.Provider = p.Name()
+	result.Model = p.Model()
+	result.LatencyMs = time.Since(start).Milliseconds()
+	result.Attempts = counter.requests + result.Retries
+	return result, nil
+}
+
+// ExtractEndpoints はコードからAPIエンドポイント/ページルートを抽出する
+func (p *TransportProvider) ExtractEndpoints(ctx context.Context, opts *ExtractOptions, codeContent string) ([]EndpointResult, error) {
+	prompt, err := buildExtractionPrompt(opts, codeContent)
+	if err != nil {
+		return nil, err
+	}
+	return requestEndpoints(ctx, p.transport, p.config, prompt)
+}
+
+// complete は既定の最大出力トークン数に設定を反映してリクエストを送信する
+func complete(ctx context.Context, transport Transport, cfg ProviderConfig, prompt string, maxTokens int, schema *ResponseSchema) (*Completion, error) {
+	return transport.Complete(ctx, &CompletionRequest{
+		Prompt:    prompt,
+		MaxTokens: cfg.maxTokensOr(maxTokens),
+		Schema:    schema,
+	})
+}
+
+// countingTransport は Complete の呼び出し回数を数える（1回の検証の中で逐次呼び出される前提）
+type countingTransport struct {
+	Transport
+	requests int
+}
+
+// Complete は呼び出し回数を数えてリクエストを送信する
+func (t *countingTransport) Complete(ctx context.Context, req *CompletionRequest) (*Completion, error) {
+	t.requests++
+	return t.Transport.Complete(ctx, req)
+}
@@ -0,0 +1,169 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeTransport は固定の応答を返し、受け取ったリクエストを記録するテスト用 Transport
+type fakeTransport struct {
+	text     string
+	err      error
+	requests []*CompletionRequest
+}
+
+func (t *fakeTransport) Complete(ctx context.Context, req *CompletionRequest) (*Completion, error) {
+	t.requests = append(t.requests, req)
+	if t.err != nil {
+		return nil, t.err
+	}
+	return &Completion{Text: t.text, Structured: req.Schema != nil, Usage: Usage{InputTokens: 10, OutputTokens: 5}}, nil
+}
+
+func (t *fakeTransport) Name() string  { return "fake" }
+func (t *fakeTransport) Model() string { return "fake-model" }
+
+func TestTransportProvider_Verify(t *testing.T) {
+	transport := &fakeTransport{text: `{"matchPercentage": 65, "matchedItems": ["a"], "unmatchedItems": [], "notes": ""}`}
+	p := NewTransportProvider(transport, WithMaxOutputTokens(1234))
+	if p.Name() != "fake" || p.Model() != "fake-model" {
+		t.Errorf("identity = %s/%s", p.Name(), p.Model())
+	}
+
+	result, err := p.Verify(context.Background(), "# spec", map[string]string{"a.ts": "code"})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if result.MatchPercentage != 65 || result.Usage == nil || result.Usage.Total() != 15 {
+		t.Errorf("result = %+v", result)
+	}
+	if result.Provider != "fake" || result.Model != "fake-model" || result.Attempts != 1 {
+		t.Errorf("accounting = %s/%s, %d attempts", result.Provider, result.Model, result.Attempts)
+	}
+	if len(transport.requests) != 1 {
+		t.Fatalf("requests = %d, want 1", len(transport.requests))
+	}
+	req := transport.requests[0]
+	if req.MaxTokens != 1234 {
+		t.Errorf("MaxTokens = %d, want the configured 1234", req.MaxTokens)
+	}
+	if req.Schema != verificationResponseSchema || !strings.Contains(req.Prompt, "# spec") {
+		t.Errorf("unexpected request: %+v", req)
+	}
+}
+
+func TestTransportProvider_VerifyFailureUsage(t *testing.T) {
+	// 応答も再質問も解析できない場合は、失敗するまでに消費した使用量をエラーで返す
+	transport := &fakeTransport{text: "not json"}
+	p := NewTransportProvider(transport)
+
+	_, err := p.Verify(context.Background(), "# spec", map[string]string{"a.ts": "code"})
+	if err == nil {
+		t.Fatal("expected error but got nil")
+	}
+	billed := AsUsageError(err)
+	if billed == nil || billed.Usage.Total() != 30 || billed.Attempts != 2 {
+		t.Errorf("billed = %+v, want the usage of both requests", billed)
+	}
+
+	if AsUsageError(errors.New("boom")) != nil {
+		t.Error("an error without usage should not report billed usage")
+	}
+}
+
+func TestTransportProvider_ExtractEndpointsWithoutStructuredOutput(t *testing.T) {
+	transport := &fakeTransport{text: "```json\n[{\"method\": \"GET\", \"path\": \"/users\"}]\n```"}
+	p := NewTransportProvider(transport, WithStructuredOutput(false))
+
+	endpoints, err := p.ExtractEndpoints(context.Background(), nil, "code")
+	if err != nil || len(endpoints) != 1 || endpoints[0].Path != "/users" {
+		t.Errorf("ExtractEndpoints = %+v, %v", endpoints, err)
+	}
+	if req := transport.requests[0]; req.Schema != nil || req.MaxTokens != 4000 {
+		t.Errorf("unexpected request: %+v", req)
+	}
+}
+
+func TestChain(t *testing.T) {
+	var order []string
+	record := func(name string) Middleware {
+		return func(next Transport) Transport {
+			return WrapTransport(next, func(ctx context.Context, req *CompletionRequest) (*Completion, error) {
+				order = append(order, name)
+				req.Prompt += "+" + name
+				return next.Complete(ctx, req)
+			})
+		}
+	}
+
+	transport := &fakeTransport{text: "[]"}
+	p := NewTransportProvider(transport, WithMiddleware(record("outer")), WithMiddleware(record("inner")))
+	if _, err := p.ExtractEndpoints(context.Background(), nil, "code"); err != nil {
+		t.Fatalf("ExtractEndpoints failed: %v", err)
+	}
+	if strings.Join(order, ",") != "outer,inner" {
+		t.Errorf("order = %v, want outer,inner", order)
+	}
+	if !strings.HasSuffix(transport.requests[0].Prompt, "+outer+inner") {
+		t.Error("the transport should receive the request modified by every middleware")
+	}
+	if p.Name() != "fake" {
+		t.Errorf("Name = %q, middlewares should keep the transport name", p.Name())
+	}
+}
+
+func TestLogMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	transport := Chain(&fakeTransport{text: "ok"}, LogMiddleware(&buf))
+	if _, err := transport.Complete(context.Background(), &CompletionRequest{Prompt: "p"}); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if got := buf.String(); !strings.HasPrefix(got, "[fake/fake-model] 10 input + 5 output tokens, 0 retries, ") {
+		t.Errorf("log = %q", got)
+	}
+
+	buf.Reset()
+	failing := Chain(&fakeTransport{err: errors.New("boom")}, LogMiddleware(&buf))
+	if _, err := failing.Complete(context.Background(), &CompletionRequest{Prompt: "p"}); err == nil {
+		t.Fatal("expected error but got nil")
+	}
+	if got := buf.String(); !strings.HasPrefix(got, "[fake/fake-model] error after ") || !strings.Contains(got, "boom") {
+		t.Errorf("log = %q", got)
+	}
+}
+
+func TestLogMiddleware_GeminiKeyNotLogged(t *testing.T) {
+	const apiKey = "AIzaSyTestKey0123456789"
+	var gotKey, gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey, gotQuery = r.Header.Get("x-goog-api-key"), r.URL.RawQuery
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	p, err := NewGeminiProvider(apiKey, WithBaseURL(server.URL), WithMaxAttempts(1), WithMiddleware(LogMiddleware(&buf)))
+	if err != nil {
+		t.Fatalf("NewGeminiProvider failed: %v", err)
+	}
+	if _, err := p.Verify(context.Background(), "# spec", map[string]string{"a.ts": "code"}); err == nil {
+		t.Fatal("expected error but got nil")
+	}
+	if gotKey != apiKey || gotQuery != "" {
+		t.Errorf("key header = %q, query = %q, want the key only in the header", gotKey, gotQuery)
+	}
+
+	// 接続できない場合のエラーにはリクエストのURLが含まれる
+	server.Close()
+	if _, err := p.Verify(context.Background(), "# spec", map[string]string{"a.ts": "code"}); err == nil || strings.Contains(err.Error(), apiKey) {
+		t.Errorf("err = %v, should not contain the API key", err)
+	}
+	if strings.Contains(buf.String(), apiKey) {
+		t.Errorf("log contains the API key: %s", buf.String())
+	}
+}
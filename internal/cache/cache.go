@@ -0,0 +1,202 @@
+// Package cache は検証結果をディスクに保存する内容アドレス方式のキャッシュを提供する
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultDir はキャッシュのデフォルトの保存先
+const DefaultDir = ".specverify/cache"
+
+// formatVersion はキャッシュエントリの形式バージョン
+// 形式を変更した場合に上げると、古いエントリはキーが変わり使われなくなる
+const formatVersion = "1"
+
+// Cache はキーに対応するJSON値をディレクトリ配下に保存するキャッシュ
+type Cache struct {
+	dir string
+	now func() time.Time
+}
+
+// entry はキャッシュファイルの内容
+type entry struct {
+	CreatedAt time.Time       `json:"created_at"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// New は dir を保存先とするCacheを作成する
+// dir が空の場合は DefaultDir を使用する
+func New(dir string) *Cache {
+	if dir == "" {
+		dir = DefaultDir
+	}
+	return &Cache{dir: dir, now: time.Now}
+}
+
+// Dir はキャッシュの保存先を返す
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Key は parts の内容からキャッシュキー（SHA-256の16進文字列）を作成する
+// 各要素は長さ付きで連結するため、区切り位置が異なる入力が同じキーになることはない
+func Key(parts ...string) string {
+	h := sha256.New()
+	h.Write([]byte(formatVersion))
+	for _, p := range parts {
+		h.Write([]byte(strconv.Itoa(len(p))))
+		h.Write([]byte{':'})
+		h.Write([]byte(p))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// path はキーに対応するファイルパスを返す
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// Get はキーに対応する値を v に読み込む
+// エントリが存在しない、または壊れている場合は false を返す
+func (c *Cache) Get(key string, v any) (bool, error) {
+	p := c.path(key)
+	data, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read cache entry: %w", err)
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || len(e.Value) == 0 {
+		return false, nil
+	}
+	if err := json.Unmarshal(e.Value, v); err != nil {
+		return false, nil
+	}
+
+	// 最終利用日時を更新して prune の対象から外す
+	now := c.now()
+	_ = os.Chtimes(p, now, now)
+	return true, nil
+}
+
+// Has はキーに対応するエントリが存在するかを返す（最終利用日時は更新しない）
+func (c *Cache) Has(key string) bool {
+	_, err := os.Stat(c.path(key))
+	return err == nil
+}
+
+// Put はキーに対応する値を保存する
+// 一時ファイルに書き込んでからリネームするため、並列実行中に壊れたエントリが読まれることはない
+func (c *Cache) Put(key string, v any) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache value: %w", err)
+	}
+	data, err := json.Marshal(entry{CreatedAt: c.now(), Value: value})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	p := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
+
+// PruneResult は Prune の結果
+type PruneResult struct {
+	// 削除したエントリ数
+	Removed int
+
+	// 残ったエントリ数
+	Kept int
+
+	// 削除したバイト数
+	RemovedBytes int64
+}
+
+// Prune は最終利用から olderThan 以上経過したエントリを削除する
+// olderThan が0以下の場合は全てのエントリを削除する
+func (c *Cache) Prune(olderThan time.Duration) (*PruneResult, error) {
+	result := &PruneResult{}
+	cutoff := c.now().Add(-olderThan)
+
+	err := filepath.WalkDir(c.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") && !strings.HasPrefix(d.Name(), ".tmp-") {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if olderThan > 0 && info.ModTime().After(cutoff) {
+			result.Kept++
+			return nil
+		}
+		if err := os.Remove(p); err != nil {
+			return err
+		}
+		result.Removed++
+		result.RemovedBytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to prune cache: %w", err)
+	}
+
+	removeEmptyDirs(c.dir)
+	return result, nil
+}
+
+// removeEmptyDirs はキャッシュディレクトリ直下の空のサブディレクトリを削除する
+func removeEmptyDirs(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			// 空でない場合は失敗するだけなので無視してよい
+			_ = os.Remove(filepath.Join(dir, e.Name()))
+		}
+	}
+}
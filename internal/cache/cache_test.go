@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testValue struct {
+	Score int    `json:"score"`
+	Note  string `json:"note"`
+}
+
+func TestKey(t *testing.T) {
+	if Key("a", "bc") == Key("ab", "c") {
+		t.Error("keys with different boundaries should differ")
+	}
+	if Key("claude", "model", "prompt") != Key("claude", "model", "prompt") {
+		t.Error("same parts should produce the same key")
+	}
+	if len(Key("x")) != 64 {
+		t.Errorf("key length = %d, want 64", len(Key("x")))
+	}
+}
+
+func TestCacheGetPut(t *testing.T) {
+	c := New(t.TempDir())
+	key := Key("spec", "code")
+
+	var got testValue
+	found, err := c.Get(key, &got)
+	if err != nil || found {
+		t.Fatalf("Get on empty cache = %v, %v; want false, nil", found, err)
+	}
+
+	want := testValue{Score: 85, Note: "ok"}
+	if err := c.Put(key, want); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	found, err = c.Get(key, &got)
+	if err != nil || !found {
+		t.Fatalf("Get = %v, %v; want true, nil", found, err)
+	}
+	if got != want {
+		t.Errorf("Get value = %+v, want %+v", got, want)
+	}
+}
+
+func TestCacheGet_CorruptEntry(t *testing.T) {
+	c := New(t.TempDir())
+	key := Key("corrupt")
+
+	if err := os.MkdirAll(filepath.Dir(c.path(key)), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(c.path(key), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var got testValue
+	found, err := c.Get(key, &got)
+	if err != nil || found {
+		t.Errorf("Get on corrupt entry = %v, %v; want false, nil", found, err)
+	}
+}
+
+func TestCachePrune(t *testing.T) {
+	dir := t.TempDir()
+	c := New(dir)
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	oldKey, newKey := Key("old"), Key("new")
+	if err := c.Put(oldKey, testValue{Score: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Put(newKey, testValue{Score: 2}); err != nil {
+		t.Fatal(err)
+	}
+	old := now.Add(-40 * 24 * time.Hour)
+	if err := os.Chtimes(c.path(oldKey), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(c.path(newKey), now, now); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := c.Prune(30 * 24 * time.Hour)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if result.Removed != 1 || result.Kept != 1 {
+		t.Errorf("Prune = %+v, want 1 removed and 1 kept", result)
+	}
+	if _, err := os.Stat(c.path(oldKey)); !os.IsNotExist(err) {
+		t.Error("old entry should be removed")
+	}
+
+	result, err = c.Prune(0)
+	if err != nil {
+		t.Fatalf("Prune(0) failed: %v", err)
+	}
+	if result.Removed != 1 {
+		t.Errorf("Prune(0) removed = %d, want 1", result.Removed)
+	}
+}
+
+func TestCachePrune_MissingDir(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "missing"))
+	result, err := c.Prune(time.Hour)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if result.Removed != 0 {
+		t.Errorf("Removed = %d, want 0", result.Removed)
+	}
+}
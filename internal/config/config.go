@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -17,12 +18,19 @@ type Config struct {
 	// ソースコードのルートディレクトリ
 	CodeDir string `yaml:"code_dir"`
 
-	// 使用するAIプロバイダー (claude, openai, gemini)
+	// 使用するAIプロバイダー (claude, openai, gemini, ollama, openai-compatible)
+	// ai_provider にリスト（例: [claude, openai, ollama]）を指定した場合は先頭のプロバイダー
 	AIProvider string `yaml:"ai_provider"`
 
+	// 主プロバイダーが再試行可能なエラーで失敗した場合に順に試すプロバイダー（ai_provider のリストの2番目以降）
+	AIProviderFallbacks []string `yaml:"-"`
+
 	// AIプロバイダーのAPIキー（環境変数から取得することを推奨）
 	AIAPIKey string `yaml:"ai_api_key,omitempty"`
 
+	// AIプロバイダーの詳細設定
+	AI AISettings `yaml:"ai,omitempty"`
+
 	// SPECタイプごとのコードディレクトリマッピング（後方互換用）
 	Mapping map[string]string `yaml:"mapping,omitempty"`
 
@@ -38,10 +46,128 @@ type Config struct {
 	// ルートソース定義（ページ/API両方対応）
 	RouteSources []RouteSource `yaml:"route_sources,omitempty"`
 
+	// 検証結果キャッシュの設定
+	Cache CacheSettings `yaml:"cache,omitempty"`
+
+	// アンサンブル検証（複数プロバイダー/モデルによる合議）の設定
+	Ensemble EnsembleSettings `yaml:"ensemble,omitempty"`
+
+	// セクション単位の検証の設定
+	Sections SectionSettings `yaml:"sections,omitempty"`
+
+	// プロンプトテンプレートの設定
+	Prompts PromptSettings `yaml:"prompts,omitempty"`
+
+	// AIに送信するコードから機密情報を取り除く設定
+	Redaction RedactionSettings `yaml:"redaction,omitempty"`
+
+	// AIに送信してよいファイルの制限と監査ログの設定
+	Egress EgressSettings `yaml:"egress,omitempty"`
+
+	// CLIの出力とAIの回答の言語（ja, en。省略時は ja）
+	Language string `yaml:"language,omitempty"`
+
 	// 検証時のオプション
 	Options VerifyOptions `yaml:"options"`
 }
 
+// EnsembleSettings はアンサンブル検証の設定
+type EnsembleSettings struct {
+	// 検証に使用するプロバイダー/モデル（2つ以上指定するとアンサンブル検証になる）
+	Members []EnsembleMember `yaml:"members,omitempty"`
+
+	// 一致度の差がこの値を超えるSPECを要レビューとする（省略時は20）
+	ReviewThreshold int `yaml:"review_threshold,omitempty"`
+}
+
+// EnsembleMember はアンサンブルの1メンバー
+type EnsembleMember struct {
+	// プロバイダー名 (claude, openai, gemini, ollama, openai-compatible)
+	Provider string `yaml:"provider"`
+
+	// モデル（省略時はプロバイダーごとのデフォルト）
+	Model string `yaml:"model,omitempty"`
+
+	// APIのベースURL（ollama, openai-compatible 用）
+	BaseURL string `yaml:"base_url,omitempty"`
+}
+
+// PromptSettings はプロンプトテンプレートの設定
+type PromptSettings struct {
+	// 検証プロンプトのテンプレートファイル（Go の text/template 形式）。省略時は組み込みのテンプレート
+	// spec_types.<type>.prompt が指定されたタイプではそちらを優先する
+	Verify string `yaml:"verify,omitempty"`
+
+	// コンテキストウィンドウに収まらないコードを分割して検証する場合の、
+	// 断片ごとに根拠を抽出するプロンプトと、集めた根拠で最終的な検証を行うプロンプトのテンプレートファイル
+	// spec_types.<type>.evidence_prompt / reduce_prompt が指定されたタイプではそちらを優先する
+	Evidence string `yaml:"evidence,omitempty"`
+	Reduce   string `yaml:"reduce,omitempty"`
+}
+
+// RedactionSettings はAIに送信するコードから機密情報（シークレット、個人情報）を取り除く設定
+type RedactionSettings struct {
+	// 機密情報をプレースホルダーに置き換えてから送信するか（省略時は true）
+	Enabled *bool `yaml:"enabled,omitempty"`
+
+	// 無効にする組み込みの検出ルール（api_key, email, high_entropy など）
+	Disable []string `yaml:"disable,omitempty"`
+
+	// 追加の検出ルール
+	Patterns []RedactionPattern `yaml:"patterns,omitempty"`
+}
+
+// EgressSettings はAIプロバイダーに送信してよいファイルの制限と、送信内容の監査ログの設定
+type EgressSettings struct {
+	// 送信してよいファイルのglob（省略時は deny に一致しない全てのファイル）
+	Allow []string `yaml:"allow,omitempty"`
+
+	// 送信を禁止するファイルのglob（allow より優先する）
+	Deny []string `yaml:"deny,omitempty"`
+
+	// 送信内容を1行1リクエストのJSONで追記する監査ログのパス（省略時は記録しない）
+	AuditLog string `yaml:"audit_log,omitempty"`
+}
+
+// RedactionPattern は正規表現による検出ルール
+type RedactionPattern struct {
+	// ルール名（プレースホルダーと件数の表示に使用する）
+	Name string `yaml:"name"`
+
+	// 検出する正規表現（サブマッチがある場合は最初のサブマッチだけを置き換える）
+	Regex string `yaml:"regex"`
+}
+
+// SectionSettings はセクション単位の検証の設定
+type SectionSettings struct {
+	// SPECの ## セクションごとに検証し、セクション別の一致度を報告するか
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// セクションごとの重み（キーはセクション名）。全体の一致度はセクションの一致度の重み付き平均になる
+	// 指定のないセクションは1、0を指定したセクションは検証しない
+	Weights map[string]float64 `yaml:"weights,omitempty"`
+}
+
+// defaultSectionWeights は検証対象にしないセクションのデフォルト
+// メタデータや関連ファイルの一覧は実装と比較する内容ではないため重みを0とする
+var defaultSectionWeights = map[string]float64{
+	"基本情報":      0,
+	"関連ファイル":    0,
+	"関連コンポーネント": 0,
+}
+
+// DefaultReviewThreshold はアンサンブル検証で要レビューとする一致度の差のデフォルト
+const DefaultReviewThreshold = 20
+
+// CacheSettings は検証結果キャッシュの設定
+type CacheSettings struct {
+	// キャッシュを使用するか（省略時は true）
+	Enabled *bool `yaml:"enabled,omitempty"`
+
+	// キャッシュの保存先（省略時は .specverify/cache）
+	Dir string `yaml:"dir,omitempty"`
+}
+
 // RouteSource はルート（API/ページ）のソース定義
 type RouteSource struct {
 	// タイプ: express, fastify, openapi, graphql, go-echo, go-gin, rails, django, auto
@@ -58,6 +184,116 @@ type RouteSource struct {
 	Options map[string]string `yaml:"options,omitempty"`
 }
 
+// AISettings はAIプロバイダーの詳細設定
+type AISettings struct {
+	// APIのベースURL（ollama, openai-compatible では必須、その他はプロキシ等を使う場合に指定）
+	// 例: http://localhost:11434 (ollama), http://localhost:8000/v1 (openai-compatible)
+	BaseURL string `yaml:"base_url,omitempty"`
+
+	// 使用するモデル（省略時はプロバイダーごとのデフォルト）
+	Model string `yaml:"model,omitempty"`
+
+	// Azure OpenAIのリソースのURL（azure-openai では必須）
+	// 例: https://my-resource.openai.azure.com
+	Endpoint string `yaml:"endpoint,omitempty"`
+
+	// Azure OpenAIのデプロイ名（azure-openai では必須）
+	Deployment string `yaml:"deployment,omitempty"`
+
+	// Azure OpenAIのAPIバージョン（省略時は 2024-10-21）
+	APIVersion string `yaml:"api_version,omitempty"`
+
+	// 生成時のtemperature（省略時はプロバイダーごとのデフォルト）
+	Temperature *float64 `yaml:"temperature,omitempty"`
+
+	// 最大出力トークン数（省略時は検証: 2000、エンドポイント抽出: 4000）
+	MaxOutputTokens int `yaml:"max_output_tokens,omitempty"`
+
+	// 1リクエストあたりのタイムアウト（例: 60s, 2m）
+	RequestTimeout time.Duration `yaml:"request_timeout,omitempty"`
+
+	// 構造化出力を使用するか（省略時は true）
+	// 構造化出力に対応していないOpenAI互換サーバーなどでは false にする
+	StructuredOutput *bool `yaml:"structured_output,omitempty"`
+
+	// 再試行を含む最大試行回数（省略時は4回。1で再試行しない）
+	MaxAttempts int `yaml:"max_attempts,omitempty"`
+
+	// プロバイダーごとのクライアント側レート制限（キーはプロバイダー名）
+	RateLimits map[string]RateLimit `yaml:"rate_limits,omitempty"`
+
+	// モデルごとの料金表（キーはモデル名）。--dry-run のコスト見積もりに使用する
+	Pricing map[string]ModelPrice `yaml:"pricing,omitempty"`
+
+	// モデルごとのコンテキストウィンドウのトークン数（キーはモデル名）
+	// プロンプトが収まらないSPECはコードを分割して検証する。ローカルモデルでは指定を推奨
+	ContextWindows map[string]int `yaml:"context_windows,omitempty"`
+
+	// 応答を記録/再生するカセットファイルのパス
+	// ai_provider が replay の場合は再生に使用し、それ以外のプロバイダーでは応答を記録する
+	Cassette string `yaml:"cassette,omitempty"`
+
+	// replay でカセットに記録がない場合に問い合わせるプロバイダー（省略時はエラーにする）
+	// 問い合わせた応答はカセットに追記する
+	ReplayFallback string `yaml:"replay_fallback,omitempty"`
+
+	// AIプロバイダーの呼び出しに失敗した場合や、APIキーがない場合にヒューリスティック検証で代替する
+	HeuristicFallback bool `yaml:"heuristic_fallback,omitempty"`
+
+	// AIプロバイダーとのHTTP通信の設定（タイムアウト、プロキシ、CA証明書、追加ヘッダー）
+	HTTP HTTPSettings `yaml:"http,omitempty"`
+
+	// 外部コマンドのプロバイダー（exec）の設定
+	Exec ExecSettings `yaml:"exec,omitempty"`
+}
+
+// ExecSettings は検証とエンドポイント抽出を外部コマンドに委ねるプロバイダー（exec）の設定
+// コマンドは標準入力でJSONのリクエストを受け取り、標準出力にJSONの結果を返す（docs/exec-provider.md）
+type ExecSettings struct {
+	// 実行するコマンドと引数（exec では必須）
+	// 例: ["./bin/gateway-plugin", "--profile", "ci"]
+	Command []string `yaml:"command,omitempty"`
+
+	// コマンドに追加する環境変数。値の ${VAR} は環境変数に展開する
+	Env map[string]string `yaml:"env,omitempty"`
+}
+
+// HTTPSettings はAIプロバイダーとのHTTP通信の設定
+type HTTPSettings struct {
+	// 接続（TCP接続とTLSハンドシェイク）のタイムアウト（省略時は30s）
+	ConnectTimeout time.Duration `yaml:"connect_timeout,omitempty"`
+
+	// リクエスト送信後、レスポンスが返り始めるまでのタイムアウト（省略時は5m）
+	ResponseTimeout time.Duration `yaml:"response_timeout,omitempty"`
+
+	// プロキシのURL（省略時は HTTPS_PROXY / HTTP_PROXY / NO_PROXY 環境変数に従う）
+	Proxy string `yaml:"proxy,omitempty"`
+
+	// システムの証明書に加えて信頼するCA証明書（PEM形式）のファイルパス
+	CABundle string `yaml:"ca_bundle,omitempty"`
+
+	// 全てのリクエストに付与するヘッダー。値の ${VAR} は環境変数に展開する
+	Headers map[string]string `yaml:"headers,omitempty"`
+}
+
+// ModelPrice はモデルの料金（USD / 100万トークン）
+type ModelPrice struct {
+	// 入力100万トークンあたりの料金
+	InputPerMTok float64 `yaml:"input_per_mtok"`
+
+	// 出力100万トークンあたりの料金
+	OutputPerMTok float64 `yaml:"output_per_mtok"`
+}
+
+// RateLimit はクライアント側のレート制限設定
+type RateLimit struct {
+	// 1分あたりの最大リクエスト数（0の場合は無制限）
+	RequestsPerMinute int `yaml:"requests_per_minute,omitempty"`
+
+	// 1分あたりの最大入力トークン数（0の場合は無制限）
+	InputTokensPerMinute int `yaml:"input_tokens_per_minute,omitempty"`
+}
+
 // APISource は後方互換のためのエイリアス
 type APISource = RouteSource
 
@@ -73,10 +309,46 @@ type VerifyOptions struct {
 	// 0の場合は無効
 	FailUnder int `yaml:"fail_under"`
 
-	// 詳細出力を有効にする
+	// 詳細出力を有効にする（AI APIの呼び出しごとに使用量と所要時間を標準エラーに出力する）
 	Verbose bool `yaml:"verbose"`
+
+	// 1回の実行で使用するトークン数（入力+出力）の上限。超えた場合は残りのSPECをスキップする
+	// 0の場合は無制限
+	MaxTokensPerRun int `yaml:"max_tokens_per_run,omitempty"`
+
+	// 1回の実行で使用するコスト（USD）の上限。超えた場合は残りのSPECをスキップする
+	// モデルの料金が必要（ai.pricing または組み込みの参考料金）。0の場合は無制限
+	MaxCostPerRun float64 `yaml:"max_cost_per_run,omitempty"`
+
+	// 1SPECあたりに送信するSPECと関連コードの合計の最大バイト数。超えたSPECはスキップする
+	// 0の場合は無制限
+	MaxInputBytesPerSpec int `yaml:"max_input_bytes_per_spec,omitempty"`
+
+	// 1SPECあたりの検証の実行回数（自己一貫性チェック）。2以上の場合は中央値を採用し、ばらつきを報告する
+	// 0または1の場合は1回のみ
+	Samples int `yaml:"samples,omitempty"`
+
+	// 各サンプルで使用するtemperature（サンプル数より少ない場合は繰り返して使用する）
+	// 空の場合は全サンプルで ai.temperature を使用する
+	SampleTemperatures []float64 `yaml:"sample_temperatures,omitempty"`
+
+	// サンプル間の一致度の標準偏差がこの値を超えたSPECを不安定とする（デフォルト: 10）
+	UnstableThreshold float64 `yaml:"unstable_threshold,omitempty"`
+
+	// fail_under の判定に使用する一致度（median: 中央値、lower_bound: サンプル/メンバーの最小値）
+	// 空の場合は median
+	FailUnderMode string `yaml:"fail_under_mode,omitempty"`
 }
 
+// fail_under の判定に使用する一致度
+const (
+	FailUnderModeMedian     = "median"
+	FailUnderModeLowerBound = "lower_bound"
+)
+
+// DefaultUnstableThreshold はサンプル間の標準偏差で不安定とする閾値のデフォルト
+const DefaultUnstableThreshold = 10.0
+
 // SpecType はSPECタイプの詳細定義
 type SpecType struct {
 	// コードパス（複数指定可能）
@@ -90,6 +362,13 @@ type SpecType struct {
 
 	// 除外パターン（オプション）
 	ExcludePatterns []string `yaml:"exclude_patterns,omitempty"`
+
+	// このタイプの検証プロンプトのテンプレートファイル（省略時は prompts.verify）
+	Prompt string `yaml:"prompt,omitempty"`
+
+	// このタイプの分割検証のプロンプトのテンプレートファイル（省略時は prompts.evidence / prompts.reduce）
+	EvidencePrompt string `yaml:"evidence_prompt,omitempty"`
+	ReducePrompt   string `yaml:"reduce_prompt,omitempty"`
 }
 
 // Group はSPECタイプのグループ
@@ -186,10 +465,53 @@ func WithAPIKey(key string) LoadOption {
 }
 
 // WithProvider はAIプロバイダーを指定するオプション
+// カンマ区切り（例: claude,openai）で指定した場合は先頭から順に試すフォールバックチェーンになる
 func WithProvider(provider string) LoadOption {
 	return func(cfg *Config) {
-		if provider != "" {
-			cfg.AIProvider = provider
+		if provider == "" {
+			return
+		}
+		chain := strings.Split(provider, ",")
+		for i := range chain {
+			chain[i] = strings.TrimSpace(chain[i])
+		}
+		cfg.AIProvider = chain[0]
+		cfg.AIProviderFallbacks = chain[1:]
+	}
+}
+
+// WithBaseURL はAIプロバイダーのベースURLを指定するオプション
+func WithBaseURL(baseURL string) LoadOption {
+	return func(cfg *Config) {
+		if baseURL != "" {
+			cfg.AI.BaseURL = baseURL
+		}
+	}
+}
+
+// WithModel はAIモデルを指定するオプション
+func WithModel(model string) LoadOption {
+	return func(cfg *Config) {
+		if model != "" {
+			cfg.AI.Model = model
+		}
+	}
+}
+
+// WithLanguage は出力とAIの回答の言語を指定するオプション
+func WithLanguage(language string) LoadOption {
+	return func(cfg *Config) {
+		if language != "" {
+			cfg.Language = language
+		}
+	}
+}
+
+// WithCassette は応答を記録/再生するカセットファイルを指定するオプション
+func WithCassette(path string) LoadOption {
+	return func(cfg *Config) {
+		if path != "" {
+			cfg.AI.Cassette = path
 		}
 	}
 }
@@ -218,6 +540,58 @@ func (c *Config) Save(path string) error {
 	return nil
 }
 
+// rawConfig は独自のYAML変換を持たない Config（Config の変換処理の内部で使用する）
+type rawConfig Config
+
+// UnmarshalYAML は ai_provider に文字列とリストのどちらも指定できるよう Config を読み込む
+func (c *Config) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.MappingNode {
+		if i := mappingValueIndex(value, "ai_provider"); i >= 0 && value.Content[i].Kind == yaml.SequenceNode {
+			var chain []string
+			if err := value.Content[i].Decode(&chain); err != nil {
+				return err
+			}
+			if len(chain) == 0 {
+				return fmt.Errorf("ai_provider must not be an empty list")
+			}
+			c.AIProviderFallbacks = chain[1:]
+
+			// 先頭のプロバイダーを文字列として読み込ませる（元のノードは変更しない）
+			mapping := *value
+			mapping.Content = append([]*yaml.Node(nil), value.Content...)
+			mapping.Content[i] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: chain[0]}
+			value = &mapping
+		}
+	}
+	return value.Decode((*rawConfig)(c))
+}
+
+// MarshalYAML はフォールバックチェーンがある場合に ai_provider をリストとして書き出す
+func (c Config) MarshalYAML() (any, error) {
+	var node yaml.Node
+	if err := node.Encode(rawConfig(c)); err != nil {
+		return nil, err
+	}
+	if len(c.AIProviderFallbacks) > 0 {
+		if i := mappingValueIndex(&node, "ai_provider"); i >= 0 {
+			if err := node.Content[i].Encode(c.ProviderChain()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &node, nil
+}
+
+// mappingValueIndex はマッピングノードのキーに対応する値の Content 内の位置を返す（ない場合は -1）
+func mappingValueIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i + 1
+		}
+	}
+	return -1
+}
+
 // FindConfigFile は設定ファイルを探す
 func FindConfigFile() string {
 	candidates := []string{
@@ -266,6 +640,108 @@ func (c *Config) GetCodePaths(specType string) []string {
 	return []string{c.CodeDir}
 }
 
+// ProviderChain は試す順に並べたAIプロバイダーを返す（先頭が主プロバイダー）
+func (c *Config) ProviderChain() []string {
+	return append([]string{c.AIProvider}, c.AIProviderFallbacks...)
+}
+
+// IsEnsembleEnabled はアンサンブル検証が有効かを返す
+func (c *Config) IsEnsembleEnabled() bool {
+	return len(c.Ensemble.Members) > 0
+}
+
+// GetReviewThreshold はアンサンブル検証で要レビューとする一致度の差を返す
+func (c *Config) GetReviewThreshold() int {
+	if c.Ensemble.ReviewThreshold > 0 {
+		return c.Ensemble.ReviewThreshold
+	}
+	return DefaultReviewThreshold
+}
+
+// IsSectionsEnabled はセクション単位の検証が有効かを返す
+func (c *Config) IsSectionsEnabled() bool {
+	return c.Sections.Enabled
+}
+
+// GetSectionWeight はセクションの重みを返す（0の場合は検証しない）
+func (c *Config) GetSectionWeight(name string) float64 {
+	if weight, ok := c.Sections.Weights[name]; ok {
+		return max(weight, 0)
+	}
+	if weight, ok := defaultSectionWeights[name]; ok {
+		return weight
+	}
+	return 1
+}
+
+// GetSamples は1SPECあたりの検証の実行回数を返す（最低1）
+func (c *Config) GetSamples() int {
+	return max(c.Options.Samples, 1)
+}
+
+// GetSampleTemperature は i 番目のサンプルで使用するtemperatureを返す
+// sample_temperatures が未設定の場合は ai.temperature（未設定ならnil）を返す
+func (c *Config) GetSampleTemperature(i int) *float64 {
+	temps := c.Options.SampleTemperatures
+	if len(temps) == 0 {
+		return c.AI.Temperature
+	}
+	t := temps[i%len(temps)]
+	return &t
+}
+
+// GetUnstableThreshold はサンプル間の標準偏差で不安定とする閾値を返す
+func (c *Config) GetUnstableThreshold() float64 {
+	if c.Options.UnstableThreshold > 0 {
+		return c.Options.UnstableThreshold
+	}
+	return DefaultUnstableThreshold
+}
+
+// UsesLowerBound は fail_under の判定に一致度の下限を使用するかを返す
+func (c *Config) UsesLowerBound() bool {
+	return c.Options.FailUnderMode == FailUnderModeLowerBound
+}
+
+// IsRedactionEnabled はAIに送信する前に機密情報を取り除くかを返す
+func (c *Config) IsRedactionEnabled() bool {
+	return c.Redaction.Enabled == nil || *c.Redaction.Enabled
+}
+
+// IsCacheEnabled は検証結果キャッシュが有効かを返す
+func (c *Config) IsCacheEnabled() bool {
+	return c.Cache.Enabled == nil || *c.Cache.Enabled
+}
+
+// GetRateLimit はプロバイダーのレート制限設定を返す（未設定の場合はゼロ値）
+func (c *Config) GetRateLimit(provider string) RateLimit {
+	return c.AI.RateLimits[provider]
+}
+
+// GetHTTPHeaders は ai.http.headers の値の環境変数（${VAR}）を展開したヘッダーを返す
+func (c *Config) GetHTTPHeaders() map[string]string {
+	if len(c.AI.HTTP.Headers) == 0 {
+		return nil
+	}
+	headers := make(map[string]string, len(c.AI.HTTP.Headers))
+	for name, value := range c.AI.HTTP.Headers {
+		headers[name] = os.ExpandEnv(value)
+	}
+	return headers
+}
+
+// GetExecEnv は ai.exec.env の値の環境変数（${VAR}）を展開した環境変数を返す
+func (c *Config) GetExecEnv() map[string]string {
+	if len(c.AI.Exec.Env) == 0 {
+		return nil
+	}
+	env := make(map[string]string, len(c.AI.Exec.Env))
+	for name, value := range c.AI.Exec.Env {
+		env[name] = os.ExpandEnv(value)
+	}
+	return env
+}
+
 // GetVerificationFocus はSPECタイプの検証観点を返す
 func (c *Config) GetVerificationFocus(specType string) []string {
 	if st, ok := c.SpecTypes[specType]; ok {
@@ -274,6 +750,33 @@ func (c *Config) GetVerificationFocus(specType string) []string {
 	return nil
 }
 
+// GetPromptTemplate はSPECタイプの検証プロンプトのテンプレートファイルを返す
+// 空の場合は組み込みのテンプレートを使用する
+func (c *Config) GetPromptTemplate(specType string) string {
+	if st, ok := c.SpecTypes[specType]; ok && st.Prompt != "" {
+		return st.Prompt
+	}
+	return c.Prompts.Verify
+}
+
+// GetEvidenceTemplate はSPECタイプの分割検証で根拠を抽出するプロンプトのテンプレートファイルを返す
+// 空の場合は組み込みのテンプレートを使用する
+func (c *Config) GetEvidenceTemplate(specType string) string {
+	if st, ok := c.SpecTypes[specType]; ok && st.EvidencePrompt != "" {
+		return st.EvidencePrompt
+	}
+	return c.Prompts.Evidence
+}
+
+// GetReduceTemplate はSPECタイプの分割検証で最終的な検証を行うプロンプトのテンプレートファイルを返す
+// 空の場合は組み込みのテンプレートを使用する
+func (c *Config) GetReduceTemplate(specType string) string {
+	if st, ok := c.SpecTypes[specType]; ok && st.ReducePrompt != "" {
+		return st.ReducePrompt
+	}
+	return c.Prompts.Reduce
+}
+
 // GetTypesByGroup はグループに含まれるSPECタイプを返す
 func (c *Config) GetTypesByGroup(groupName string) []string {
 	if group, ok := c.Groups[groupName]; ok {
@@ -3,7 +3,9 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestSpecTypesAndGroups(t *testing.T) {
@@ -294,6 +296,16 @@ ai_api_key: config-key
 			t.Errorf("Expected CLI key 'cli-key', got '%s'", cfg.AIAPIKey)
 		}
 	})
+
+	t.Run("WithLanguage overrides config file", func(t *testing.T) {
+		cfg, err := Load(configFile, WithLanguage("en"))
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.Language != "en" {
+			t.Errorf("Expected 'en', got '%s'", cfg.Language)
+		}
+	})
 }
 
 func TestHasAPIKeyOption(t *testing.T) {
@@ -365,3 +377,493 @@ options:
 		}
 	})
 }
+
+func TestAISettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, ".specverify.yml")
+
+	configContent := `
+ai_provider: ollama
+ai:
+  base_url: http://gpu-box:11434
+  model: qwen2.5-coder:14b
+  temperature: 0
+  max_output_tokens: 3000
+  request_timeout: 90s
+  rate_limits:
+    ollama:
+      requests_per_minute: 30
+      input_tokens_per_minute: 40000
+`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	t.Run("base_url from config file", func(t *testing.T) {
+		cfg, err := Load(configFile)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.AIProvider != "ollama" {
+			t.Errorf("Expected 'ollama', got '%s'", cfg.AIProvider)
+		}
+		if cfg.AI.BaseURL != "http://gpu-box:11434" {
+			t.Errorf("Expected 'http://gpu-box:11434', got '%s'", cfg.AI.BaseURL)
+		}
+		if cfg.AI.Model != "qwen2.5-coder:14b" {
+			t.Errorf("Expected 'qwen2.5-coder:14b', got '%s'", cfg.AI.Model)
+		}
+		if cfg.AI.Temperature == nil || *cfg.AI.Temperature != 0 {
+			t.Errorf("Expected temperature 0, got %v", cfg.AI.Temperature)
+		}
+		if cfg.AI.MaxOutputTokens != 3000 {
+			t.Errorf("Expected 3000, got %d", cfg.AI.MaxOutputTokens)
+		}
+		if cfg.AI.RequestTimeout != 90*time.Second {
+			t.Errorf("Expected 90s, got %v", cfg.AI.RequestTimeout)
+		}
+	})
+
+	t.Run("GetRateLimit", func(t *testing.T) {
+		cfg, err := Load(configFile)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		limit := cfg.GetRateLimit("ollama")
+		if limit.RequestsPerMinute != 30 || limit.InputTokensPerMinute != 40000 {
+			t.Errorf("Unexpected rate limit: %+v", limit)
+		}
+		if limit := cfg.GetRateLimit("claude"); limit != (RateLimit{}) {
+			t.Errorf("Expected zero rate limit for claude, got %+v", limit)
+		}
+	})
+
+	t.Run("WithModel overrides config file", func(t *testing.T) {
+		cfg, err := Load(configFile, WithModel("llama3.1:70b"))
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.AI.Model != "llama3.1:70b" {
+			t.Errorf("Expected 'llama3.1:70b', got '%s'", cfg.AI.Model)
+		}
+	})
+
+	t.Run("WithBaseURL overrides config file", func(t *testing.T) {
+		cfg, err := Load(configFile, WithBaseURL("http://localhost:11434"))
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.AI.BaseURL != "http://localhost:11434" {
+			t.Errorf("Expected 'http://localhost:11434', got '%s'", cfg.AI.BaseURL)
+		}
+	})
+}
+
+func TestHTTPSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, ".specverify.yml")
+	configContent := `
+ai:
+  http:
+    connect_timeout: 10s
+    response_timeout: 3m
+    proxy: http://proxy.corp.example:8080
+    ca_bundle: /etc/ssl/corp-ca.pem
+    headers:
+      X-Gateway-Key: ${SPEC_VERIFY_TEST_GATEWAY_KEY}
+      X-Team: qa
+`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv("SPEC_VERIFY_TEST_GATEWAY_KEY", "gw-secret")
+
+	cfg, err := Load(configFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	http := cfg.AI.HTTP
+	if http.ConnectTimeout != 10*time.Second || http.ResponseTimeout != 3*time.Minute {
+		t.Errorf("timeouts = %v / %v", http.ConnectTimeout, http.ResponseTimeout)
+	}
+	if http.Proxy != "http://proxy.corp.example:8080" || http.CABundle != "/etc/ssl/corp-ca.pem" {
+		t.Errorf("unexpected http settings: %+v", http)
+	}
+
+	headers := cfg.GetHTTPHeaders()
+	if headers["X-Gateway-Key"] != "gw-secret" || headers["X-Team"] != "qa" {
+		t.Errorf("GetHTTPHeaders() = %v", headers)
+	}
+	if DefaultConfig().GetHTTPHeaders() != nil {
+		t.Error("GetHTTPHeaders() should be nil without headers")
+	}
+}
+
+func TestExecSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, ".specverify.yml")
+	configContent := `
+ai_provider: exec
+ai:
+  request_timeout: 90s
+  exec:
+    command: ["./bin/gateway-plugin", "--profile", "ci"]
+    env:
+      GATEWAY_TOKEN: ${SPEC_VERIFY_TEST_GATEWAY_TOKEN}
+      GATEWAY_REGION: ap-northeast-1
+`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv("SPEC_VERIFY_TEST_GATEWAY_TOKEN", "gw-token")
+
+	cfg, err := Load(configFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.AIProvider != "exec" || len(cfg.AI.Exec.Command) != 3 || cfg.AI.Exec.Command[2] != "ci" {
+		t.Errorf("unexpected exec settings: %s %+v", cfg.AIProvider, cfg.AI.Exec)
+	}
+
+	env := cfg.GetExecEnv()
+	if env["GATEWAY_TOKEN"] != "gw-token" || env["GATEWAY_REGION"] != "ap-northeast-1" {
+		t.Errorf("GetExecEnv() = %v", env)
+	}
+	if DefaultConfig().GetExecEnv() != nil {
+		t.Error("GetExecEnv() should be nil without env")
+	}
+}
+
+func TestIsCacheEnabled(t *testing.T) {
+	cfg := DefaultConfig()
+	if !cfg.IsCacheEnabled() {
+		t.Error("cache should be enabled by default")
+	}
+
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, ".specverify.yml")
+	configContent := `
+cache:
+  enabled: false
+  dir: tmp/cache
+`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := Load(configFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.IsCacheEnabled() {
+		t.Error("Expected cache to be disabled")
+	}
+	if cfg.Cache.Dir != "tmp/cache" {
+		t.Errorf("Expected 'tmp/cache', got '%s'", cfg.Cache.Dir)
+	}
+}
+
+func TestEnsembleSettings(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.IsEnsembleEnabled() {
+		t.Error("ensemble should be disabled by default")
+	}
+	if cfg.GetReviewThreshold() != DefaultReviewThreshold {
+		t.Errorf("Expected default review threshold %d, got %d", DefaultReviewThreshold, cfg.GetReviewThreshold())
+	}
+
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, ".specverify.yml")
+	configContent := `
+ensemble:
+  review_threshold: 15
+  members:
+    - provider: claude
+    - provider: openai
+      model: gpt-4o-mini
+    - provider: ollama
+      base_url: http://gpu-server:11434
+`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := Load(configFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !cfg.IsEnsembleEnabled() {
+		t.Error("Expected ensemble to be enabled")
+	}
+	if len(cfg.Ensemble.Members) != 3 {
+		t.Fatalf("Expected 3 members, got %d", len(cfg.Ensemble.Members))
+	}
+	if cfg.Ensemble.Members[1].Model != "gpt-4o-mini" {
+		t.Errorf("Expected 'gpt-4o-mini', got '%s'", cfg.Ensemble.Members[1].Model)
+	}
+	if cfg.Ensemble.Members[2].BaseURL != "http://gpu-server:11434" {
+		t.Errorf("Expected base_url, got '%s'", cfg.Ensemble.Members[2].BaseURL)
+	}
+	if cfg.GetReviewThreshold() != 15 {
+		t.Errorf("Expected review threshold 15, got %d", cfg.GetReviewThreshold())
+	}
+}
+
+func TestProviderChain(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	t.Run("list", func(t *testing.T) {
+		configFile := filepath.Join(tmpDir, "list.yml")
+		if err := os.WriteFile(configFile, []byte("ai_provider: [claude, openai, ollama]\ncode_dir: app/\n"), 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+		cfg, err := Load(configFile)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.AIProvider != "claude" {
+			t.Errorf("Expected primary 'claude', got '%s'", cfg.AIProvider)
+		}
+		if got := cfg.ProviderChain(); !slices.Equal(got, []string{"claude", "openai", "ollama"}) {
+			t.Errorf("ProviderChain() = %v", got)
+		}
+		if cfg.CodeDir != "app/" {
+			t.Errorf("Expected other settings to be loaded, got code_dir '%s'", cfg.CodeDir)
+		}
+
+		// 保存して読み込み直してもチェーンが保たれる
+		saved := filepath.Join(tmpDir, "saved.yml")
+		if err := cfg.Save(saved); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+		reloaded, err := Load(saved)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if got := reloaded.ProviderChain(); !slices.Equal(got, cfg.ProviderChain()) {
+			t.Errorf("reloaded ProviderChain() = %v", got)
+		}
+	})
+
+	t.Run("single provider", func(t *testing.T) {
+		configFile := filepath.Join(tmpDir, "single.yml")
+		if err := os.WriteFile(configFile, []byte("ai_provider: openai\n"), 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+		cfg, err := Load(configFile)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if got := cfg.ProviderChain(); !slices.Equal(got, []string{"openai"}) {
+			t.Errorf("ProviderChain() = %v", got)
+		}
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		configFile := filepath.Join(tmpDir, "empty.yml")
+		if err := os.WriteFile(configFile, []byte("ai_provider: []\n"), 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+		if _, err := Load(configFile); err == nil {
+			t.Error("expected error for an empty ai_provider list")
+		}
+	})
+
+	t.Run("WithProvider with comma-separated names", func(t *testing.T) {
+		configFile := filepath.Join(tmpDir, "list.yml")
+		cfg, err := Load(configFile, WithProvider("openai, gemini"))
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if got := cfg.ProviderChain(); !slices.Equal(got, []string{"openai", "gemini"}) {
+			t.Errorf("ProviderChain() = %v", got)
+		}
+	})
+}
+
+func TestSampleSettings(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.GetSamples() != 1 {
+		t.Errorf("Expected 1 sample by default, got %d", cfg.GetSamples())
+	}
+	if cfg.GetSampleTemperature(0) != nil {
+		t.Error("Expected nil temperature by default")
+	}
+	if cfg.GetUnstableThreshold() != DefaultUnstableThreshold {
+		t.Errorf("Expected default unstable threshold, got %g", cfg.GetUnstableThreshold())
+	}
+	if cfg.UsesLowerBound() {
+		t.Error("fail_under should use the median by default")
+	}
+
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, ".specverify.yml")
+	configContent := `
+options:
+  samples: 5
+  sample_temperatures: [0.0, 0.5]
+  unstable_threshold: 7.5
+  fail_under_mode: lower_bound
+`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := Load(configFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.GetSamples() != 5 {
+		t.Errorf("Expected 5 samples, got %d", cfg.GetSamples())
+	}
+	// サンプル数より少ないtemperatureは繰り返して使用する
+	if temp := cfg.GetSampleTemperature(3); temp == nil || *temp != 0.5 {
+		t.Errorf("Expected temperature 0.5 for sample 4, got %v", temp)
+	}
+	if cfg.GetUnstableThreshold() != 7.5 {
+		t.Errorf("Expected 7.5, got %g", cfg.GetUnstableThreshold())
+	}
+	if !cfg.UsesLowerBound() {
+		t.Error("Expected fail_under to use the lower bound")
+	}
+}
+
+func TestSectionSettings(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.IsSectionsEnabled() {
+		t.Error("section verification should be disabled by default")
+	}
+	if cfg.GetSectionWeight("画面構成") != 1 || cfg.GetSectionWeight("関連ファイル") != 0 {
+		t.Error("Expected default weights of 1, and 0 for related files")
+	}
+
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, ".specverify.yml")
+	configContent := `
+sections:
+  enabled: true
+  weights:
+    バリデーション: 2.5
+    概要: 0
+    関連ファイル: 1
+`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := Load(configFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !cfg.IsSectionsEnabled() {
+		t.Error("Expected section verification to be enabled")
+	}
+	for name, want := range map[string]float64{"バリデーション": 2.5, "概要": 0, "関連ファイル": 1, "画面構成": 1} {
+		if got := cfg.GetSectionWeight(name); got != want {
+			t.Errorf("GetSectionWeight(%s) = %g, want %g", name, got, want)
+		}
+	}
+}
+
+func TestRedactionSettings(t *testing.T) {
+	cfg := DefaultConfig()
+	if !cfg.IsRedactionEnabled() {
+		t.Error("redaction should be enabled by default")
+	}
+
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, ".specverify.yml")
+	configContent := `
+redaction:
+  enabled: false
+  disable: [email, high_entropy]
+  patterns:
+    - name: customer_id
+      regex: 'CUST-\d{8}'
+`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := Load(configFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.IsRedactionEnabled() {
+		t.Error("Expected redaction to be disabled")
+	}
+	if len(cfg.Redaction.Disable) != 2 || cfg.Redaction.Disable[1] != "high_entropy" {
+		t.Errorf("Disable = %v", cfg.Redaction.Disable)
+	}
+	if len(cfg.Redaction.Patterns) != 1 || cfg.Redaction.Patterns[0].Name != "customer_id" || cfg.Redaction.Patterns[0].Regex != `CUST-\d{8}` {
+		t.Errorf("Patterns = %+v", cfg.Redaction.Patterns)
+	}
+}
+
+func TestEgressSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, ".specverify.yml")
+	configContent := `
+egress:
+  allow: ["src/**"]
+  deny: ["**/.env*", "*.pem"]
+  audit_log: .specverify/audit.jsonl
+`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := Load(configFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.Egress.Allow) != 1 || len(cfg.Egress.Deny) != 2 || cfg.Egress.Deny[1] != "*.pem" {
+		t.Errorf("Egress = %+v", cfg.Egress)
+	}
+	if cfg.Egress.AuditLog != ".specverify/audit.jsonl" {
+		t.Errorf("AuditLog = %q", cfg.Egress.AuditLog)
+	}
+}
+
+func TestGetPromptTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, ".specverify.yml")
+	configContent := `
+prompts:
+  verify: prompts/verify.tmpl
+  reduce: prompts/reduce.tmpl
+spec_types:
+  api:
+    prompt: prompts/api.tmpl
+    evidence_prompt: prompts/api_evidence.tmpl
+  ui:
+    code_paths: [ui]
+`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := Load(configFile)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	for specType, want := range map[string]string{"api": "prompts/api.tmpl", "ui": "prompts/verify.tmpl", "domain": "prompts/verify.tmpl"} {
+		if got := cfg.GetPromptTemplate(specType); got != want {
+			t.Errorf("GetPromptTemplate(%s) = %q, want %q", specType, got, want)
+		}
+	}
+
+	if got := cfg.GetEvidenceTemplate("api"); got != "prompts/api_evidence.tmpl" {
+		t.Errorf("GetEvidenceTemplate(api) = %q", got)
+	}
+	if got := cfg.GetEvidenceTemplate("ui"); got != "" {
+		t.Errorf("GetEvidenceTemplate(ui) = %q, want the built-in template", got)
+	}
+	if got := cfg.GetReduceTemplate("api"); got != "prompts/reduce.tmpl" {
+		t.Errorf("GetReduceTemplate(api) = %q", got)
+	}
+
+	if got := DefaultConfig().GetPromptTemplate("ui"); got != "" {
+		t.Errorf("GetPromptTemplate without settings = %q, want the built-in template", got)
+	}
+}
@@ -76,6 +76,8 @@ func GetAPIKeyFromEnv(provider string) string {
 		return os.Getenv("OPENAI_API_KEY")
 	case "gemini":
 		return os.Getenv("GOOGLE_API_KEY")
+	case "azure-openai", "azure":
+		return os.Getenv("AZURE_OPENAI_API_KEY")
 	}
 
 	return ""
@@ -107,10 +107,11 @@ func TestLoadEnvFile_NoFileExists(t *testing.T) {
 func TestGetAPIKeyFromEnv(t *testing.T) {
 	// 既存の環境変数を保存してクリア
 	savedKeys := map[string]string{
-		"SPEC_VERIFY_API_KEY": os.Getenv("SPEC_VERIFY_API_KEY"),
-		"ANTHROPIC_API_KEY":   os.Getenv("ANTHROPIC_API_KEY"),
-		"OPENAI_API_KEY":      os.Getenv("OPENAI_API_KEY"),
-		"GOOGLE_API_KEY":      os.Getenv("GOOGLE_API_KEY"),
+		"SPEC_VERIFY_API_KEY":  os.Getenv("SPEC_VERIFY_API_KEY"),
+		"ANTHROPIC_API_KEY":    os.Getenv("ANTHROPIC_API_KEY"),
+		"OPENAI_API_KEY":       os.Getenv("OPENAI_API_KEY"),
+		"GOOGLE_API_KEY":       os.Getenv("GOOGLE_API_KEY"),
+		"AZURE_OPENAI_API_KEY": os.Getenv("AZURE_OPENAI_API_KEY"),
 	}
 	defer func() {
 		for k, v := range savedKeys {
@@ -166,6 +167,15 @@ func TestGetAPIKeyFromEnv(t *testing.T) {
 			},
 			expected: "google-key",
 		},
+		{
+			name:     "azure-openai provider uses AZURE_OPENAI_API_KEY",
+			provider: "azure-openai",
+			envVars: map[string]string{
+				"AZURE_OPENAI_API_KEY": "azure-key",
+				"OPENAI_API_KEY":       "openai-key",
+			},
+			expected: "azure-key",
+		},
 		{
 			name:     "unknown provider returns empty",
 			provider: "unknown",
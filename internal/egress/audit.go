@@ -0,0 +1,196 @@
+package egress
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/k-totani/spec-verify/internal/ai"
+)
+
+// 監査ログに記録するリクエストの種類
+const (
+	KindVerify  = "verify"
+	KindExtract = "extract"
+)
+
+// File は送信したファイルのパス、バイト数、内容のハッシュ
+type File struct {
+	Path   string `json:"path"`
+	Bytes  int    `json:"bytes"`
+	SHA256 string `json:"sha256"`
+}
+
+// NewFile は送信する内容から File を作成する
+func NewFile(path, content string) File {
+	sum := sha256.Sum256([]byte(content))
+	return File{Path: path, Bytes: len(content), SHA256: hex.EncodeToString(sum[:])}
+}
+
+// AuditEntry は監査ログの1行（AIプロバイダーへの1回の検証またはエンドポイント抽出の依頼）
+// 分割検証や再質問で複数回APIを呼び出す場合も、送信した内容の全体を1行に記録する
+type AuditEntry struct {
+	// 送信した時刻
+	Timestamp time.Time `json:"timestamp"`
+
+	// リクエストの種類（verify, extract）
+	Kind string `json:"kind"`
+
+	// 送信先のプロバイダーとモデル
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+
+	// 検証したSPEC（verify のみ。パスは検証時に指定されたもの）
+	Spec *File `json:"spec,omitempty"`
+
+	// 送信したコードファイル（パスの順）
+	Files []File `json:"files"`
+
+	// 送信したSPECとコードの合計バイト数
+	Bytes int `json:"bytes"`
+}
+
+// auditMu は監査ログへの書き込みを直列化する（同じファイルに複数のプロバイダーから書き込むため）
+var auditMu sync.Mutex
+
+// AuditLog は送信内容を1リクエスト1行のJSONで追記する監査ログ
+type AuditLog struct {
+	path string
+	now  func() time.Time
+}
+
+// NewAuditLog は path に追記する監査ログを作成する
+func NewAuditLog(path string) *AuditLog {
+	return &AuditLog{path: path, now: time.Now}
+}
+
+// Record は entry を追記する（Timestamp が未設定の場合は現在時刻を設定する）
+// 既存の行は変更せず、書き込むたびにファイルを開いて閉じる
+func (l *AuditLog) Record(entry AuditEntry) error {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = l.now().UTC()
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return f.Close()
+}
+
+type contextKey int
+
+const (
+	specKey contextKey = iota
+	filesKey
+)
+
+// WithSpec は監査ログに記録するSPECのパスを ctx に設定する
+func WithSpec(ctx context.Context, specFile string) context.Context {
+	return context.WithValue(ctx, specKey, specFile)
+}
+
+// WithFiles は監査ログに記録する送信ファイルを ctx に設定する
+// エンドポイント抽出のように複数のファイルを結合して送信する場合に使用する
+func WithFiles(ctx context.Context, files []File) context.Context {
+	return context.WithValue(ctx, filesKey, files)
+}
+
+// AuditProvider は inner に送信する前に、送信内容を監査ログに記録する Provider
+// 記録に失敗した場合は送信しない
+type AuditProvider struct {
+	inner ai.Provider
+	log   *AuditLog
+}
+
+// NewAuditProvider は inner への送信を log に記録するプロバイダーを作成する
+func NewAuditProvider(inner ai.Provider, log *AuditLog) *AuditProvider {
+	return &AuditProvider{inner: inner, log: log}
+}
+
+// Name は inner のプロバイダー名を返す
+func (p *AuditProvider) Name() string {
+	return p.inner.Name()
+}
+
+// Model は inner のモデル名を返す
+func (p *AuditProvider) Model() string {
+	return p.inner.Model()
+}
+
+// Verify は送信内容を記録してSPECとコードの一致度を検証する
+func (p *AuditProvider) Verify(ctx context.Context, specContent string, codeContents map[string]string) (*ai.VerificationResult, error) {
+	return p.VerifyWithOptions(ctx, specContent, codeContents, nil)
+}
+
+// VerifyWithOptions は送信内容を記録して検証観点を指定してSPECとコードの一致度を検証する
+func (p *AuditProvider) VerifyWithOptions(ctx context.Context, specContent string, codeContents map[string]string, opts *ai.VerifyOptions) (*ai.VerificationResult, error) {
+	files := make([]File, 0, len(codeContents))
+	for path, content := range codeContents {
+		files = append(files, NewFile(path, content))
+	}
+	specPath, _ := ctx.Value(specKey).(string)
+	spec := NewFile(specPath, specContent)
+	if err := p.record(KindVerify, &spec, files); err != nil {
+		return nil, err
+	}
+
+	if opts != nil {
+		return p.inner.VerifyWithOptions(ctx, specContent, codeContents, opts)
+	}
+	return p.inner.Verify(ctx, specContent, codeContents)
+}
+
+// ExtractEndpoints は送信内容を記録してエンドポイントを抽出する
+// WithFiles で送信ファイルが指定されていない場合は、送信する内容全体を1つのファイルとして記録する
+func (p *AuditProvider) ExtractEndpoints(ctx context.Context, opts *ai.ExtractOptions, codeContent string) ([]ai.EndpointResult, error) {
+	files, ok := ctx.Value(filesKey).([]File)
+	if !ok {
+		files = []File{NewFile("", codeContent)}
+	}
+	if err := p.record(KindExtract, nil, files); err != nil {
+		return nil, err
+	}
+	return p.inner.ExtractEndpoints(ctx, opts, codeContent)
+}
+
+// record は1回分のリクエストを監査ログに追記する
+func (p *AuditProvider) record(kind string, spec *File, files []File) error {
+	sorted := append([]File(nil), files...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+
+	entry := AuditEntry{
+		Kind:     kind,
+		Provider: p.inner.Name(),
+		Model:    p.inner.Model(),
+		Spec:     spec,
+		Files:    sorted,
+	}
+	if spec != nil {
+		entry.Bytes += spec.Bytes
+	}
+	for _, f := range sorted {
+		entry.Bytes += f.Bytes
+	}
+	return p.log.Record(entry)
+}
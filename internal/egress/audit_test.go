@@ -0,0 +1,117 @@
+package egress
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/k-totani/spec-verify/internal/ai"
+)
+
+// stubProvider は固定の結果を返し、呼び出し回数を数えるテスト用プロバイダー
+type stubProvider struct {
+	calls int
+}
+
+func (p *stubProvider) Verify(ctx context.Context, specContent string, codeContents map[string]string) (*ai.VerificationResult, error) {
+	return p.VerifyWithOptions(ctx, specContent, codeContents, nil)
+}
+
+func (p *stubProvider) VerifyWithOptions(ctx context.Context, specContent string, codeContents map[string]string, opts *ai.VerifyOptions) (*ai.VerificationResult, error) {
+	p.calls++
+	return &ai.VerificationResult{MatchPercentage: 80}, nil
+}
+
+func (p *stubProvider) ExtractEndpoints(ctx context.Context, opts *ai.ExtractOptions, codeContent string) ([]ai.EndpointResult, error) {
+	p.calls++
+	return nil, nil
+}
+
+func (p *stubProvider) Name() string  { return "claude" }
+func (p *stubProvider) Model() string { return "claude-sonnet-4-20250514" }
+
+// readAuditLog は監査ログの全ての行を読み込む
+func readAuditLog(t *testing.T, path string) []AuditEntry {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid audit line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestAuditProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "audit.jsonl")
+	log := NewAuditLog(path)
+	log.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	inner := &stubProvider{}
+	p := NewAuditProvider(inner, log)
+
+	ctx := WithSpec(context.Background(), "specs/api/users.md")
+	code := map[string]string{"src/b.ts": "bbbb", "src/a.ts": "aa"}
+	if _, err := p.VerifyWithOptions(ctx, "# spec", code, &ai.VerifyOptions{}); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	files := []File{NewFile("src/routes.ts", "router.get('/users')")}
+	if _, err := p.ExtractEndpoints(WithFiles(context.Background(), files), nil, "=== File: src/routes.ts ===\nrouter.get('/users')"); err != nil {
+		t.Fatalf("ExtractEndpoints failed: %v", err)
+	}
+
+	entries := readAuditLog(t, path)
+	if len(entries) != 2 || inner.calls != 2 {
+		t.Fatalf("entries = %+v, calls = %d", entries, inner.calls)
+	}
+	verify := entries[0]
+	if verify.Kind != KindVerify || verify.Provider != "claude" || verify.Model != "claude-sonnet-4-20250514" || !verify.Timestamp.Equal(log.now()) {
+		t.Errorf("verify entry = %+v", verify)
+	}
+	if verify.Spec == nil || verify.Spec.Path != "specs/api/users.md" || verify.Spec.Bytes != len("# spec") {
+		t.Errorf("spec = %+v", verify.Spec)
+	}
+	if len(verify.Files) != 2 || verify.Files[0] != NewFile("src/a.ts", "aa") || verify.Bytes != 6+2+4 {
+		t.Errorf("files = %+v, bytes = %d", verify.Files, verify.Bytes)
+	}
+	if extract := entries[1]; extract.Kind != KindExtract || extract.Spec != nil || len(extract.Files) != 1 || extract.Files[0] != files[0] {
+		t.Errorf("extract entry = %+v", extract)
+	}
+}
+
+func TestAuditProvider_DoesNotSendWhenLoggingFails(t *testing.T) {
+	// ディレクトリと同じ名前のファイルがあるため、監査ログのディレクトリを作成できない
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "logs")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	inner := &stubProvider{}
+	p := NewAuditProvider(inner, NewAuditLog(filepath.Join(blocker, "audit.jsonl")))
+
+	if _, err := p.Verify(context.Background(), "# spec", nil); err == nil {
+		t.Fatal("expected error but got nil")
+	}
+	if inner.calls != 0 {
+		t.Errorf("the provider was called %d times, want 0", inner.calls)
+	}
+}
+
+func TestNewFile(t *testing.T) {
+	f := NewFile("a.txt", "hello")
+	if f.Bytes != 5 || f.SHA256 != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Errorf("NewFile = %+v", f)
+	}
+}
@@ -0,0 +1,138 @@
+// Package egress はAIプロバイダーに送信するファイルの制限（許可/拒否のglob）と、送信内容の監査ログを提供する
+package egress
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Policy は送信してよいファイルを許可/拒否のglobで判定する
+type Policy struct {
+	allow []glob
+	deny  []glob
+}
+
+// glob はコンパイルしたglobパターン
+type glob struct {
+	re *regexp.Regexp
+
+	// パターンに / を含まない場合はファイル名だけと照合する（.gitignore と同様）
+	baseOnly bool
+}
+
+// NewPolicy は許可/拒否のglobから Policy を作成する
+// allow が空の場合は deny に一致しない全てのファイルを許可する。両方に一致する場合は deny を優先する
+// パターンでは * と ? は / を含まない文字列、** は任意の深さのディレクトリに一致する
+func NewPolicy(allow, deny []string) (*Policy, error) {
+	p := &Policy{}
+	var err error
+	if p.allow, err = compileGlobs(allow); err != nil {
+		return nil, err
+	}
+	if p.deny, err = compileGlobs(deny); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Allowed は path のファイルを送信してよいかを返す
+// Policy が nil の場合は全て許可する
+func (p *Policy) Allowed(path string) bool {
+	if p == nil {
+		return true
+	}
+	path = normalizePath(path)
+	if matchAny(p.deny, path) {
+		return false
+	}
+	return len(p.allow) == 0 || matchAny(p.allow, path)
+}
+
+// Denied は path のファイルが deny のパターンに一致するかを返す
+// allow でコードを限定した場合も送信するファイル（SPECなど）の判定に使用する
+func (p *Policy) Denied(path string) bool {
+	return p != nil && matchAny(p.deny, normalizePath(path))
+}
+
+// Filter は paths を送信してよいファイルと、除外するファイルに分ける
+func (p *Policy) Filter(paths []string) (allowed, dropped []string) {
+	for _, path := range paths {
+		if p.Allowed(path) {
+			allowed = append(allowed, path)
+		} else {
+			dropped = append(dropped, path)
+		}
+	}
+	return allowed, dropped
+}
+
+// normalizePath は照合に使用するパスを返す
+// 絶対パスはカレントディレクトリ配下であれば相対パスにし、区切り文字は / に統一する
+func normalizePath(path string) string {
+	path = filepath.Clean(path)
+	if filepath.IsAbs(path) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+func matchAny(globs []glob, path string) bool {
+	base := path[strings.LastIndex(path, "/")+1:]
+	for _, g := range globs {
+		if g.baseOnly && g.re.MatchString(base) || !g.baseOnly && g.re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func compileGlobs(patterns []string) ([]glob, error) {
+	globs := make([]glob, 0, len(patterns))
+	for _, pattern := range patterns {
+		g, err := compileGlob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		globs = append(globs, g)
+	}
+	return globs, nil
+}
+
+// compileGlob はglobパターンを正規表現に変換する
+func compileGlob(pattern string) (glob, error) {
+	if pattern == "" {
+		return glob{}, fmt.Errorf("empty egress pattern")
+	}
+	trimmed := strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(trimmed); i++ {
+		c := trimmed[i]
+		switch {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+
+	// ワイルドカード以外の文字はエスケープしているため、コンパイルは失敗しない
+	return glob{re: regexp.MustCompile(b.String()), baseOnly: !strings.Contains(trimmed, "/")}, nil
+}
@@ -0,0 +1,75 @@
+package egress
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPolicy_Allowed(t *testing.T) {
+	p, err := NewPolicy([]string{"src/**", "lib/*.go"}, []string{"**/.env*", "*.pem", "src/**/fixtures/**"})
+	if err != nil {
+		t.Fatalf("NewPolicy failed: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"src/routes/users.ts", true},
+		{"./src/App.tsx", true},
+		{"lib/util.go", true},
+		{"lib/sub/util.go", false},
+		{"docs/readme.md", false},
+		{"src/.env.example", false},
+		{".env", false},
+		{"src/certs/server.pem", false},
+		{"src/test/fixtures/customers.json", false},
+	}
+	for _, tt := range tests {
+		if got := p.Allowed(tt.path); got != tt.want {
+			t.Errorf("Allowed(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPolicy_Denied(t *testing.T) {
+	p, err := NewPolicy([]string{"src/**"}, []string{"specs/internal/**"})
+	if err != nil {
+		t.Fatalf("NewPolicy failed: %v", err)
+	}
+	// allow に一致しないだけのファイルは拒否しない
+	if p.Denied("specs/ui/login.md") || !p.Denied("specs/internal/admin.md") {
+		t.Error("Denied should only match the deny patterns")
+	}
+	var nilPolicy *Policy
+	if nilPolicy.Denied("specs/internal/admin.md") {
+		t.Error("a nil Policy should not deny anything")
+	}
+}
+
+func TestPolicy_Filter(t *testing.T) {
+	p, _ := NewPolicy(nil, []string{"**/secrets/**"})
+	abs, err := filepath.Abs(filepath.Join("src", "secrets", "keys.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	allowed, dropped := p.Filter([]string{"src/a.ts", abs, "b.ts"})
+	if len(allowed) != 2 || allowed[1] != "b.ts" {
+		t.Errorf("allowed = %v", allowed)
+	}
+	if len(dropped) != 1 || dropped[0] != abs {
+		t.Errorf("dropped = %v, want the absolute path under the working directory", dropped)
+	}
+
+	var unrestricted *Policy
+	if allowed, dropped := unrestricted.Filter([]string{".env"}); len(allowed) != 1 || dropped != nil {
+		t.Error("a nil Policy should allow every file")
+	}
+}
+
+func TestNewPolicy_EmptyPattern(t *testing.T) {
+	if _, err := NewPolicy(nil, []string{""}); err == nil {
+		t.Error("expected error for an empty pattern")
+	}
+}
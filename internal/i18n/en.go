@@ -0,0 +1,308 @@
+package i18n
+
+// en は英語のメッセージカタログ
+var en = map[string]string{
+	// 言語
+	"language.name":             "English",
+	"warn.unsupported_language": "⚠️  Warning: language '%s' is not supported (supported: %s). Falling back to Japanese",
+
+	// ヘルプ
+	"usage": `spec-verify - verification tool for spec-driven development
+
+Usage:
+  spec-verify <command> [options]
+  go run github.com/k-totani/spec-verify/cmd/spec-verify@latest <command> [options]
+
+Commands:
+  init              Initialize the config file
+  check [type...]   Verify how well the code matches the specs
+                    type: ui, api, domain, model, etc. (defined in the config)
+                    Multiple types allowed; all types if omitted
+  types             List defined spec types
+  groups            List defined groups
+  endpoints         List API endpoints
+  coverage          Route coverage report (spec coverage of pages/APIs)
+  cache prune       Delete cached verification results (--older-than 720h, --all)
+  prompt show SPEC  Show the prompt sent to the AI for a spec (no API calls)
+  version           Show the version
+  help              Show this help
+
+Options:
+  --format json      Output JSON (for CI)
+  --threshold N      Pass threshold (default: 50)
+  --fail-under N     Per-spec threshold (fail if any spec is below N%%)
+  --fail-under-mode M
+                     Score used for the per-spec threshold (median, lower_bound: minimum)
+  --group, -g NAME   Verify a group
+  --config FILE      Config file
+  --api-key KEY      API key (takes precedence over environment variables)
+  --provider NAME    AI provider (claude, openai, gemini, ollama, openai-compatible, azure-openai, exec, heuristic, replay)
+                     Comma-separated names (e.g. claude,openai) are tried in order on failure
+  --base-url URL     Base URL of the AI provider (for ollama, openai-compatible)
+  --model NAME       AI model (e.g. claude-sonnet-4-20250514, gpt-4o)
+  --cassette FILE    Record AI responses to a cassette (replayed with --provider replay)
+  --no-cache         Do not use cached verification results
+  --refresh-cache    Verify without reading the cache and overwrite it with the results
+  --dry-run          Estimate tokens and cost without calling the API (check)
+  --sections         Verify each ## section of a spec and show per-section scores (check)
+  --lang LANG        Language of the output and the AI's answers (%s)
+
+Environment Variables (precedence: --api-key > environment variables > .env > config file):
+  ANTHROPIC_API_KEY    Claude API key
+  OPENAI_API_KEY       OpenAI API key
+  GOOGLE_API_KEY       Gemini API key
+  AZURE_OPENAI_API_KEY Azure OpenAI API key
+  SPEC_VERIFY_API_KEY  Generic API key (used for any provider)
+
+.env File Support:
+  A .env file in the project root is loaded automatically.
+  Existing environment variables are not overwritten.
+
+Examples:
+  # Basic usage
+  spec-verify init
+  spec-verify check
+  spec-verify check ui
+
+  # Run directly with go run
+  go run github.com/k-totani/spec-verify/cmd/spec-verify@latest check
+
+  # Pass the API key directly
+  spec-verify check --api-key sk-xxx
+
+  # Multiple types / groups
+  spec-verify check domain model          # multiple types
+  spec-verify check --group backend       # verify a group
+
+  # CI
+  spec-verify check --format json
+  spec-verify check api --threshold 70
+  spec-verify coverage --format json
+  spec-verify coverage --fail-under 80   # fail if coverage is below 80%%
+
+  # Cost estimate (no API calls)
+  spec-verify check --dry-run
+  spec-verify check api --dry-run --format json
+
+  # Cache
+  spec-verify check --no-cache           # verify everything without the cache
+  spec-verify cache prune --older-than 168h
+
+  # Inspect the prompt template
+  spec-verify prompt show specs/ui/login.md
+
+  # Output in English
+  spec-verify check --lang en`,
+
+	// 共通のエラーとヒント
+	"error.load_config":       "Error: failed to load the config file: %v",
+	"error.no_api_key":        "Error: no API key is configured.",
+	"error.create_provider":   "Error: failed to create the AI provider: %v",
+	"warn.undefined_types_in": "⚠️  Warning: %s contains undefined types: %s",
+	"warn.undefined_types":    "⚠️  Warning: undefined types specified: %s",
+	"warn.heuristic_no_key":   "⚠️  No API key is configured; running heuristic verification (no AI)",
+	"hint.list_types":         "To list defined types: spec-verify types",
+	"hint.list_groups":        "To list defined groups: spec-verify groups",
+	"label.group":             "group '%s'",
+
+	// init
+	"init.exists":         "Config file %s already exists. Overwrite? [y/N] ",
+	"init.cancelled":      "Cancelled.",
+	"error.create_config": "Error: failed to create the config file: %v",
+	"init.created":        "✅ Created config file %s.",
+	"init.next_steps": `
+Next steps:
+1. Edit the config file to match your project
+2. Set the ANTHROPIC_API_KEY environment variable
+3. Put your spec files in the specs/ directory
+4. Run spec-verify check`,
+
+	// check
+	"error.fail_under_mode":      "Error: fail_under_mode must be %s or %s: %s",
+	"error.undefined_group":      "Error: group '%s' is not defined.",
+	"error.group_no_valid_types": "Error: group '%s' has no valid types",
+	"error.no_valid_types":       "Error: no valid types",
+	"error.estimate":             "Error: failed to estimate: %v",
+	"hint.set_api_key":           "Set the ANTHROPIC_API_KEY environment variable or add api_key to the config file.",
+	"hint.heuristic_check":       "To verify without AI, use --provider heuristic.",
+	"error.create_verifier":      "Error: failed to create the verifier: %v",
+	"error.verify":               "Error: verification failed: %v",
+	"check.start":                "🔍 Starting spec verification...",
+	"check.group":                "   Group: %s (types: %s)",
+	"check.types":                "   Types: %s",
+
+	// check --dry-run
+	"estimate.header":         "💰 Cost estimate (dry run: no API calls)",
+	"estimate.provider":       "   Provider: %s / Model: %s",
+	"estimate.calls_per_spec": "   API calls per spec: %d",
+	"estimate.price":          "   Price: input $%.2f / output $%.2f (per million tokens)",
+	"estimate.price_unknown":  "   ⚠️  The model is not in the price table (set ai.pricing to show costs)",
+	"estimate.no_code":        "   Related code: 0 files (no API calls)",
+	"estimate.code":           "   Related code: %d files (%s)",
+	"estimate.tokens":         "   Estimated tokens: input %d / output %d",
+	"estimate.cached":         "   💾 Cached (no API calls)",
+	"estimate.cost":           "   Estimated cost: $%.4f",
+	"estimate.total":          "📊 Estimate total",
+	"estimate.requests":       "   API requests: %d",
+	"estimate.files":          "   Code files: %d",
+	"estimate.bytes":          "   Payload size: %s",
+
+	// 検証結果
+	"result.title":              "   Title: %s",
+	"result.path":               "   Path: %s",
+	"result.code":               "   Related code: %d files",
+	"result.redacted":           "   🔒 Redacted %d secrets before sending (%s)",
+	"result.dropped":            "   🚫 Not sent (denied by egress policy): %s",
+	"result.cached":             "   💾 Using cached result",
+	"result.error":              "   ❌ Error: %v",
+	"result.skipped":            "   ⏭️  Skipped: %s",
+	"result.no_verification":    "   ⚠️  No verification result",
+	"result.match":              "   %s Match: %d%%%s",
+	"result.below_threshold":    " ← Below threshold (%d%%)",
+	"result.retries":            "   🔁 Retries: %d",
+	"result.member_error":       "%s/%s error",
+	"result.ensemble":           "   🗳️  Ensemble: %s (spread %d)",
+	"result.needs_review":       "   👀 The providers disagree. Please review",
+	"result.samples":            "   🎲 Samples: %d (min %d%% / max %d%%, stddev %.1f)",
+	"result.samples_failed":     "   ⚠️  %d samples failed",
+	"result.unstable":           "   🌀 Results vary between samples (unstable)",
+	"result.unstable_matched":   "      ~ %s (matched: %d/%d)",
+	"result.unstable_unmatched": "      ~ %s (unmatched: %d/%d)",
+	"result.provider":           "   🤖 Provider: %s/%s",
+	"result.usage":              "   📈 %s/%s: %d input / %d output tokens, %s, %d API calls",
+	"result.fallback":           "   🧮 The AI call failed; using the result of %s",
+	"result.sections":           "   📑 By section:",
+	"result.section":            "      %s %3d%% %s (weight %g)",
+	"result.section_error":      "      ❌ %s (weight %g): %s",
+	"result.section_skipped":    "      ⏭️  %s (weight %g): %s",
+	"result.chunks":             "   🧩 The code did not fit in the context window and was verified in %d chunks",
+	"result.repaired":           "   🩹 Repaired the JSON in the response",
+	"result.reasked":            "   🩹 The response could not be parsed and the model was asked again",
+	"result.matched":            "   ✓ Matched:",
+	"result.unmatched":          "   ✗ Unmatched:",
+	"result.more_items":         "     ... %d more",
+
+	// サマリー
+	"summary.header":              "📊 Summary",
+	"summary.total_specs":         "   Total specs: %d",
+	"summary.average":             "   Average match: %.1f%%",
+	"summary.high":                "   High match (≥80%%): %d",
+	"summary.low":                 "   Low match (<50%%): %d",
+	"summary.cached":              "   Cached: %d",
+	"summary.redacted":            "   🔒 Redacted secrets: %d",
+	"summary.dropped":             "   🚫 Files not sent (denied by egress policy): %d",
+	"summary.skipped":             "   ⏭️  Skipped over budget: %d (exit code %d)",
+	"summary.details":             "   Details:",
+	"summary.section_averages":    "   Average by section:",
+	"summary.section_average":     "   %s %5.1f%% %s (%d specs)",
+	"summary.usage_by_model":      "📈 API usage by provider/model:",
+	"summary.usage_by_spec_type":  "📈 API usage by spec type:",
+	"summary.usage_total":         "   %s: %d specs, %d input / %d output tokens, %s, %d API calls",
+	"summary.review":              "👀 Needs review (providers disagree): %d",
+	"summary.review_spec":         "   - %s (%d%%, spread %d) : %s",
+	"summary.unstable":            "🌀 Unstable (results vary between samples): %d",
+	"summary.unstable_spec":       "   - %s (%d%%, %d-%d%%, stddev %.1f) : %s",
+	"summary.failing":             "❌ Below the per-spec threshold (< %d%%%s): %d",
+	"summary.failing_lower_bound": ", judged by the lower bound",
+
+	// endpoints
+	"error.no_api_sources":    "Error: api_sources is not configured.",
+	"hint.heuristic_extract":  "To extract without AI, use --provider heuristic.",
+	"hint.add_api_sources":    "Add api_sources to the config file like this:",
+	"endpoints.extracting":    "📡 Extracting API endpoints...",
+	"error.extract_endpoints": "Error: failed to extract endpoints: %v",
+	"endpoints.none":          "No endpoints found.",
+	"endpoints.header":        "📡 Detected endpoints (%d)",
+	"endpoints.source":        "📁 %s (%d)",
+	"endpoints.dropped":       "🚫 Not sent (denied by egress policy): %s",
+	"endpoints.redacted":      "🔒 Redacted %d secrets before sending for route extraction (%s)",
+	"endpoints.repaired":      "🩹 Endpoints extracted from a repaired JSON response: %d",
+	"endpoints.reasked":       "🩹 Endpoints extracted after re-asking for an unparsable response: %d",
+
+	// coverage
+	"hint.coverage_api_sources": "The coverage report requires API endpoint extraction settings.",
+	"coverage.generating":       "📊 Generating the API coverage report...",
+	"error.coverage":            "Error: failed to generate the coverage report: %v",
+	"coverage.below_threshold":  "❌ Coverage is below the threshold: %.1f%% < %d%%",
+	"coverage.header":           "📊 Route coverage report",
+	"coverage.threshold":        "   (threshold: %d%%)",
+	"coverage.overall":          "%s Overall coverage: %.1f%%",
+	"coverage.total_routes":     "   Total routes: %d",
+	"coverage.covered":          "   Covered (with spec): %d",
+	"coverage.uncovered":        "   Uncovered (no spec): %d",
+	"coverage.total_specs":      "   Total specs: %d",
+	"coverage.orphaned":         "   Orphaned specs (no route): %d",
+	"coverage.by_category":      "📂 Coverage by category",
+	"coverage.category":         "   %s Coverage: %.1f%% (%d/%d)",
+	"coverage.category_ui":      "Pages (UI)",
+	"coverage.uncovered_items":  "   ❌ Uncovered: ",
+	"coverage.more_items":       "%s ... %d more",
+	"coverage.covered_routes":   "✅ Covered routes (%d)",
+	"coverage.uncovered_routes": "❌ Uncovered routes (%d)",
+	"coverage.orphaned_specs":   "⚠️  Orphaned specs (no matching route) (%d)",
+
+	// types
+	"types.none":             "No spec types are defined.",
+	"types.hint_add":         "Add spec_types or mapping to the config file.",
+	"types.header":           "📋 Defined spec types",
+	"types.code_paths":       "   Code paths: %s",
+	"types.focus":            "   Verification focus:",
+	"types.file_patterns":    "   File patterns: %s",
+	"types.exclude_patterns": "   Exclude patterns: %s",
+
+	// groups
+	"groups.none":     "No groups are defined.",
+	"groups.hint_add": "Add groups to the config file.",
+	"groups.example": `
+Example:
+groups:
+  frontend:
+    types: [ui]
+    description: "Frontend"
+  backend:
+    types: [api, service]
+    description: "Backend"`,
+	"groups.header":      "📦 Defined groups",
+	"groups.types":       "   Types: %s",
+	"groups.description": "   Description: %s",
+	"groups.usage": `
+Usage:
+  spec-verify check --group <group name>`,
+
+	// cache
+	"cache.usage":      "Usage: spec-verify cache prune [--older-than 720h] [--all] [--config FILE]",
+	"error.older_than": "Error: invalid --older-than value: %s",
+	"error.prune":      "Error: failed to prune the cache: %v",
+	"cache.pruned":     "🧹 Pruned the cache: %d entries (%.1f KB), %d remaining [%s]",
+
+	// prompt
+	"prompt.usage":         "Usage: spec-verify prompt show <spec file> [--sections] [--config FILE]",
+	"error.render_prompt":  "Error: failed to build the prompt: %v",
+	"prompt.template":      "📝 Template: %s",
+	"prompt.no_code":       "No related code was found, so this spec is not sent to the AI.",
+	"prompt.section":       "===== Section: %s =====",
+	"prompt.chunked":       "✂️  The code does not fit in the context window, so it is split and evidence is extracted from each chunk (template: %s). The final verification prompt is built from the extracted evidence (template: %s)",
+	"prompt.chunk":         "===== Chunk %d/%d =====",
+	"prompt.section_chunk": "===== Section: %s / chunk %d/%d =====",
+
+	// 検証（verifier）
+	"verify.no_code_item":    "No matching code found",
+	"verify.no_code_notes":   "Possibly not implemented yet",
+	"budget.max_input_bytes": "Payload of %d bytes exceeds max_input_bytes_per_spec (%d)",
+	"budget.max_tokens":      "Reached max_tokens_per_run (%d) (used: %d)",
+	"budget.max_cost":        "Reached max_cost_per_run ($%.2f) (used: $%.4f)",
+	"focus.layout":           "Layout: the elements described in the spec exist in the code",
+	"focus.state":            "State management: the states and hooks described in the spec are used",
+	"focus.flow":             "Flow: the processing flow described in the spec is implemented",
+	"focus.validation":       "Validation: the validation rules described in the spec are implemented",
+	"focus.error_handling":   "Error handling: the error cases described in the spec are handled",
+
+	// ヒューリスティック検証
+	"heuristic.route":      "route %s",
+	"heuristic.identifier": "identifier %s",
+	"heuristic.label":      "UI label \"%s\"",
+	"heuristic.message":    "error message \"%s\"",
+	"heuristic.constraint": "validation %s: %s",
+	"heuristic.notes":      "Heuristic verification (no AI): of %d items, %d were found in the code. Only string matches are checked, so the behavior is not verified",
+	"heuristic.fallback":   "This is the result of heuristic verification because the call to %s failed (%v)\n%s",
+}
@@ -0,0 +1,78 @@
+// Package i18n はCLIの出力やAIへの指示に使用するメッセージを言語ごとのカタログから提供する
+package i18n
+
+import (
+	"fmt"
+	"sort"
+)
+
+// 対応している言語
+const (
+	Japanese = "ja"
+	English  = "en"
+
+	// DefaultLanguage は言語が指定されていない場合に使用する言語
+	DefaultLanguage = Japanese
+)
+
+// catalogs は言語ごとのメッセージカタログ（キー → fmt 形式のメッセージ）
+// 言語を追加する場合はカタログを定義してここに登録する。全てのカタログは同じキーを持つ必要がある
+var catalogs = map[string]map[string]string{
+	Japanese: ja,
+	English:  en,
+}
+
+// Printer は指定した言語でメッセージを整形する
+type Printer struct {
+	lang     string
+	messages map[string]string
+}
+
+// New は言語のメッセージを整形する Printer を作成する
+// 対応していない言語の場合はデフォルトの言語を使用する
+func New(lang string) *Printer {
+	if !Supported(lang) {
+		lang = DefaultLanguage
+	}
+	return &Printer{lang: lang, messages: catalogs[lang]}
+}
+
+// Supported は言語のカタログがあるかどうかを返す
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Languages は対応している言語の一覧を返す
+func Languages() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Language は Printer の言語を返す
+func (p *Printer) Language() string {
+	return p.lang
+}
+
+// Has はカタログにメッセージがあるかどうかを返す
+func (p *Printer) Has(key string) bool {
+	_, ok := p.messages[key]
+	return ok
+}
+
+// Sprintf はキーのメッセージを args で整形して返す
+// カタログにキーがない場合はデフォルトの言語のメッセージ、それもない場合はキーをそのまま返す
+func (p *Printer) Sprintf(key string, args ...any) string {
+	format, ok := p.messages[key]
+	if !ok {
+		format, ok = catalogs[DefaultLanguage][key]
+	}
+	if !ok {
+		return key
+	}
+	return fmt.Sprintf(format, args...)
+}
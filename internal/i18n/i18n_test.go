@@ -0,0 +1,61 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+// verbPattern は fmt の書式指定子（%% を含む）
+var verbPattern = regexp.MustCompile(`%[-+# 0]*[0-9]*(\.[0-9]+)?[a-zA-Z%]`)
+
+func TestCatalogsHaveSameKeys(t *testing.T) {
+	base := catalogs[DefaultLanguage]
+	for lang, messages := range catalogs {
+		for key := range base {
+			if _, ok := messages[key]; !ok {
+				t.Errorf("catalog %s is missing key %q", lang, key)
+			}
+		}
+		for key := range messages {
+			if _, ok := base[key]; !ok {
+				t.Errorf("catalog %s has key %q that is not in the %s catalog", lang, key, DefaultLanguage)
+			}
+		}
+	}
+}
+
+func TestCatalogsHaveSameVerbs(t *testing.T) {
+	base := catalogs[DefaultLanguage]
+	for lang, messages := range catalogs {
+		for key, message := range messages {
+			want := verbPattern.FindAllString(base[key], -1)
+			if got := verbPattern.FindAllString(message, -1); !slices.Equal(got, want) {
+				t.Errorf("catalog %s key %q has verbs %v, want %v", lang, key, got, want)
+			}
+		}
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	p := New(English)
+	if p.Language() != English {
+		t.Errorf("Language() = %q, want %q", p.Language(), English)
+	}
+	if got := p.Sprintf("result.retries", 2); got != "   🔁 Retries: 2" {
+		t.Errorf("Sprintf = %q", got)
+	}
+	if got := p.Sprintf("no.such.key"); got != "no.such.key" {
+		t.Errorf("Sprintf for a missing key = %q, want the key", got)
+	}
+
+	if New("xx").Language() != DefaultLanguage {
+		t.Error("an unsupported language should fall back to the default language")
+	}
+	if !Supported(Japanese) || Supported("xx") {
+		t.Error("Supported returned an unexpected result")
+	}
+	if got := Languages(); !slices.Equal(got, []string{English, Japanese}) {
+		t.Errorf("Languages() = %v", got)
+	}
+}
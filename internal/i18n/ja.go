@@ -0,0 +1,308 @@
+package i18n
+
+// ja は日本語のメッセージカタログ
+var ja = map[string]string{
+	// 言語
+	"language.name":             "日本語",
+	"warn.unsupported_language": "⚠️  警告: 言語 '%s' には対応していません（対応: %s）。日本語で出力します",
+
+	// ヘルプ
+	"usage": `spec-verify - SPEC駆動開発のための検証ツール
+
+Usage:
+  spec-verify <command> [options]
+  go run github.com/k-totani/spec-verify/cmd/spec-verify@latest <command> [options]
+
+Commands:
+  init              設定ファイルを初期化
+  check [type...]   SPECとコードの一致度を検証
+                    type: ui, api, domain, model 等（設定で定義）
+                    複数指定可能、省略で全て
+  types             定義済みSPECタイプ一覧を表示
+  groups            定義済みグループ一覧を表示
+  endpoints         APIエンドポイント一覧を表示
+  coverage          ルートカバレッジレポート（ページ/APIに対するSPEC網羅率）
+  cache prune       検証結果キャッシュを削除（--older-than 720h, --all）
+  prompt show SPEC  SPECの検証でAIに送信するプロンプトを表示（APIは呼び出さない）
+  version           バージョンを表示
+  help              このヘルプを表示
+
+Options:
+  --format json      JSON形式で出力（CI向け）
+  --threshold N      合格ラインを指定（デフォルト: 50）
+  --fail-under N     個別閾値を指定（N%%未満のSPECがあれば失敗）
+  --fail-under-mode M
+                     個別閾値の判定に使う一致度（median: 中央値, lower_bound: 最小値）
+  --group, -g NAME   グループ単位で検証
+  --config FILE      設定ファイルを指定
+  --api-key KEY      APIキーを直接指定（環境変数より優先）
+  --provider NAME    AIプロバイダーを指定（claude, openai, gemini, ollama, openai-compatible, azure-openai, exec, heuristic, replay）
+                     カンマ区切り（例: claude,openai）で指定すると、失敗時に順に代替する
+  --base-url URL     AIプロバイダーのベースURLを指定（ollama, openai-compatible 用）
+  --model NAME       AIモデルを指定（例: claude-sonnet-4-20250514, gpt-4o）
+  --cassette FILE    AIの応答をカセットに記録する（--provider replay では記録を再生する）
+  --no-cache         検証結果キャッシュを使用しない
+  --refresh-cache    キャッシュを読まずに検証し、結果でキャッシュを更新する
+  --dry-run          APIを呼び出さずにトークン数とコストを見積もる（check）
+  --sections         SPECの ## セクションごとに検証し、セクション別の一致度を表示（check）
+  --lang LANG        出力とAIの回答の言語を指定（%s）
+
+Environment Variables (優先順位: --api-key > 環境変数 > .env > 設定ファイル):
+  ANTHROPIC_API_KEY    Claude APIキー
+  OPENAI_API_KEY       OpenAI APIキー
+  GOOGLE_API_KEY       Gemini APIキー
+  AZURE_OPENAI_API_KEY Azure OpenAI APIキー
+  SPEC_VERIFY_API_KEY  汎用APIキー（プロバイダーに関わらず使用可能）
+
+.env File Support:
+  プロジェクトルートに .env ファイルを配置すると自動的に読み込まれます。
+  既存の環境変数は上書きされません。
+
+Examples:
+  # 基本的な使い方
+  spec-verify init
+  spec-verify check
+  spec-verify check ui
+
+  # go run で直接実行
+  go run github.com/k-totani/spec-verify/cmd/spec-verify@latest check
+
+  # APIキーを直接指定
+  spec-verify check --api-key sk-xxx
+
+  # 複数タイプ/グループ指定
+  spec-verify check domain model          # 複数タイプ指定
+  spec-verify check --group backend       # グループ単位で検証
+
+  # CI向け
+  spec-verify check --format json
+  spec-verify check api --threshold 70
+  spec-verify coverage --format json
+  spec-verify coverage --fail-under 80   # カバレッジ80%%未満で失敗
+
+  # コスト見積もり（APIは呼び出さない）
+  spec-verify check --dry-run
+  spec-verify check api --dry-run --format json
+
+  # キャッシュ
+  spec-verify check --no-cache           # キャッシュを使わずに全て検証
+  spec-verify cache prune --older-than 168h
+
+  # プロンプトテンプレートの確認
+  spec-verify prompt show specs/ui/login.md
+
+  # 英語で出力
+  spec-verify check --lang en`,
+
+	// 共通のエラーとヒント
+	"error.load_config":       "エラー: 設定ファイルの読み込みに失敗しました: %v",
+	"error.no_api_key":        "エラー: APIキーが設定されていません。",
+	"error.create_provider":   "エラー: AIプロバイダーの作成に失敗しました: %v",
+	"warn.undefined_types_in": "⚠️  警告: %sに存在しないタイプが含まれています: %s",
+	"warn.undefined_types":    "⚠️  警告: 存在しないタイプが指定されています: %s",
+	"warn.heuristic_no_key":   "⚠️  APIキーが設定されていないため、ヒューリスティック検証（AI未使用）で実行します",
+	"hint.list_types":         "定義済みタイプを確認するには: spec-verify types",
+	"hint.list_groups":        "定義済みグループを確認するには: spec-verify groups",
+	"label.group":             "グループ '%s'",
+
+	// init
+	"init.exists":         "設定ファイル %s は既に存在します。上書きしますか？ [y/N] ",
+	"init.cancelled":      "キャンセルしました。",
+	"error.create_config": "エラー: 設定ファイルの作成に失敗しました: %v",
+	"init.created":        "✅ 設定ファイル %s を作成しました。",
+	"init.next_steps": `
+次のステップ:
+1. 設定ファイルを編集してプロジェクトに合わせてください
+2. ANTHROPIC_API_KEY 環境変数を設定してください
+3. specs/ ディレクトリにSPECファイルを配置してください
+4. spec-verify check を実行してください`,
+
+	// check
+	"error.fail_under_mode":      "エラー: fail_under_mode は %s または %s を指定してください: %s",
+	"error.undefined_group":      "エラー: グループ '%s' は定義されていません。",
+	"error.group_no_valid_types": "エラー: グループ '%s' に有効なタイプが1つもありません",
+	"error.no_valid_types":       "エラー: 有効なタイプが1つもありません",
+	"error.estimate":             "エラー: 見積もりに失敗しました: %v",
+	"hint.set_api_key":           "ANTHROPIC_API_KEY 環境変数を設定するか、設定ファイルに api_key を追加してください。",
+	"hint.heuristic_check":       "AIを使わずに検証するには --provider heuristic を指定してください。",
+	"error.create_verifier":      "エラー: Verifierの作成に失敗しました: %v",
+	"error.verify":               "エラー: 検証に失敗しました: %v",
+	"check.start":                "🔍 SPEC検証を開始します...",
+	"check.group":                "   グループ: %s (タイプ: %s)",
+	"check.types":                "   タイプ: %s",
+
+	// check --dry-run
+	"estimate.header":         "💰 コスト見積もり（ドライラン: APIは呼び出しません）",
+	"estimate.provider":       "   プロバイダー: %s / モデル: %s",
+	"estimate.calls_per_spec": "   1SPECあたりのAPI呼び出し: %d回",
+	"estimate.price":          "   料金: 入力 $%.2f / 出力 $%.2f（100万トークンあたり）",
+	"estimate.price_unknown":  "   ⚠️  料金表にモデルがありません（ai.pricing で設定するとコストを表示します）",
+	"estimate.no_code":        "   関連コード: 0ファイル（APIは呼び出されません）",
+	"estimate.code":           "   関連コード: %dファイル (%s)",
+	"estimate.tokens":         "   推定トークン: 入力 %d / 出力 %d",
+	"estimate.cached":         "   💾 キャッシュ済み（APIは呼び出されません）",
+	"estimate.cost":           "   推定コスト: $%.4f",
+	"estimate.total":          "📊 見積もり合計",
+	"estimate.requests":       "   APIリクエスト数: %d",
+	"estimate.files":          "   コードファイル: %d",
+	"estimate.bytes":          "   送信サイズ: %s",
+
+	// 検証結果
+	"result.title":              "   タイトル: %s",
+	"result.path":               "   パス: %s",
+	"result.code":               "   関連コード: %dファイル",
+	"result.redacted":           "   🔒 機密情報を%d件マスクして送信しました（%s）",
+	"result.dropped":            "   🚫 送信が禁止されているため除外: %s",
+	"result.cached":             "   💾 キャッシュ済みの結果を使用",
+	"result.error":              "   ❌ エラー: %v",
+	"result.skipped":            "   ⏭️  スキップ: %s",
+	"result.no_verification":    "   ⚠️  検証結果がありません",
+	"result.match":              "   %s 一致度: %d%%%s",
+	"result.below_threshold":    " ← Below threshold (%d%%)",
+	"result.retries":            "   🔁 再試行: %d回",
+	"result.member_error":       "%s/%s エラー",
+	"result.ensemble":           "   🗳️  アンサンブル: %s（差 %d）",
+	"result.needs_review":       "   👀 評価が割れています。レビューしてください",
+	"result.samples":            "   🎲 サンプル: %d回 (最小 %d%% / 最大 %d%%, 標準偏差 %.1f)",
+	"result.samples_failed":     "   ⚠️  %d回のサンプルが失敗しました",
+	"result.unstable":           "   🌀 サンプル間で結果がばらついています（不安定）",
+	"result.unstable_matched":   "      ~ %s（一致: %d/%d回）",
+	"result.unstable_unmatched": "      ~ %s（不一致: %d/%d回）",
+	"result.provider":           "   🤖 プロバイダー: %s/%s",
+	"result.usage":              "   📈 %s/%s: 入力 %d / 出力 %d トークン, %s, API呼び出し %d回",
+	"result.fallback":           "   🧮 AIの呼び出しに失敗したため、%s の結果を使用しました",
+	"result.sections":           "   📑 セクション別:",
+	"result.section":            "      %s %3d%% %s (重み %g)",
+	"result.section_error":      "      ❌ %s (重み %g): %s",
+	"result.section_skipped":    "      ⏭️  %s (重み %g): %s",
+	"result.chunks":             "   🧩 コンテキストウィンドウに収まらないため、コードを%d分割して検証しました",
+	"result.repaired":           "   🩹 応答のJSONを修復して解析しました",
+	"result.reasked":            "   🩹 応答を解析できなかったため再質問しました",
+	"result.matched":            "   ✓ 一致:",
+	"result.unmatched":          "   ✗ 不一致:",
+	"result.more_items":         "     ... 他%d件",
+
+	// サマリー
+	"summary.header":              "📊 サマリー",
+	"summary.total_specs":         "   総SPEC数: %d",
+	"summary.average":             "   平均一致度: %.1f%%",
+	"summary.high":                "   高一致(≥80%%): %d件",
+	"summary.low":                 "   低一致(<50%%): %d件",
+	"summary.cached":              "   キャッシュ使用: %d件",
+	"summary.redacted":            "   🔒 マスクした機密情報: %d件",
+	"summary.dropped":             "   🚫 送信が禁止されているため除外したファイル: %d件",
+	"summary.skipped":             "   ⏭️  予算超過でスキップ: %d件（終了コード %d）",
+	"summary.details":             "   詳細:",
+	"summary.section_averages":    "   セクション別平均:",
+	"summary.section_average":     "   %s %5.1f%% %s (%d件)",
+	"summary.usage_by_model":      "📈 API使用量（プロバイダー/モデル別）:",
+	"summary.usage_by_spec_type":  "📈 API使用量（SPECタイプ別）:",
+	"summary.usage_total":         "   %s: %d件, 入力 %d / 出力 %d トークン, %s, API呼び出し %d回",
+	"summary.review":              "👀 要レビュー（プロバイダー間の評価が割れたSPEC）: %d件",
+	"summary.review_spec":         "   - %s (%d%%, 差 %d) : %s",
+	"summary.unstable":            "🌀 不安定（サンプル間で結果がばらついたSPEC）: %d件",
+	"summary.unstable_spec":       "   - %s (%d%%, %d〜%d%%, 標準偏差 %.1f) : %s",
+	"summary.failing":             "❌ 個別閾値未達 (%d%% 未満%s): %d件",
+	"summary.failing_lower_bound": "、一致度の下限で判定",
+
+	// endpoints
+	"error.no_api_sources":    "エラー: api_sources が設定されていません。",
+	"hint.heuristic_extract":  "AIを使わずに抽出するには --provider heuristic を指定してください。",
+	"hint.add_api_sources":    "設定ファイルに以下のように api_sources を追加してください:",
+	"endpoints.extracting":    "📡 APIエンドポイントを抽出中...",
+	"error.extract_endpoints": "エラー: エンドポイントの抽出に失敗しました: %v",
+	"endpoints.none":          "エンドポイントが見つかりませんでした。",
+	"endpoints.header":        "📡 検出されたエンドポイント (%d件)",
+	"endpoints.source":        "📁 %s (%d件)",
+	"endpoints.dropped":       "🚫 送信が禁止されているため除外: %s",
+	"endpoints.redacted":      "🔒 ルートの抽出で機密情報を%d件マスクして送信しました（%s）",
+	"endpoints.repaired":      "🩹 応答のJSONを修復して抽出したエンドポイント: %d件",
+	"endpoints.reasked":       "🩹 応答を解析できなかったため再質問して抽出したエンドポイント: %d件",
+
+	// coverage
+	"hint.coverage_api_sources": "カバレッジレポートにはAPIエンドポイントの抽出設定が必要です。",
+	"coverage.generating":       "📊 APIカバレッジレポートを生成中...",
+	"error.coverage":            "エラー: カバレッジレポートの生成に失敗しました: %v",
+	"coverage.below_threshold":  "❌ カバレッジが閾値未満です: %.1f%% < %d%%",
+	"coverage.header":           "📊 ルートカバレッジレポート",
+	"coverage.threshold":        "   (閾値: %d%%)",
+	"coverage.overall":          "%s 全体カバレッジ: %.1f%%",
+	"coverage.total_routes":     "   ルート総数: %d",
+	"coverage.covered":          "   カバー済み (SPECあり): %d",
+	"coverage.uncovered":        "   未カバー (SPECなし): %d",
+	"coverage.total_specs":      "   SPEC総数: %d",
+	"coverage.orphaned":         "   孤立SPEC (対応なし): %d",
+	"coverage.by_category":      "📂 カテゴリ別カバレッジ",
+	"coverage.category":         "   %s カバレッジ: %.1f%% (%d/%d)",
+	"coverage.category_ui":      "ページ (UI)",
+	"coverage.uncovered_items":  "   ❌ 未カバー: ",
+	"coverage.more_items":       "%s ... 他%d件",
+	"coverage.covered_routes":   "✅ カバー済みルート (%d件)",
+	"coverage.uncovered_routes": "❌ 未カバールート (%d件)",
+	"coverage.orphaned_specs":   "⚠️  孤立SPEC（対応するルートなし） (%d件)",
+
+	// types
+	"types.none":             "定義されているSPECタイプがありません。",
+	"types.hint_add":         "設定ファイルに spec_types または mapping を追加してください。",
+	"types.header":           "📋 定義済みSPECタイプ",
+	"types.code_paths":       "   コードパス: %s",
+	"types.focus":            "   検証観点:",
+	"types.file_patterns":    "   ファイルパターン: %s",
+	"types.exclude_patterns": "   除外パターン: %s",
+
+	// groups
+	"groups.none":     "定義されているグループがありません。",
+	"groups.hint_add": "設定ファイルに groups を追加してください。",
+	"groups.example": `
+例:
+groups:
+  frontend:
+    types: [ui]
+    description: "フロントエンド関連"
+  backend:
+    types: [api, service]
+    description: "バックエンド関連"`,
+	"groups.header":      "📦 定義済みグループ",
+	"groups.types":       "   タイプ: %s",
+	"groups.description": "   説明: %s",
+	"groups.usage": `
+使用方法:
+  spec-verify check --group <グループ名>`,
+
+	// cache
+	"cache.usage":      "使い方: spec-verify cache prune [--older-than 720h] [--all] [--config FILE]",
+	"error.older_than": "エラー: --older-than の値が不正です: %s",
+	"error.prune":      "エラー: キャッシュの削除に失敗しました: %v",
+	"cache.pruned":     "🧹 キャッシュを削除しました: %d件 (%.1f KB)、残り: %d件 [%s]",
+
+	// prompt
+	"prompt.usage":         "使い方: spec-verify prompt show <SPECファイル> [--sections] [--config FILE]",
+	"error.render_prompt":  "エラー: プロンプトの作成に失敗しました: %v",
+	"prompt.template":      "📝 テンプレート: %s",
+	"prompt.no_code":       "関連コードが見つからないため、このSPECはAIに送信されません。",
+	"prompt.section":       "===== セクション: %s =====",
+	"prompt.chunked":       "✂️  コンテキストウィンドウに収まらないため、コードを分割して断片ごとに根拠を抽出します（テンプレート: %s）。最終的な検証のプロンプトは抽出した根拠から作成します（テンプレート: %s）",
+	"prompt.chunk":         "===== 断片 %d/%d =====",
+	"prompt.section_chunk": "===== セクション: %s / 断片 %d/%d =====",
+
+	// 検証（verifier）
+	"verify.no_code_item":    "対応するコードが見つかりません",
+	"verify.no_code_notes":   "未実装の可能性があります",
+	"budget.max_input_bytes": "送信サイズ %d バイトが上限 max_input_bytes_per_spec (%d) を超えています",
+	"budget.max_tokens":      "トークン数の上限 max_tokens_per_run (%d) に達しました（使用済み: %d）",
+	"budget.max_cost":        "コストの上限 max_cost_per_run ($%.2f) に達しました（使用済み: $%.4f）",
+	"focus.layout":           "画面構成: SPECに記載された要素がコードに存在するか",
+	"focus.state":            "状態管理: SPECに記載された状態やフックが使用されているか",
+	"focus.flow":             "処理フロー: SPECに記載された処理フローがコードで実装されているか",
+	"focus.validation":       "バリデーション: SPECに記載されたバリデーションルールが実装されているか",
+	"focus.error_handling":   "エラーハンドリング: SPECに記載されたエラーケースが処理されているか",
+
+	// ヒューリスティック検証
+	"heuristic.route":      "ルート %s",
+	"heuristic.identifier": "識別子 %s",
+	"heuristic.label":      "UIラベル「%s」",
+	"heuristic.message":    "エラーメッセージ「%s」",
+	"heuristic.constraint": "バリデーション %s: %s",
+	"heuristic.notes":      "ヒューリスティック検証（AI未使用）: %d項目中%d項目がコードに見つかりました。文字列の一致のみで判定しているため、処理の正しさは確認していません",
+	"heuristic.fallback":   "%s の呼び出しに失敗したため、ヒューリスティック検証の結果です（%v）\n%s",
+}
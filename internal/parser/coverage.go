@@ -7,6 +7,8 @@ import (
 
 	"github.com/k-totani/spec-verify/internal/ai"
 	"github.com/k-totani/spec-verify/internal/config"
+	"github.com/k-totani/spec-verify/internal/egress"
+	"github.com/k-totani/spec-verify/internal/redact"
 )
 
 // CoverageReport はルート（API/ページ）のカバレッジレポート
@@ -40,6 +42,15 @@ type CoverageReport struct {
 
 	// カテゴリ別サマリー
 	ByCategory map[string]*CategoryCoverage `json:"byCategory,omitempty"`
+
+	// egress の設定で送信を禁止されたため、ルートの抽出から除外したファイル
+	DroppedFiles []string `json:"droppedFiles,omitempty"`
+
+	// AIの応答の修復（repaired）や再質問（reasked）を経て抽出したルート数（復旧方法ごと）
+	Repairs map[string]int `json:"repairs,omitempty"`
+
+	// ルートの抽出でAIに送信する前に置き換えた機密情報の件数（キーは検出ルール名）
+	Redactions map[string]int `json:"redactions,omitempty"`
 }
 
 // CategoryCoverage はカテゴリ別のカバレッジ情報
@@ -115,12 +126,25 @@ func CalculateCoverage(ctx context.Context, cfg *config.Config, provider ai.Prov
 		sources = cfg.APISources
 	}
 
-	// ルートを抽出
-	endpoints, err := ExtractEndpoints(ctx, sources, provider)
+	// ルートを抽出（送信を禁止されたファイルと機密情報の件数はレポートに記録する）
+	policy, err := egress.NewPolicy(cfg.Egress.Allow, cfg.Egress.Deny)
+	if err != nil {
+		return nil, err
+	}
+	redactor, err := redact.FromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	endpoints, err := ExtractEndpoints(ctx, sources, provider, WithEgressPolicy(policy, func(path string) {
+		report.DroppedFiles = append(report.DroppedFiles, path)
+	}), WithRedactor(redactor, func(counts map[string]int) {
+		report.Redactions = counts
+	}))
 	if err != nil {
 		return nil, err
 	}
 	report.TotalEndpoints = len(endpoints)
+	report.Repairs = CountRepairs(endpoints)
 
 	// SPECファイルを検索（全タイプ）
 	specFiles, err := FindSpecFiles(cfg.SpecsDir, "")
@@ -10,6 +10,8 @@ import (
 
 	"github.com/k-totani/spec-verify/internal/ai"
 	"github.com/k-totani/spec-verify/internal/config"
+	"github.com/k-totani/spec-verify/internal/egress"
+	"github.com/k-totani/spec-verify/internal/redact"
 )
 
 // Endpoint はルート（API/ページ）を表す
@@ -32,10 +34,70 @@ type Endpoint struct {
 
 	// 説明（あれば）
 	Description string `json:"description,omitempty"`
+
+	// AIの応答の解析に修復（repaired）または再質問（reasked）が必要だった場合にその方法
+	Repair string `json:"repair,omitempty"`
+}
+
+// CountRepairs は応答の復旧方法ごとにエンドポイント数を数える（復旧が不要だった場合は nil）
+func CountRepairs(endpoints []Endpoint) map[string]int {
+	var counts map[string]int
+	for _, ep := range endpoints {
+		if ep.Repair == "" {
+			continue
+		}
+		if counts == nil {
+			counts = make(map[string]int)
+		}
+		counts[ep.Repair]++
+	}
+	return counts
+}
+
+// ExtractOption はルート抽出時のオプション
+type ExtractOption func(*extractConfig)
+
+type extractConfig struct {
+	// AIに送信してよいファイルの制限（nilの場合は制限しない）
+	egress *egress.Policy
+
+	// 送信を禁止されたため除外したファイルを受け取る（nilの場合は通知しない）
+	onDropped func(path string)
+
+	// AIに送信する前にコードから機密情報を取り除く（nilの場合は取り除かない）
+	redactor *redact.Redactor
+
+	// 置き換えた機密情報のルールごとの件数を受け取る（nilの場合は通知しない）
+	onRedacted func(counts map[string]int)
+
+	// 置き換えた機密情報のルールごとの件数（全ソースの合計）
+	redactions map[string]int
+}
+
+// WithEgressPolicy はAIに送信してよいファイルを制限するオプション
+// 禁止されたファイルは送信せず、onDropped に通知する
+func WithEgressPolicy(policy *egress.Policy, onDropped func(path string)) ExtractOption {
+	return func(c *extractConfig) {
+		c.egress = policy
+		c.onDropped = onDropped
+	}
+}
+
+// WithRedactor はAIに送信する前にコードから機密情報を取り除くオプション
+// 抽出が終わった後、置き換えた件数（ルールごと）があれば onRedacted に通知する
+func WithRedactor(redactor *redact.Redactor, onRedacted func(counts map[string]int)) ExtractOption {
+	return func(c *extractConfig) {
+		c.redactor = redactor
+		c.onRedacted = onRedacted
+	}
 }
 
 // ExtractEndpoints は設定に基づいてルートを抽出する
-func ExtractEndpoints(ctx context.Context, sources []config.APISource, provider ai.Provider) ([]Endpoint, error) {
+func ExtractEndpoints(ctx context.Context, sources []config.APISource, provider ai.Provider, opts ...ExtractOption) ([]Endpoint, error) {
+	var cfg extractConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
 	var allEndpoints []Endpoint
 
 	for _, source := range sources {
@@ -46,7 +108,7 @@ func ExtractEndpoints(ctx context.Context, sources []config.APISource, provider
 		case "openapi":
 			endpoints, err = extractFromOpenAPI(source.Patterns)
 		case "express", "fastify", "go-echo", "go-gin", "rails", "django", "graphql", "auto":
-			endpoints, err = extractWithAI(ctx, source, provider)
+			endpoints, err = extractWithAI(ctx, source, provider, &cfg)
 		default:
 			return nil, fmt.Errorf("unknown api source type: %s", source.Type)
 		}
@@ -69,6 +131,10 @@ func ExtractEndpoints(ctx context.Context, sources []config.APISource, provider
 		allEndpoints = append(allEndpoints, endpoints...)
 	}
 
+	if cfg.onRedacted != nil && len(cfg.redactions) > 0 {
+		cfg.onRedacted(cfg.redactions)
+	}
+
 	return allEndpoints, nil
 }
 
@@ -162,7 +228,7 @@ func parseOpenAPIFile(filePath string) ([]Endpoint, error) {
 }
 
 // maxBatchBytes はバッチあたりの最大バイト数（約6000トークン相当）
-// Claude APIの制限（10,000トークン/分）を考慮して余裕を持たせる
+// 1リクエストが入力トークン/分の制限（Claudeの低ティアでは10,000）を超えないよう余裕を持たせる
 const maxBatchBytes = 20000
 
 // fileWithContent はファイルパスと内容を保持する
@@ -170,10 +236,13 @@ type fileWithContent struct {
 	path    string
 	content string
 	size    int
+
+	// 監査ログに記録する送信内容
+	audit egress.File
 }
 
 // extractWithAI はAIを使ってエンドポイントを抽出する
-func extractWithAI(ctx context.Context, source config.APISource, provider ai.Provider) ([]Endpoint, error) {
+func extractWithAI(ctx context.Context, source config.APISource, provider ai.Provider, cfg *extractConfig) ([]Endpoint, error) {
 	var allEndpoints []Endpoint
 
 	// パターンにマッチするファイルを収集
@@ -197,22 +266,39 @@ func extractWithAI(ctx context.Context, source config.APISource, provider ai.Pro
 		files = append(files, matches...)
 	}
 
+	// egress で送信を禁止されたファイルは読み込まずに除外する
+	files, dropped := cfg.egress.Filter(files)
+	if cfg.onDropped != nil {
+		for _, file := range dropped {
+			cfg.onDropped(file)
+		}
+	}
+
 	if len(files) == 0 {
 		return nil, nil
 	}
 
 	// ファイル内容を読み込む
+	contents, err := ReadFiles(files)
+	if err != nil {
+		return nil, err
+	}
+	// シークレットや個人情報を外部のAPIに送信しないよう、プレースホルダーに置き換える
+	contents, counts := cfg.redactor.RedactFiles(contents)
+	cfg.redactions = redact.Merge(cfg.redactions, counts)
+
 	var fileContents []fileWithContent
 	for _, file := range files {
-		content, err := os.ReadFile(file)
-		if err != nil {
+		content, ok := contents[file]
+		if !ok {
 			continue
 		}
-		formatted := fmt.Sprintf("=== File: %s ===\n%s", file, string(content))
+		formatted := fmt.Sprintf("=== File: %s ===\n%s", file, content)
 		fileContents = append(fileContents, fileWithContent{
 			path:    file,
 			content: formatted,
 			size:    len(formatted),
+			audit:   egress.NewFile(file, formatted),
 		})
 	}
 
@@ -226,24 +312,20 @@ func extractWithAI(ctx context.Context, source config.APISource, provider ai.Pro
 		Category:   source.Category,
 	}
 
-	// Claudeの場合のみバッチ処理（レート制限が厳しいため）
-	// OpenAI/Geminiは一括処理
-	var batches [][]fileWithContent
-	if provider.Name() == "claude" {
-		batches = splitIntoBatches(fileContents, maxBatchBytes)
-	} else {
-		// 全ファイルを1バッチとして処理
-		batches = [][]fileWithContent{fileContents}
-	}
+	// 全プロバイダーでバッチ処理する
+	// バッチ間の送信ペースはプロバイダーのレートリミッターが制御する
+	batches := splitIntoBatches(fileContents, maxBatchBytes)
 
 	for _, batch := range batches {
 		// バッチ内のファイル内容を結合
 		var contents []string
+		var sent []egress.File
 		for _, fc := range batch {
 			contents = append(contents, fc.content)
+			sent = append(sent, fc.audit)
 		}
 
-		aiResults, err := provider.ExtractEndpoints(ctx, opts, strings.Join(contents, "\n\n"))
+		aiResults, err := provider.ExtractEndpoints(egress.WithFiles(ctx, sent), opts, strings.Join(contents, "\n\n"))
 		if err != nil {
 			return nil, err
 		}
@@ -256,6 +338,7 @@ func extractWithAI(ctx context.Context, source config.APISource, provider ai.Pro
 				Source:      source.Type,
 				File:        result.File,
 				Description: result.Description,
+				Repair:      result.Repair,
 			}
 			if ep.Source == "" {
 				ep.Source = source.Type
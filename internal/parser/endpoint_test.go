@@ -1,9 +1,28 @@
 package parser
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/k-totani/spec-verify/internal/ai"
+	"github.com/k-totani/spec-verify/internal/config"
+	"github.com/k-totani/spec-verify/internal/redact"
 )
 
+// extractCapturingProvider はエンドポイント抽出で受け取ったコードを記録するテスト用プロバイダー
+type extractCapturingProvider struct {
+	ai.Provider
+	code []string
+}
+
+func (p *extractCapturingProvider) ExtractEndpoints(ctx context.Context, opts *ai.ExtractOptions, codeContent string) ([]ai.EndpointResult, error) {
+	p.code = append(p.code, codeContent)
+	return []ai.EndpointResult{{Method: "GET", Path: "/users"}}, nil
+}
+
 func TestSplitIntoBatches(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -109,3 +128,58 @@ func TestSplitIntoBatches_BatchSizeLimit(t *testing.T) {
 		}
 	}
 }
+
+func TestCountRepairs(t *testing.T) {
+	endpoints := []Endpoint{
+		{Path: "/users", Repair: "repaired"},
+		{Path: "/users/:id", Repair: "repaired"},
+		{Path: "/orders", Repair: "reasked"},
+		{Path: "/health"},
+	}
+	counts := CountRepairs(endpoints)
+	if len(counts) != 2 || counts["repaired"] != 2 || counts["reasked"] != 1 {
+		t.Errorf("CountRepairs = %v", counts)
+	}
+	if CountRepairs(endpoints[3:]) != nil {
+		t.Error("CountRepairs should be nil without repairs")
+	}
+}
+
+func TestExtractEndpoints_Redaction(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"routes.ts":    "const token = \"sk-ant-REDACTED\"\nrouter.get('/users', list) // owner@example.com\n",
+		".env.example": "DB_PASSWORD=hunter2hunter2\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	redactor, err := redact.FromConfig(config.DefaultConfig())
+	if err != nil {
+		t.Fatalf("FromConfig failed: %v", err)
+	}
+	var reported map[string]int
+	provider := &extractCapturingProvider{}
+	sources := []config.APISource{{Type: "express", Patterns: []string{filepath.Join(dir, "*.ts"), filepath.Join(dir, ".env.example")}}}
+	endpoints, err := ExtractEndpoints(context.Background(), sources, provider, WithRedactor(redactor, func(counts map[string]int) {
+		reported = counts
+	}))
+	if err != nil {
+		t.Fatalf("ExtractEndpoints failed: %v", err)
+	}
+
+	if len(endpoints) != 1 || len(provider.code) != 1 {
+		t.Fatalf("endpoints = %v, provider received %d requests", endpoints, len(provider.code))
+	}
+	for _, secret := range []string{"sk-ant-api03", "owner@example.com", "hunter2hunter2"} {
+		if strings.Contains(provider.code[0], secret) {
+			t.Errorf("%q was sent to the provider:\n%s", secret, provider.code[0])
+		}
+	}
+	if reported[redact.RuleAPIKey] != 1 || reported[redact.RuleEmail] != 1 || reported[redact.RuleSecretAssignment] != 1 {
+		t.Errorf("reported redactions = %v", reported)
+	}
+}
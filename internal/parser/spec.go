@@ -34,6 +34,9 @@ type Spec struct {
 
 	// セクション
 	Sections map[string]string
+
+	// セクション名（SPECに記載された順）
+	SectionNames []string
 }
 
 // ParseSpec はSPECファイルを解析する
@@ -166,9 +169,9 @@ func (s *Spec) parseSections(lines []string) {
 		if strings.HasPrefix(line, "## ") {
 			// 前のセクションを保存
 			if currentSection != "" {
-				s.Sections[currentSection] = strings.TrimSpace(sectionContent.String())
+				s.addSection(currentSection, sectionContent.String())
 			}
-			currentSection = strings.TrimPrefix(line, "## ")
+			currentSection = strings.TrimSpace(strings.TrimPrefix(line, "## "))
 			sectionContent.Reset()
 		} else if currentSection != "" {
 			sectionContent.WriteString(line)
@@ -178,8 +181,20 @@ func (s *Spec) parseSections(lines []string) {
 
 	// 最後のセクションを保存
 	if currentSection != "" {
-		s.Sections[currentSection] = strings.TrimSpace(sectionContent.String())
+		s.addSection(currentSection, sectionContent.String())
+	}
+}
+
+// addSection はセクションを追加する
+// 同じ名前のセクションが複数ある場合は内容を連結する
+func (s *Spec) addSection(name, content string) {
+	content = strings.TrimSpace(content)
+	if existing, ok := s.Sections[name]; ok {
+		s.Sections[name] = strings.TrimSpace(existing + "\n\n" + content)
+		return
 	}
+	s.Sections[name] = content
+	s.SectionNames = append(s.SectionNames, name)
 }
 
 // FindSpecFiles は指定ディレクトリ内のSPECファイルを検索する
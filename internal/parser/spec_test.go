@@ -212,3 +212,25 @@ func TestFindCodeFiles_BackwardCompatibility(t *testing.T) {
 		t.Errorf("Expected TestPage.tsx not found in results: %v", files)
 	}
 }
+
+func TestParseSpec_Sections(t *testing.T) {
+	content := "# ログイン画面\n\n" +
+		"## 画面構成\n\n- ログインボタン\n\n" +
+		"## バリデーション\n\n- メールアドレスは必須\n\n" +
+		"## 画面構成\n\n- パスワード入力欄\n"
+	path := filepath.Join(t.TempDir(), "login.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := ParseSpec(path)
+	if err != nil {
+		t.Fatalf("ParseSpec failed: %v", err)
+	}
+	if len(spec.SectionNames) != 2 || spec.SectionNames[0] != "画面構成" || spec.SectionNames[1] != "バリデーション" {
+		t.Errorf("SectionNames = %v", spec.SectionNames)
+	}
+	if want := "- ログインボタン\n\n- パスワード入力欄"; spec.Sections["画面構成"] != want {
+		t.Errorf("Sections[画面構成] = %q, want %q", spec.Sections["画面構成"], want)
+	}
+}
@@ -0,0 +1,38 @@
+package redact
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/k-totani/spec-verify/internal/config"
+)
+
+// FromConfig は redaction の設定に基づいて、AIに送信するコードから機密情報を取り除く Redactor を作成する
+// 無効な場合は nil を返す（nil の Redactor は何も置き換えない）
+func FromConfig(cfg *config.Config) (*Redactor, error) {
+	if !cfg.IsRedactionEnabled() {
+		return nil, nil
+	}
+
+	defaults := DefaultRules()
+	for _, name := range cfg.Redaction.Disable {
+		if !slices.ContainsFunc(defaults, func(r Rule) bool { return r.Name == name }) {
+			return nil, fmt.Errorf("unknown redaction rule: %s", name)
+		}
+	}
+
+	var rules []Rule
+	for _, rule := range defaults {
+		if !slices.Contains(cfg.Redaction.Disable, rule.Name) {
+			rules = append(rules, rule)
+		}
+	}
+	for _, pattern := range cfg.Redaction.Patterns {
+		rule, err := CompileRule(pattern.Name, pattern.Regex)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return New(rules), nil
+}
@@ -0,0 +1,36 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/k-totani/spec-verify/internal/config"
+)
+
+func TestFromConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	disabled := false
+	cfg.Redaction.Enabled = &disabled
+	if r, err := FromConfig(cfg); r != nil || err != nil {
+		t.Errorf("FromConfig = %v, %v, want nil when disabled", r, err)
+	}
+
+	cfg = config.DefaultConfig()
+	cfg.Redaction.Disable = []string{RuleEmail}
+	r, err := FromConfig(cfg)
+	if err != nil {
+		t.Fatalf("FromConfig failed: %v", err)
+	}
+	if got, _ := r.Redact("a@example.com"); got != "a@example.com" {
+		t.Errorf("Redact = %q, the disabled rule should not apply", got)
+	}
+
+	cfg.Redaction.Disable = []string{"unknown"}
+	if _, err := FromConfig(cfg); err == nil {
+		t.Error("expected error for an unknown rule")
+	}
+	cfg.Redaction.Disable = nil
+	cfg.Redaction.Patterns = []config.RedactionPattern{{Name: "broken", Regex: "("}}
+	if _, err := FromConfig(cfg); err == nil {
+		t.Error("expected error for an invalid pattern")
+	}
+}
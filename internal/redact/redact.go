@@ -0,0 +1,342 @@
+// Package redact はAIプロバイダーに送信する前にコードから機密情報（シークレット、個人情報）を取り除く
+package redact
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// 組み込みの検出ルール名
+const (
+	RulePrivateKey       = "private_key"
+	RuleAWSAccessKey     = "aws_access_key"
+	RuleAWSSecretKey     = "aws_secret_key"
+	RuleJWT              = "jwt"
+	RuleAPIKey           = "api_key"
+	RuleSecretAssignment = "secret_assignment"
+	RuleEmail            = "email"
+	RuleHighEntropy      = "high_entropy"
+)
+
+// highEntropyThreshold は高エントロピー文字列とみなす1文字あたりのシャノンエントロピー（ビット）
+// ランダムなBase64文字列は5前後、英単語をつなげた識別子は3〜4程度になる
+const highEntropyThreshold = 4.2
+
+// Rule は機密情報の検出ルール
+type Rule struct {
+	// ルール名（プレースホルダーと件数の集計に使用する）
+	Name string
+
+	// 検出する正規表現
+	Pattern *regexp.Regexp
+
+	// 置き換えるサブマッチの番号（0の場合はマッチ全体、負の場合は最初に一致したサブマッチ）
+	// 変数名などの前後の文脈はコードの理解に必要なため、値の部分だけを置き換える場合に指定する
+	Group int
+
+	// 置き換える値かを判定する（nilの場合は全て置き換える）
+	Match func(value string) bool
+}
+
+// DefaultRules は組み込みの検出ルールを返す
+// 先に適用したルールで置き換えた部分は後のルールでは検出しないため、具体的なルールから順に並べている
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			Name:    RulePrivateKey,
+			Pattern: regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z0-9 ]*PRIVATE KEY-----`),
+		},
+		{
+			Name:    RuleAWSAccessKey,
+			Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+		},
+		{
+			Name:    RuleAWSSecretKey,
+			Pattern: regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})`),
+			Group:   1,
+		},
+		{
+			Name:    RuleJWT,
+			Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`),
+		},
+		{
+			// Anthropic, OpenAI, GitHub, Slack, Google, Stripe のAPIキー
+			Name: RuleAPIKey,
+			Pattern: regexp.MustCompile(`\b(?:sk-ant-[A-Za-z0-9_-]{20,}|sk-(?:proj-)?[A-Za-z0-9_-]{20,}|` +
+				`gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,}|xox[abprs]-[A-Za-z0-9-]{10,}|` +
+				`AIza[0-9A-Za-z_-]{35}|[rs]k_(?:live|test)_[0-9A-Za-z]{16,})`),
+		},
+		{
+			// apiKey: "...", DB_PASSWORD=... のように、名前からシークレットと分かる変数に代入された値
+			Name: RuleSecretAssignment,
+			Pattern: regexp.MustCompile(`(?im)(?:api_?key|secret|token|passw(?:or)?d)["']?\s*[:=]\s*` +
+				`(?:"([^"\s]{8,})"|'([^'\s]{8,})'|([^"'\s,;#()]{8,})\s*$)`),
+			Group: -1,
+			Match: isLiteralValue,
+		},
+		{
+			Name:    RuleEmail,
+			Pattern: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}\b`),
+		},
+		{
+			Name:    RuleHighEntropy,
+			Pattern: regexp.MustCompile(`[A-Za-z0-9+/_=-]{24,}`),
+			Match:   isHighEntropy,
+		},
+	}
+}
+
+// CompileRule は設定で指定された正規表現から検出ルールを作成する
+// 正規表現にサブマッチがある場合は最初のサブマッチだけを置き換える
+func CompileRule(name, pattern string) (Rule, error) {
+	if name == "" {
+		return Rule{}, fmt.Errorf("redaction pattern %q requires a name", pattern)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid redaction pattern %s: %w", name, err)
+	}
+	rule := Rule{Name: name, Pattern: re}
+	if re.NumSubexp() > 0 {
+		rule.Group = 1
+	}
+	return rule, nil
+}
+
+// Redactor は検出ルールに一致した部分をプレースホルダーに置き換える
+type Redactor struct {
+	rules []Rule
+}
+
+// New は rules を順に適用する Redactor を作成する
+func New(rules []Rule) *Redactor {
+	return &Redactor{rules: rules}
+}
+
+// placeholders は値ごとのプレースホルダーの番号を管理する
+// 番号はルールごとに値が最初に現れた順に振るため、プレースホルダーから値を推測できない
+// 同じ内容は常に同じプレースホルダーになるため、置き換え後もキャッシュのキーが変わらず、
+// AIは同じ値が複数の場所で使われていることを読み取れる
+type placeholders map[string]map[string]int
+
+// get は rule に一致した value を置き換えるプレースホルダー（[REDACTED:rule:番号]）を返す
+func (p placeholders) get(rule, value string) string {
+	indexes := p[rule]
+	if indexes == nil {
+		indexes = make(map[string]int)
+		p[rule] = indexes
+	}
+	index, ok := indexes[value]
+	if !ok {
+		index = len(indexes) + 1
+		indexes[value] = index
+	}
+	return fmt.Sprintf("[REDACTED:%s:%d]", rule, index)
+}
+
+// Redact は content の機密情報を置き換え、ルールごとの置き換え件数とともに返す
+// Redactor が nil の場合は何もしない
+func (r *Redactor) Redact(content string) (string, map[string]int) {
+	return r.redact(content, make(placeholders))
+}
+
+// redact は p の番号でプレースホルダーに置き換える
+func (r *Redactor) redact(content string, p placeholders) (string, map[string]int) {
+	if r == nil {
+		return content, nil
+	}
+	var counts map[string]int
+	for _, rule := range r.rules {
+		var n int
+		content, n = rule.apply(content, p)
+		if n > 0 {
+			if counts == nil {
+				counts = make(map[string]int)
+			}
+			counts[rule.Name] += n
+		}
+	}
+	return content, counts
+}
+
+// RedactFiles はファイルごとの内容の機密情報を置き換え、全ファイルのルールごとの置き換え件数とともに返す
+// プレースホルダーの番号は全ファイルで共通のため、同じ値は別のファイルでも同じプレースホルダーになる
+func (r *Redactor) RedactFiles(files map[string]string) (map[string]string, map[string]int) {
+	if r == nil {
+		return files, nil
+	}
+	// 番号が map の順序に左右されないよう、パスの順に置き換える
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	p := make(placeholders)
+	redacted := make(map[string]string, len(files))
+	var counts map[string]int
+	for _, path := range paths {
+		var fileCounts map[string]int
+		redacted[path], fileCounts = r.redact(files[path], p)
+		counts = Merge(counts, fileCounts)
+	}
+	return redacted, counts
+}
+
+// Merge は counts にルールごとの件数 more を加えて返す（counts が nil の場合は必要に応じて作成する）
+func Merge(counts, more map[string]int) map[string]int {
+	for name, n := range more {
+		if counts == nil {
+			counts = make(map[string]int)
+		}
+		counts[name] += n
+	}
+	return counts
+}
+
+// placeholderPattern は置き換えたプレースホルダー
+var placeholderPattern = regexp.MustCompile(`\[REDACTED:[^\]\s]*\]`)
+
+// apply はルールに一致した部分を置き換え、置き換えた件数を返す
+// 先に適用したルールのプレースホルダーと重なる部分は置き換えない
+func (rule Rule) apply(content string, p placeholders) (string, int) {
+	matches := rule.Pattern.FindAllStringSubmatchIndex(content, -1)
+	if len(matches) == 0 {
+		return content, 0
+	}
+	placeholders := placeholderPattern.FindAllStringIndex(content, -1)
+
+	var b strings.Builder
+	last, n := 0, 0
+	for _, m := range matches {
+		start, end := rule.span(m)
+		if start < 0 || overlaps(placeholders, start, end) {
+			continue
+		}
+		value := content[start:end]
+		if rule.Match != nil && !rule.Match(value) {
+			continue
+		}
+		b.WriteString(content[last:start])
+		b.WriteString(p.get(rule.Name, value))
+		last = end
+		n++
+	}
+	b.WriteString(content[last:])
+	return b.String(), n
+}
+
+// overlaps は [start, end) が spans のいずれかと重なるかを返す
+func overlaps(spans [][]int, start, end int) bool {
+	for _, span := range spans {
+		if start < span[1] && span[0] < end {
+			return true
+		}
+	}
+	return false
+}
+
+// span は置き換える範囲を返す（対象のサブマッチがない場合は -1）
+// Group が負の場合は最初に一致したサブマッチを対象とする
+func (rule Rule) span(m []int) (int, int) {
+	if rule.Group >= 0 {
+		return m[2*rule.Group], m[2*rule.Group+1]
+	}
+	for i := 2; i < len(m); i += 2 {
+		if m[i] >= 0 {
+			return m[i], m[i+1]
+		}
+	}
+	return -1, -1
+}
+
+// isLiteralValue は変数に代入された値がシークレットの値そのものかを判定する
+// 環境変数や設定の参照（${API_KEY}, process.env.TOKEN）、変数の参照などは対象外とする
+func isLiteralValue(value string) bool {
+	if strings.HasPrefix(value, "$") || strings.HasPrefix(value, "<") {
+		return false
+	}
+	if strings.Contains(value, "process.env") || strings.Contains(value, "os.Getenv") || strings.Contains(value, "ENV[") {
+		return false
+	}
+	return !reference.MatchString(value)
+}
+
+// reference は config.apiToken のような変数やフィールドの参照
+var reference = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)+$`)
+
+// isHighEntropy はランダムに生成された値（トークンやキー）らしい文字列かを判定する
+// 識別子やパスの誤検出を避けるため、大文字・小文字・数字を全て含み、エントロピーが閾値以上のものに限る
+func isHighEntropy(value string) bool {
+	if isPath(value) {
+		return false
+	}
+	var upper, lower, digit bool
+	for _, c := range value {
+		switch {
+		case unicode.IsUpper(c):
+			upper = true
+		case unicode.IsLower(c):
+			lower = true
+		case unicode.IsDigit(c):
+			digit = true
+		}
+	}
+	return upper && lower && digit && entropy(value) >= highEntropyThreshold
+}
+
+// isPath は src/components/Button のようなパスかを判定する（小文字だけの3文字以上の部分を含む）
+func isPath(value string) bool {
+	segments := strings.Split(value, "/")
+	if len(segments) < 2 {
+		return false
+	}
+	for _, segment := range segments {
+		if len(segment) >= 3 && strings.ToLower(segment) == segment && strings.IndexFunc(segment, unicode.IsDigit) < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// entropy は1文字あたりのシャノンエントロピー（ビット）を返す
+func entropy(s string) float64 {
+	freq := make(map[rune]int)
+	for _, c := range s {
+		freq[c]++
+	}
+	n := float64(len(s))
+	var h float64
+	for _, count := range freq {
+		p := float64(count) / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+// Summary はルールごとの件数を「ルール名 件数」の形式で名前順に連結する（表示用）
+func Summary(counts map[string]int) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %d", name, counts[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Total はルールごとの件数の合計を返す
+func Total(counts map[string]int) int {
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	return total
+}
@@ -0,0 +1,180 @@
+package verifier
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/k-totani/spec-verify/internal/ai"
+	"github.com/k-totani/spec-verify/internal/config"
+	"github.com/k-totani/spec-verify/internal/i18n"
+)
+
+// budget は1回の実行で使用するトークン数・コストの上限を管理する
+// 複数のgoroutineで共有する。nilの場合は制限しない
+type budget struct {
+	mu sync.Mutex
+
+	// 上限（0の場合は無制限）
+	maxTokens    int
+	maxCost      float64
+	maxSpecBytes int
+
+	// 予約のコスト計算に使用する料金（アンサンブルの場合はメンバーの平均）
+	price ai.ModelPrice
+
+	// 設定ファイルの料金表（実際に使用したモデルの料金を調べるのに使用する）
+	pricing map[string]ai.ModelPrice
+
+	// 使用済みの量（送信中のリクエストの予約分を含む）
+	usedTokens int
+	usedCost   float64
+
+	// 上限に達した理由（空の場合は未到達）
+	exceeded string
+
+	// スキップ理由の言語
+	language string
+}
+
+// pricedMember はコスト計算の対象となるプロバイダーとモデル
+type pricedMember struct {
+	provider string
+	model    string
+}
+
+// newBudget は設定から budget を作成する
+// 上限が1つも設定されていない場合は nil を返す
+func newBudget(cfg *config.Config, members []pricedMember) (*budget, error) {
+	opts := cfg.Options
+	if opts.MaxTokensPerRun <= 0 && opts.MaxCostPerRun <= 0 && opts.MaxInputBytesPerSpec <= 0 {
+		return nil, nil
+	}
+
+	b := &budget{
+		maxTokens:    opts.MaxTokensPerRun,
+		maxCost:      opts.MaxCostPerRun,
+		maxSpecBytes: opts.MaxInputBytesPerSpec,
+		language:     cfg.Language,
+	}
+	if b.maxCost > 0 {
+		b.pricing = pricingFromConfig(cfg)
+		for _, m := range members {
+			price, ok := ai.LookupPrice(m.provider, m.model, b.pricing)
+			if !ok {
+				return nil, fmt.Errorf("max_cost_per_run requires a price for model %q (set ai.pricing)", m.model)
+			}
+			b.price.InputPerMTok += price.InputPerMTok / float64(len(members))
+			b.price.OutputPerMTok += price.OutputPerMTok / float64(len(members))
+		}
+	}
+	return b, nil
+}
+
+// reservation は admit で予約した使用量（settle で実際の使用量に置き換える）
+type reservation struct {
+	usage ai.Usage
+}
+
+// admit はSPECのプロンプトを calls 回送信してよいかを判定し、推定量を予約する
+// 送信できない場合はスキップ理由を返す
+// 実行全体の上限は、使用済みの量と送信中の他のリクエストの予約分に今回の推定量を加えて判定する
+// 予約してから判定するため、並列に送信しても上限を超えない
+func (b *budget) admit(p *preparedSpec, calls int) (*reservation, string) {
+	if b == nil {
+		return nil, ""
+	}
+
+	if b.maxSpecBytes > 0 && p.inputBytes > b.maxSpecBytes {
+		return nil, i18n.New(b.language).Sprintf("budget.max_input_bytes", p.inputBytes, b.maxSpecBytes)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.exceeded != "" {
+		return nil, b.exceeded
+	}
+
+	estimate := estimateUsage(p.prompt, calls)
+	if b.maxTokens > 0 && b.usedTokens+estimate.Total() > b.maxTokens {
+		b.exceeded = i18n.New(b.language).Sprintf("budget.max_tokens", b.maxTokens, b.usedTokens)
+		return nil, b.exceeded
+	}
+	if b.maxCost > 0 && b.usedCost+b.cost(estimate) > b.maxCost {
+		b.exceeded = i18n.New(b.language).Sprintf("budget.max_cost", b.maxCost, b.usedCost)
+		return nil, b.exceeded
+	}
+	b.usedTokens += estimate.Total()
+	b.usedCost += b.cost(estimate)
+	return &reservation{usage: estimate}, ""
+}
+
+// settle は予約した推定量をプロバイダー/モデルごとの実際の使用量に置き換える
+// コストは結果を返したモデル（代替プロバイダーを含む）の料金で計算する
+// 使用量が返されなかった場合（返さないプロバイダー、呼び出しの失敗）は推定量のまま使用済みとする
+// 失敗した呼び出しも再試行や分割したチャンクでトークンを消費している可能性があるため、予約は取り消さない
+func (b *budget) settle(r *reservation, records []UsageRecord) {
+	if b == nil || r == nil {
+		return
+	}
+	var total int
+	var cost float64
+	for _, record := range records {
+		total += record.InputTokens + record.OutputTokens
+		cost += b.priceOf(record.Provider, record.Model).Cost(record.InputTokens, record.OutputTokens)
+	}
+	if total == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.usedTokens += total - r.usage.Total()
+	b.usedCost += cost - b.cost(r.usage)
+}
+
+// priceOf は provider/model の料金を返す（料金が分からない場合は予約に使用した料金）
+func (b *budget) priceOf(provider, model string) ai.ModelPrice {
+	if provider == "" {
+		return b.price
+	}
+	if price, ok := ai.LookupPrice(provider, model, b.pricing); ok {
+		return price
+	}
+	return b.price
+}
+
+// release は予約した推定量を取り消す
+// APIを呼び出さずに得た結果（カセットの再生、ヒューリスティック）に使用する
+func (b *budget) release(r *reservation) {
+	if b == nil || r == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.usedTokens -= r.usage.Total()
+	b.usedCost -= b.cost(r.usage)
+}
+
+// cost は予約に使用した料金で使用量のコストを返す
+func (b *budget) cost(usage ai.Usage) float64 {
+	return b.price.Cost(usage.InputTokens, usage.OutputTokens)
+}
+
+// estimateUsage はプロンプトを calls 回送信した場合の使用量を推定する
+func estimateUsage(prompt string, calls int) ai.Usage {
+	return ai.Usage{
+		InputTokens:  ai.EstimateTokens(prompt) * calls,
+		OutputTokens: ai.EstimatedVerificationOutputTokens * calls,
+	}
+}
+
+// pricingFromConfig は設定ファイルの料金表を ai.ModelPrice に変換する
+func pricingFromConfig(cfg *config.Config) map[string]ai.ModelPrice {
+	pricing := make(map[string]ai.ModelPrice, len(cfg.AI.Pricing))
+	for name, price := range cfg.AI.Pricing {
+		pricing[name] = ai.ModelPrice{InputPerMTok: price.InputPerMTok, OutputPerMTok: price.OutputPerMTok}
+	}
+	return pricing
+}
@@ -0,0 +1,219 @@
+package verifier
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/k-totani/spec-verify/internal/ai"
+	"github.com/k-totani/spec-verify/internal/config"
+)
+
+// stubProvider は固定の結果と使用量を返すテスト用プロバイダー
+// percentage が0の場合は一致度90%を返す
+type stubProvider struct {
+	usage      *ai.Usage
+	percentage int
+	err        error
+	calls      atomic.Int32
+}
+
+func (p *stubProvider) Verify(ctx context.Context, specContent string, codeContents map[string]string) (*ai.VerificationResult, error) {
+	return p.VerifyWithOptions(ctx, specContent, codeContents, nil)
+}
+
+func (p *stubProvider) VerifyWithOptions(ctx context.Context, specContent string, codeContents map[string]string, opts *ai.VerifyOptions) (*ai.VerificationResult, error) {
+	p.calls.Add(1)
+	if p.err != nil {
+		return nil, p.err
+	}
+	percentage := p.percentage
+	if percentage == 0 {
+		percentage = 90
+	}
+	return &ai.VerificationResult{MatchPercentage: percentage, Usage: p.usage}, nil
+}
+
+func (p *stubProvider) ExtractEndpoints(ctx context.Context, opts *ai.ExtractOptions, codeContent string) ([]ai.EndpointResult, error) {
+	return nil, nil
+}
+
+func (p *stubProvider) Name() string  { return "claude" }
+func (p *stubProvider) Model() string { return "claude-sonnet-4-20250514" }
+
+func TestNewBudget(t *testing.T) {
+	cfg := config.DefaultConfig()
+	claude := []pricedMember{{provider: "claude", model: "claude-sonnet-4-20250514"}}
+	b, err := newBudget(cfg, claude)
+	if err != nil || b != nil {
+		t.Errorf("newBudget without limits = %v, %v; want nil, nil", b, err)
+	}
+
+	cfg.Options.MaxCostPerRun = 1
+	if _, err := newBudget(cfg, []pricedMember{{provider: "claude", model: "unknown-model"}}); err == nil {
+		t.Error("expected error for max_cost_per_run without a price")
+	}
+	if _, err := newBudget(cfg, claude); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// アンサンブルの場合はメンバーの平均料金を使用する
+	b, err = newBudget(cfg, []pricedMember{claude[0], {provider: "ollama", model: "llama3.1"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.price.InputPerMTok != 1.5 || b.price.OutputPerMTok != 7.5 {
+		t.Errorf("price = %+v, want average of members", b.price)
+	}
+}
+
+// promptOf は budget.admit に渡す準備済みのSPECを作成する
+func promptOf(prompt string, inputBytes int) *preparedSpec {
+	return &preparedSpec{prompt: prompt, inputBytes: inputBytes}
+}
+
+func TestBudgetAdmit(t *testing.T) {
+	t.Run("max input bytes per spec", func(t *testing.T) {
+		b := &budget{maxSpecBytes: 10}
+		if _, reason := b.admit(promptOf(strings.Repeat("x", 100), 10), 1); reason != "" {
+			t.Errorf("the size of the rendered prompt should not count: %s", reason)
+		}
+		if _, reason := b.admit(promptOf("short", 11), 1); !strings.Contains(reason, "max_input_bytes_per_spec") {
+			t.Errorf("reason = %q", reason)
+		}
+		// 1SPECのサイズ超過では実行全体は止めない
+		if _, reason := b.admit(promptOf("short", 5), 1); reason != "" {
+			t.Errorf("unexpected skip after oversized spec: %s", reason)
+		}
+	})
+
+	t.Run("max tokens per run", func(t *testing.T) {
+		b := &budget{maxTokens: 2000}
+		r, reason := b.admit(promptOf("prompt", 0), 1)
+		if reason != "" {
+			t.Fatalf("unexpected skip: %s", reason)
+		}
+		b.settle(r, []UsageRecord{{InputTokens: 1000, OutputTokens: 400}})
+		// 使用済み1400 + 推定(2+500) <= 2000
+		r, reason = b.admit(promptOf("prompt", 0), 1)
+		if reason != "" {
+			t.Fatalf("unexpected skip: %s", reason)
+		}
+		b.settle(r, []UsageRecord{{InputTokens: 100, OutputTokens: 100}})
+		if b.usedTokens != 1600 {
+			t.Errorf("usedTokens = %d, want the reservation replaced by the reported usage", b.usedTokens)
+		}
+		if _, reason := b.admit(promptOf("prompt", 0), 1); !strings.Contains(reason, "max_tokens_per_run") {
+			t.Errorf("reason = %q", reason)
+		}
+		// 一度上限に達したら以降は全てスキップ
+		if _, reason := b.admit(promptOf("", 0), 1); reason == "" {
+			t.Error("expected skip after budget is exceeded")
+		}
+	})
+
+	t.Run("concurrent reservations", func(t *testing.T) {
+		// 推定量は1回あたり502トークン。使用量が返る前に並列に判定しても2回分しか通さない
+		b := &budget{maxTokens: 1100}
+		var wg sync.WaitGroup
+		var admitted atomic.Int32
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, reason := b.admit(promptOf("prompt", 0), 1); reason == "" {
+					admitted.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		if admitted.Load() != 2 {
+			t.Errorf("admitted = %d, want 2", admitted.Load())
+		}
+	})
+
+	t.Run("failed call keeps the reservation", func(t *testing.T) {
+		b := &budget{maxTokens: 2000}
+		r, _ := b.admit(promptOf("prompt", 0), 1)
+		b.settle(r, nil)
+		if b.usedTokens != 502 {
+			t.Errorf("usedTokens = %d, want the estimate to stay charged", b.usedTokens)
+		}
+	})
+
+	t.Run("release", func(t *testing.T) {
+		b := &budget{maxTokens: 2000}
+		r, _ := b.admit(promptOf("prompt", 0), 1)
+		b.release(r)
+		if b.usedTokens != 0 {
+			t.Errorf("usedTokens = %d, want the reservation released", b.usedTokens)
+		}
+	})
+
+	t.Run("max cost per run", func(t *testing.T) {
+		b := &budget{maxCost: 0.01, price: ai.ModelPrice{InputPerMTok: 3, OutputPerMTok: 15}}
+		r, reason := b.admit(promptOf("prompt", 0), 1)
+		if reason != "" {
+			t.Fatalf("unexpected skip: %s", reason)
+		}
+		b.settle(r, []UsageRecord{{InputTokens: 1000, OutputTokens: 500}})
+		// 使用済み $0.0105 > $0.01
+		if _, reason := b.admit(promptOf("prompt", 0), 1); !strings.Contains(reason, "max_cost_per_run") {
+			t.Errorf("reason = %q", reason)
+		}
+	})
+
+	t.Run("fallback model price", func(t *testing.T) {
+		// 予約は先頭のプロバイダー（ollama、無料）の料金、精算は結果を返したモデルの料金で行う
+		b := &budget{maxCost: 1}
+		r, _ := b.admit(promptOf("prompt", 0), 1)
+		b.settle(r, []UsageRecord{{Provider: "claude", Model: "claude-sonnet-4-20250514", InputTokens: 1000, OutputTokens: 500}})
+		if want := 0.0105; math.Abs(b.usedCost-want) > 1e-9 {
+			t.Errorf("usedCost = %v, want %v at the fallback model's price", b.usedCost, want)
+		}
+	})
+
+	t.Run("nil budget", func(t *testing.T) {
+		var b *budget
+		r, reason := b.admit(promptOf("prompt", 1<<20), 1)
+		if reason != "" {
+			t.Errorf("nil budget should not skip: %s", reason)
+		}
+		b.settle(r, nil)
+		b.release(r)
+	})
+}
+
+func TestVerifyMultipleTypes_BudgetExceeded(t *testing.T) {
+	cfg := writeTestProject(t)
+	cfg.Options.Concurrency = 1
+	cfg.Options.MaxTokensPerRun = 1
+	disabled := false
+	cfg.Cache.Enabled = &disabled
+
+	provider := &stubProvider{usage: &ai.Usage{InputTokens: 100, OutputTokens: 50}}
+	b, err := newBudget(cfg, []pricedMember{{provider: provider.Name(), model: provider.Model()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := &Verifier{config: cfg, provider: provider, budget: b}
+
+	summary, err := v.VerifyMultipleTypes(context.Background(), []string{"ui"})
+	if err != nil {
+		t.Fatalf("VerifyMultipleTypes failed: %v", err)
+	}
+	if provider.calls.Load() != 0 {
+		t.Errorf("provider was called %d times, want 0", provider.calls.Load())
+	}
+	if summary.SkippedSpecs != 1 {
+		t.Errorf("SkippedSpecs = %d, want 1", summary.SkippedSpecs)
+	}
+	for _, r := range summary.Results {
+		if r.SpecFile == "login.md" && (!r.Skipped || r.SkipReason == "") {
+			t.Errorf("login.md should be skipped with a reason: %+v", r)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package verifier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/k-totani/spec-verify/internal/config"
+)
+
+func TestVerifyMultipleTypes_EgressDeny(t *testing.T) {
+	cfg := writeTestProject(t)
+	disabled := false
+	cfg.Cache.Enabled = &disabled
+	cfg.Egress.Deny = []string{"Login.tsx"}
+
+	v, err := newPreparer(cfg)
+	if err != nil {
+		t.Fatalf("newPreparer failed: %v", err)
+	}
+	provider := &capturingProvider{}
+	v.provider = provider
+	summary, err := v.VerifyMultipleTypes(context.Background(), []string{"ui"})
+	if err != nil {
+		t.Fatalf("VerifyMultipleTypes failed: %v", err)
+	}
+
+	login := findResult(summary, "login.md")
+	if login == nil || len(login.DroppedFiles) != 1 || !strings.HasSuffix(login.DroppedFiles[0], "Login.tsx") {
+		t.Fatalf("login.md = %+v", login)
+	}
+	if len(login.CodeFiles) != 0 || provider.calls.Load() != 0 {
+		t.Errorf("the denied file was sent: code files %v, %d calls", login.CodeFiles, provider.calls.Load())
+	}
+	if summary.DroppedFiles != 1 {
+		t.Errorf("summary.DroppedFiles = %d, want 1", summary.DroppedFiles)
+	}
+}
+
+func TestVerifyMultipleTypes_EgressDenySpec(t *testing.T) {
+	cfg := writeTestProject(t)
+	disabled := false
+	cfg.Cache.Enabled = &disabled
+	cfg.Egress.Deny = []string{"login.md"}
+
+	v, err := newPreparer(cfg)
+	if err != nil {
+		t.Fatalf("newPreparer failed: %v", err)
+	}
+	provider := &capturingProvider{}
+	v.provider = provider
+	summary, err := v.VerifyMultipleTypes(context.Background(), []string{"ui"})
+	if err != nil {
+		t.Fatalf("VerifyMultipleTypes failed: %v", err)
+	}
+
+	login := findResult(summary, "login.md")
+	if login == nil || login.Error == nil || len(login.DroppedFiles) != 1 || !strings.HasSuffix(login.DroppedFiles[0], "login.md") {
+		t.Fatalf("login.md = %+v, want an error with the spec dropped", login)
+	}
+	if provider.calls.Load() != 0 {
+		t.Errorf("the denied spec was sent: %d calls", provider.calls.Load())
+	}
+}
+
+func TestNewProvider_AuditLog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"message":{"role":"assistant","content":"{\"matchPercentage\":80,\"matchedItems\":[],\"unmatchedItems\":[],\"notes\":\"\"}"},"done":true}`))
+	}))
+	defer server.Close()
+
+	auditLog := filepath.Join(t.TempDir(), "audit.jsonl")
+	cfg := config.DefaultConfig()
+	cfg.AIProvider = "ollama"
+	cfg.AI.BaseURL = server.URL
+	cfg.Egress.AuditLog = auditLog
+
+	provider, err := NewProvider(cfg)
+	if err != nil {
+		t.Fatalf("NewProvider failed: %v", err)
+	}
+	if _, err := provider.Verify(context.Background(), "# spec", map[string]string{"src/a.ts": "code"}); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+
+	data, err := os.ReadFile(auditLog)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 1 || !strings.Contains(lines[0], `"path":"src/a.ts"`) {
+		t.Errorf("audit log = %s", data)
+	}
+}
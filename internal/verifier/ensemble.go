@@ -0,0 +1,135 @@
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/k-totani/spec-verify/internal/ai"
+	"github.com/k-totani/spec-verify/internal/config"
+)
+
+// ReviewSpec はアンサンブル検証でメンバー間の評価が割れたSPECの情報
+type ReviewSpec struct {
+	// SPECファイルのパス
+	SpecFile string `json:"specFile"`
+
+	// SPECのタイトル
+	Title string `json:"title"`
+
+	// 統合後の一致度（中央値）
+	MatchPercentage int `json:"matchPercentage"`
+
+	// メンバー間の一致度の差
+	Spread int `json:"spread"`
+}
+
+// newEnsembleProviders は設定されたアンサンブルの各メンバーのプロバイダーを作成する
+// 同じプロバイダーのメンバーは主プロバイダーとレートリミッターを共有する
+// ヒューリスティック検証による代替はメンバーごとではなく統合した結果に対して行う
+func newEnsembleProviders(cfg *config.Config, limiters rateLimiters) ([]ai.Provider, error) {
+	if !cfg.IsEnsembleEnabled() {
+		return nil, nil
+	}
+	if len(cfg.Ensemble.Members) < 2 {
+		return nil, fmt.Errorf("ensemble requires at least 2 members, got %d", len(cfg.Ensemble.Members))
+	}
+
+	providers := make([]ai.Provider, 0, len(cfg.Ensemble.Members))
+	for i, member := range cfg.Ensemble.Members {
+		p, err := newChainProvider(memberConfig(cfg, member), limiters)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ensemble member %d (%s): %w", i+1, member.Provider, err)
+		}
+		providers = append(providers, p)
+	}
+	return providers, nil
+}
+
+// memberConfig はメンバー用の設定を作成する
+// モデルとベースURLはメンバーの指定のみを使用し、APIキーは主プロバイダーと同じ場合のみ引き継ぐ
+// プロバイダーのフォールバックチェーンのフォールバック先の設定にも使用する
+func memberConfig(cfg *config.Config, member config.EnsembleMember) *config.Config {
+	memberCfg := *cfg
+	memberCfg.AIProvider = member.Provider
+	memberCfg.AIProviderFallbacks = nil
+	memberCfg.AI.Model = member.Model
+	memberCfg.AI.BaseURL = member.BaseURL
+	if ai.CanonicalProviderName(member.Provider) != ai.CanonicalProviderName(cfg.AIProvider) {
+		memberCfg.AIAPIKey = config.GetAPIKeyFromEnv(member.Provider)
+	}
+	return &memberCfg
+}
+
+// verifyEnsemble は全メンバーで並列に検証し、結果を統合する
+func (v *Verifier) verifyEnsemble(ctx context.Context, p *preparedSpec) (*ai.VerificationResult, error) {
+	members := make([]ai.MemberResult, len(v.ensemble))
+	results := make([]*ai.VerificationResult, len(v.ensemble))
+
+	var wg sync.WaitGroup
+	for i, provider := range v.ensemble {
+		members[i] = ai.MemberResult{Provider: provider.Name(), Model: provider.Model()}
+		wg.Add(1)
+		go func(i int, provider ai.Provider) {
+			defer wg.Done()
+			result, err := callProvider(ctx, provider, p)
+			if err != nil {
+				members[i].Error = err.Error()
+				if billed := ai.AsUsageError(err); billed != nil {
+					members[i].Usage = &billed.Usage
+					members[i].LatencyMs = billed.LatencyMs
+					members[i].Attempts = billed.Attempts
+				}
+				return
+			}
+			results[i] = result
+		}(i, provider)
+	}
+	wg.Wait()
+
+	combined, err := ai.CombineResults(members, results, v.config.GetReviewThreshold())
+	if err != nil {
+		return v.heuristicFallback(ctx, p, "ensemble", err)
+	}
+	return combined, nil
+}
+
+// memberIdentity はメンバーのプロバイダー名とモデル名を返す（Provider.Name(), Model() と同じ値）
+func memberIdentity(member config.EnsembleMember) (name, model string) {
+	model = member.Model
+	if model == "" {
+		model = ai.DefaultModel(member.Provider)
+	}
+	return ai.CanonicalProviderName(member.Provider), model
+}
+
+// ensembleIdentity はキャッシュキーや見積もりに使用するアンサンブルの識別子を返す
+// 要レビューの閾値も結果に影響するため含める
+func ensembleIdentity(cfg *config.Config) (name, model string) {
+	models := make([]string, len(cfg.Ensemble.Members))
+	for i, member := range cfg.Ensemble.Members {
+		name, model := memberIdentity(member)
+		models[i] = name + "/" + model
+	}
+	return "ensemble", fmt.Sprintf("%s;review=%d", strings.Join(models, ","), cfg.GetReviewThreshold())
+}
+
+// buildReviewSpecs はメンバー間の評価が割れたSPECを抽出する
+func buildReviewSpecs(results []Result) []ReviewSpec {
+	var review []ReviewSpec
+	for _, result := range results {
+		if result.Error != nil || result.Verification == nil || result.Verification.Consensus == nil {
+			continue
+		}
+		if c := result.Verification.Consensus; c.NeedsReview {
+			review = append(review, ReviewSpec{
+				SpecFile:        result.SpecFile,
+				Title:           result.Title,
+				MatchPercentage: result.Verification.MatchPercentage,
+				Spread:          c.Spread,
+			})
+		}
+	}
+	return review
+}
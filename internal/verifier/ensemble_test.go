@@ -0,0 +1,180 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/k-totani/spec-verify/internal/ai"
+	"github.com/k-totani/spec-verify/internal/cache"
+	"github.com/k-totani/spec-verify/internal/config"
+)
+
+func TestVerifyMultipleTypes_Ensemble(t *testing.T) {
+	cfg := writeTestProject(t)
+	disabled := false
+	cfg.Cache.Enabled = &disabled
+	cfg.Ensemble.Members = []config.EnsembleMember{
+		{Provider: "claude"},
+		{Provider: "openai"},
+		{Provider: "ollama"},
+	}
+
+	members := []*stubProvider{
+		{percentage: 90, usage: &ai.Usage{InputTokens: 100, OutputTokens: 10}},
+		{percentage: 80},
+		{percentage: 40},
+	}
+	ensemble := make([]ai.Provider, len(members))
+	for i, m := range members {
+		ensemble[i] = m
+	}
+	v := &Verifier{config: cfg, ensemble: ensemble}
+
+	summary, err := v.VerifyMultipleTypes(context.Background(), []string{"ui"})
+	if err != nil {
+		t.Fatalf("VerifyMultipleTypes failed: %v", err)
+	}
+
+	for i, m := range members {
+		if m.calls.Load() != 1 {
+			t.Errorf("member %d was called %d times, want 1", i, m.calls.Load())
+		}
+	}
+
+	var login *Result
+	for i := range summary.Results {
+		if summary.Results[i].SpecFile == "login.md" {
+			login = &summary.Results[i]
+		}
+	}
+	if login == nil || login.Verification == nil {
+		t.Fatalf("login.md was not verified: %+v", summary.Results)
+	}
+	if login.Verification.MatchPercentage != 80 {
+		t.Errorf("MatchPercentage = %d, want median 80", login.Verification.MatchPercentage)
+	}
+	if c := login.Verification.Consensus; c == nil || c.Spread != 50 || !c.NeedsReview {
+		t.Errorf("Consensus = %+v, want spread 50 and needs review", c)
+	}
+	if len(summary.ReviewSpecs) != 1 || summary.ReviewSpecs[0].SpecFile != "login.md" {
+		t.Errorf("ReviewSpecs = %+v", summary.ReviewSpecs)
+	}
+}
+
+func TestVerifyMultipleTypes_EnsemblePartialFailure(t *testing.T) {
+	cfg := writeTestProject(t)
+	disabled := false
+	cfg.Cache.Enabled = &disabled
+	cfg.Ensemble.Members = []config.EnsembleMember{{Provider: "claude"}, {Provider: "openai"}}
+
+	// 2番目のメンバーは分割したチャンクの途中で失敗し、それまでにトークンを消費している
+	failed := &ai.UsageError{Err: errors.New("chunk 2/2: boom"), Usage: ai.Usage{InputTokens: 50, OutputTokens: 5}, Attempts: 2}
+	v := &Verifier{config: cfg, ensemble: []ai.Provider{
+		&accountingProvider{},
+		&stubProvider{err: failed},
+	}}
+
+	summary, err := v.VerifyMultipleTypes(context.Background(), []string{"ui"})
+	if err != nil {
+		t.Fatalf("VerifyMultipleTypes failed: %v", err)
+	}
+	login := findResult(summary, "login.md")
+	if login == nil || login.Verification == nil || login.Verification.Consensus == nil {
+		t.Fatalf("login.md = %+v", login)
+	}
+	if !login.Verification.Consensus.NeedsReview || len(summary.ReviewSpecs) != 1 {
+		t.Errorf("Consensus = %+v, want needs review when a member failed", login.Verification.Consensus)
+	}
+	var input int
+	for _, record := range login.Usage {
+		input += record.InputTokens
+	}
+	if len(login.Usage) != 2 || input != 1050 {
+		t.Errorf("Usage = %+v, want the failed member's usage included", login.Usage)
+	}
+}
+
+func TestEnsembleIdentity(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Ensemble.Members = []config.EnsembleMember{
+		{Provider: "anthropic"},
+		{Provider: "openai", Model: "gpt-4o-mini"},
+	}
+
+	name, model := ensembleIdentity(cfg)
+	if name != "ensemble" {
+		t.Errorf("name = %q", name)
+	}
+	want := "claude/" + ai.DefaultModel("claude") + ",openai/gpt-4o-mini;review=20"
+	if model != want {
+		t.Errorf("model = %q, want %q", model, want)
+	}
+}
+
+func TestNewEnsembleProviders_RequiresTwoMembers(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Ensemble.Members = []config.EnsembleMember{{Provider: "ollama"}}
+	if _, err := newEnsembleProviders(cfg, rateLimiters{}); err == nil {
+		t.Error("expected error for a single-member ensemble")
+	}
+}
+
+func TestVerifyMultipleTypes_EnsembleHeuristicFallback(t *testing.T) {
+	cfg := writeTestProject(t)
+	spec := "# ログイン画面\n\n## 要素\n- `Login` コンポーネント\n\n## 関連ファイル\n- `~/ui/Login.tsx`\n"
+	if err := os.WriteFile(filepath.Join(cfg.SpecsDir, "ui", "login.md"), []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	failing := errors.New("API error (status 503)")
+	members := []*stubProvider{{err: failing}, {err: failing}}
+	v := &Verifier{
+		config:    cfg,
+		ensemble:  []ai.Provider{members[0], members[1]},
+		heuristic: ai.NewHeuristicProvider(),
+		cache:     cache.New(cfg.Cache.Dir),
+	}
+
+	for run := 1; run <= 2; run++ {
+		summary, err := v.VerifyMultipleTypes(context.Background(), []string{"ui"})
+		if err != nil {
+			t.Fatalf("VerifyMultipleTypes failed: %v", err)
+		}
+		var login *Result
+		for i := range summary.Results {
+			if summary.Results[i].SpecFile == "login.md" {
+				login = &summary.Results[i]
+			}
+		}
+		if login == nil || login.Verification == nil || login.Verification.Fallback != ai.HeuristicProviderName {
+			t.Fatalf("run %d: login.md = %+v, want a heuristic fallback", run, login)
+		}
+		if login.Cached {
+			t.Errorf("run %d: a fallback result should not be cached", run)
+		}
+	}
+	for i, m := range members {
+		if m.calls.Load() != 2 {
+			t.Errorf("member %d was called %d times, want 2", i, m.calls.Load())
+		}
+	}
+}
+
+func TestNewEnsembleProviders_NoHeuristicFallback(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AI.HeuristicFallback = true
+	cfg.Ensemble.Members = []config.EnsembleMember{{Provider: "ollama"}, {Provider: "ollama", Model: "llama3"}}
+
+	providers, err := newEnsembleProviders(cfg, rateLimiters{})
+	if err != nil {
+		t.Fatalf("newEnsembleProviders failed: %v", err)
+	}
+	for i, p := range providers {
+		if _, ok := p.(*ai.HeuristicFallbackProvider); ok {
+			t.Errorf("member %d should not fall back individually", i)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package verifier
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/k-totani/spec-verify/internal/ai"
+	"github.com/k-totani/spec-verify/internal/cache"
+	"github.com/k-totani/spec-verify/internal/config"
+	"github.com/k-totani/spec-verify/internal/parser"
+)
+
+// SpecEstimate は単一SPECのコスト見積もり
+type SpecEstimate struct {
+	// SPECファイルのパス
+	SpecFile string `json:"specFile"`
+
+	// SPECのタイトル
+	Title string `json:"title"`
+
+	// 選択されたコードファイル
+	CodeFiles []string `json:"codeFiles"`
+
+	// コードファイルの合計バイト数
+	CodeBytes int `json:"codeBytes"`
+
+	// プロンプト全体のバイト数
+	PromptBytes int `json:"promptBytes"`
+
+	// 推定入力トークン数（アンサンブルの場合は全メンバーの合計）
+	InputTokens int `json:"inputTokens"`
+
+	// 推定出力トークン数（アンサンブルの場合は全メンバーの合計）
+	OutputTokens int `json:"outputTokens"`
+
+	// 推定コスト（USD）
+	Cost float64 `json:"cost"`
+
+	// APIを呼び出す回数（セクション単位の検証ではセクション数×1SPECあたりの呼び出し回数）
+	Requests int `json:"requests"`
+
+	// キャッシュ済みのため実行時にAPIを呼び出さない
+	Cached bool `json:"cached,omitempty"`
+
+	// 関連コードがないため実行時にAPIを呼び出さない
+	NoCode bool `json:"noCode,omitempty"`
+
+	// エラー（SPECの解析やコードの読み込みに失敗した場合）
+	Error string `json:"error,omitempty"`
+}
+
+// Estimate はドライランによるコスト見積もり
+type Estimate struct {
+	// プロバイダー名
+	Provider string `json:"provider"`
+
+	// モデル名
+	Model string `json:"model"`
+
+	// 料金表にモデルが見つかったか（false の場合コストは0）
+	PriceKnown bool `json:"priceKnown"`
+
+	// 1SPECあたりのAPI呼び出し回数（アンサンブル検証ではメンバー数、サンプリングではサンプル数）
+	CallsPerSpec int `json:"callsPerSpec"`
+
+	// 使用した料金（USD / 100万トークン。アンサンブルの場合はメンバーの合計）
+	InputPerMTok  float64 `json:"inputPerMTok"`
+	OutputPerMTok float64 `json:"outputPerMTok"`
+
+	// SPEC数
+	TotalSpecs int `json:"totalSpecs"`
+
+	// APIを呼び出す予定のリクエスト数
+	Requests int `json:"requests"`
+
+	// 選択されたコードファイル数（SPEC間の重複を含む）
+	TotalFiles int `json:"totalFiles"`
+
+	// 送信するプロンプトの合計バイト数
+	TotalBytes int `json:"totalBytes"`
+
+	// 推定入力トークン数の合計
+	InputTokens int `json:"inputTokens"`
+
+	// 推定出力トークン数の合計
+	OutputTokens int `json:"outputTokens"`
+
+	// 推定コストの合計（USD）
+	Cost float64 `json:"cost"`
+
+	// SPECごとの見積もり
+	Specs []SpecEstimate `json:"specs"`
+}
+
+// configuredModel は ai_provider で使用するモデル名を返す（Provider.Model() と同じ値）
+func configuredModel(cfg *config.Config) string {
+	model := cfg.AI.Model
+	if model == "" && ai.CanonicalProviderName(cfg.AIProvider) == ai.AzureOpenAIProviderName {
+		model = cfg.AI.Deployment
+	}
+	if model == "" {
+		model = ai.DefaultModel(cfg.AIProvider)
+	}
+	return model
+}
+
+// EstimateMultipleTypes はAIを呼び出さずに、実際の検証と同じ手順でSPECとコードを選択して
+// プロンプトを構築し、トークン数とコストを見積もる
+// APIキーは不要で、プロバイダーも作成しない
+func EstimateMultipleTypes(cfg *config.Config, specTypes []string) (*Estimate, error) {
+	v, err := newPreparer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.IsCacheEnabled() {
+		v.cache = cache.New(cfg.Cache.Dir)
+	}
+
+	providerName := ai.CanonicalProviderName(cfg.AIProvider)
+	model := configuredModel(cfg)
+	members := []config.EnsembleMember{{Provider: cfg.AIProvider, Model: model}}
+	switch {
+	case cfg.IsEnsembleEnabled():
+		providerName, model = ensembleIdentity(cfg)
+		members = cfg.Ensemble.Members
+	case cfg.GetSamples() > 1:
+		// サンプリングでは同じモデルをサンプル数だけ呼び出す
+		providerName, model = sampleIdentity(cfg, providerName, model)
+		members = slices.Repeat(members, cfg.GetSamples())
+	}
+
+	// 各メンバーに同じプロンプトを送信するため、料金はメンバーの合計になる
+	var price ai.ModelPrice
+	known := true
+	pricing := pricingFromConfig(cfg)
+	for _, member := range members {
+		name, memberModel := memberIdentity(member)
+		p, ok := ai.LookupPrice(name, memberModel, pricing)
+		known = known && ok
+		price.InputPerMTok += p.InputPerMTok
+		price.OutputPerMTok += p.OutputPerMTok
+	}
+
+	estimate := &Estimate{
+		Provider:      providerName,
+		Model:         model,
+		PriceKnown:    known,
+		CallsPerSpec:  len(members),
+		InputPerMTok:  price.InputPerMTok,
+		OutputPerMTok: price.OutputPerMTok,
+		Specs:         []SpecEstimate{},
+	}
+
+	if len(specTypes) == 0 {
+		specTypes = []string{""}
+	}
+	for _, specType := range specTypes {
+		specFiles, err := parser.FindSpecFiles(cfg.SpecsDir, specType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find spec files for type %s: %w", specType, err)
+		}
+
+		for _, specFile := range specFiles {
+			se := v.estimateOne(specFile, providerName, model, price, len(members))
+			estimate.Specs = append(estimate.Specs, se)
+
+			estimate.TotalSpecs++
+			estimate.TotalFiles += len(se.CodeFiles)
+			if se.Cached || se.NoCode || se.Error != "" {
+				continue
+			}
+			estimate.Requests += se.Requests
+			estimate.TotalBytes += se.PromptBytes
+			estimate.InputTokens += se.InputTokens
+			estimate.OutputTokens += se.OutputTokens
+			estimate.Cost += se.Cost
+		}
+	}
+
+	return estimate, nil
+}
+
+// estimateOne は単一SPECの見積もりを行う
+func (v *Verifier) estimateOne(specFile, providerName, model string, price ai.ModelPrice, calls int) SpecEstimate {
+	result := Result{SpecFile: filepath.Base(specFile)}
+	prepared := v.prepare(specFile, &result)
+
+	se := SpecEstimate{
+		SpecFile:  result.SpecFile,
+		Title:     result.Title,
+		CodeFiles: result.CodeFiles,
+	}
+	if result.Error != nil {
+		se.Error = result.Error.Error()
+		return se
+	}
+	if prepared == nil {
+		se.NoCode = true
+		return se
+	}
+
+	for _, content := range prepared.codeContents {
+		se.CodeBytes += len(content)
+	}
+	// セクション単位の検証ではセクションごとにプロンプトを送信する
+	prompts := []*preparedSpec{prepared}
+	if v.config.IsSectionsEnabled() {
+		sections, err := v.sectionSpecs(prepared)
+		if err != nil {
+			se.Error = err.Error()
+			return se
+		}
+		if len(sections) > 0 {
+			prompts = prompts[:0]
+			for _, section := range sections {
+				prompts = append(prompts, section.prepared)
+			}
+		}
+	}
+
+	se.Cached = v.cache != nil
+	for _, p := range prompts {
+		se.PromptBytes += len(p.prompt)
+		usage := estimateUsage(p.prompt, 1)
+		se.InputTokens += usage.InputTokens * calls
+		se.OutputTokens += usage.OutputTokens * calls
+		se.Cost += price.Cost(usage.InputTokens, usage.OutputTokens)
+		se.Cached = se.Cached && v.cache.Has(cacheKey(v.config, providerName, model, p))
+	}
+	se.Requests = len(prompts) * calls
+	return se
+}
@@ -0,0 +1,99 @@
+package verifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/k-totani/spec-verify/internal/config"
+)
+
+// writeTestProject はSPECとコードを含むテスト用プロジェクトを作成する
+func writeTestProject(t *testing.T) *config.Config {
+	t.Helper()
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"specs/ui/login.md": "# ログイン画面\n\n## 関連ファイル\n- `~/ui/Login.tsx`\n",
+		"specs/ui/empty.md": "# 未実装画面\n\n## 関連ファイル\n- `~/ui/Missing.tsx`\n",
+		"src/ui/Login.tsx":  "export function Login() { return <form>login</form> }\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.SpecsDir = filepath.Join(dir, "specs")
+	cfg.CodeDir = filepath.Join(dir, "src")
+	cfg.AIProvider = "claude"
+	cfg.Mapping = map[string]string{"ui": "ui"}
+	cfg.Cache.Dir = filepath.Join(dir, ".specverify", "cache")
+	return cfg
+}
+
+func TestEstimateMultipleTypes(t *testing.T) {
+	cfg := writeTestProject(t)
+	cfg.AI.Pricing = map[string]config.ModelPrice{
+		"claude-sonnet-4-20250514": {InputPerMTok: 1_000_000, OutputPerMTok: 0},
+	}
+
+	estimate, err := EstimateMultipleTypes(cfg, nil)
+	if err != nil {
+		t.Fatalf("EstimateMultipleTypes failed: %v", err)
+	}
+
+	if estimate.Provider != "claude" || estimate.Model != "claude-sonnet-4-20250514" {
+		t.Errorf("provider/model = %s/%s", estimate.Provider, estimate.Model)
+	}
+	if !estimate.PriceKnown {
+		t.Error("expected price from ai.pricing")
+	}
+	if estimate.TotalSpecs != 2 {
+		t.Fatalf("TotalSpecs = %d, want 2", estimate.TotalSpecs)
+	}
+
+	var login *SpecEstimate
+	for i := range estimate.Specs {
+		if estimate.Specs[i].SpecFile == "login.md" {
+			login = &estimate.Specs[i]
+		}
+	}
+	if login == nil {
+		t.Fatal("login.md not found in estimate")
+	}
+	if len(login.CodeFiles) != 1 || login.CodeBytes == 0 || login.InputTokens == 0 {
+		t.Errorf("unexpected login estimate: %+v", login)
+	}
+	// 入力1トークンあたり$1に設定しているのでコストは入力トークン数と一致する
+	if login.Cost != float64(login.InputTokens) {
+		t.Errorf("Cost = %v, want %v", login.Cost, float64(login.InputTokens))
+	}
+	if estimate.Requests != 1 || estimate.InputTokens != login.InputTokens {
+		t.Errorf("totals = %d requests, %d tokens", estimate.Requests, estimate.InputTokens)
+	}
+}
+
+func TestEstimateMultipleTypes_UnknownModel(t *testing.T) {
+	cfg := writeTestProject(t)
+	cfg.AI.Model = "unreleased-model"
+
+	estimate, err := EstimateMultipleTypes(cfg, []string{"ui"})
+	if err != nil {
+		t.Fatalf("EstimateMultipleTypes failed: %v", err)
+	}
+	if estimate.PriceKnown {
+		t.Error("expected unknown price for unlisted model")
+	}
+	if estimate.Cost != 0 {
+		t.Errorf("Cost = %v, want 0", estimate.Cost)
+	}
+	if estimate.InputTokens == 0 {
+		t.Error("tokens should be estimated even without a price")
+	}
+}
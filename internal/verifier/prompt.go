@@ -0,0 +1,146 @@
+package verifier
+
+import (
+	"path/filepath"
+
+	"github.com/k-totani/spec-verify/internal/ai"
+	"github.com/k-totani/spec-verify/internal/config"
+)
+
+// PromptPreview はSPECの検証でAIに送信するプロンプトの一覧
+type PromptPreview struct {
+	// 使用するテンプレート（組み込みのテンプレートの場合は "builtin"）
+	Template string `json:"template"`
+
+	// コードを分割して検証する場合に、断片ごとに根拠を抽出するテンプレートと
+	// 集めた根拠で最終的な検証を行うテンプレート（分割するプロンプトがない場合は空）
+	EvidenceTemplate string `json:"evidenceTemplate,omitempty"`
+	ReduceTemplate   string `json:"reduceTemplate,omitempty"`
+
+	// プロンプト（関連コードが見つからずAIに送信しない場合は空）
+	Prompts []RenderedPrompt `json:"prompts"`
+}
+
+// RenderedPrompt はAIに送信する検証プロンプト
+type RenderedPrompt struct {
+	// セクション名（SPEC全体を検証する場合は空）
+	Section string `json:"section,omitempty"`
+
+	// コードを分割して検証する場合の断片の番号と断片の数（分割しない場合は0）
+	Chunk  int `json:"chunk,omitempty"`
+	Chunks int `json:"chunks,omitempty"`
+
+	// プロンプト
+	Prompt string `json:"prompt"`
+}
+
+// promptTemplates は設定ファイルで指定されたプロンプトテンプレート（キーはテンプレートファイルのパス）
+type promptTemplates struct {
+	verify   map[string]*ai.PromptTemplate
+	evidence map[string]*ai.PromptTemplate
+	reduce   map[string]*ai.PromptTemplate
+}
+
+// loadPromptTemplates は設定ファイルで指定されたプロンプトテンプレートを読み込む
+// 複数のSPECタイプで同じファイルを指定した場合は1回だけ読み込む
+func loadPromptTemplates(cfg *config.Config) (promptTemplates, error) {
+	verifyPaths := []string{cfg.Prompts.Verify}
+	evidencePaths := []string{cfg.Prompts.Evidence}
+	reducePaths := []string{cfg.Prompts.Reduce}
+	for _, specType := range cfg.SpecTypes {
+		verifyPaths = append(verifyPaths, specType.Prompt)
+		evidencePaths = append(evidencePaths, specType.EvidencePrompt)
+		reducePaths = append(reducePaths, specType.ReducePrompt)
+	}
+
+	var templates promptTemplates
+	var err error
+	if templates.verify, err = loadTemplateFiles(verifyPaths, ai.LoadVerificationTemplate); err != nil {
+		return promptTemplates{}, err
+	}
+	if templates.evidence, err = loadTemplateFiles(evidencePaths, ai.LoadEvidenceTemplate); err != nil {
+		return promptTemplates{}, err
+	}
+	if templates.reduce, err = loadTemplateFiles(reducePaths, ai.LoadReduceTemplate); err != nil {
+		return promptTemplates{}, err
+	}
+	return templates, nil
+}
+
+// loadTemplateFiles は paths のテンプレートを load で読み込む（空のパスは除く）
+func loadTemplateFiles(paths []string, load func(string) (*ai.PromptTemplate, error)) (map[string]*ai.PromptTemplate, error) {
+	templates := map[string]*ai.PromptTemplate{}
+	for _, path := range paths {
+		if path == "" || templates[path] != nil {
+			continue
+		}
+		t, err := load(path)
+		if err != nil {
+			return nil, err
+		}
+		templates[path] = t
+	}
+	return templates, nil
+}
+
+// templateName はテンプレートファイルのパスを表示用の名前にする
+// 組み込みのテンプレートを使用する場合（パスが空）は "builtin" を返す
+func templateName(path string) string {
+	if path != "" {
+		return path
+	}
+	return "builtin"
+}
+
+// RenderPrompts はSPECの検証でAIに送信するプロンプトを描画する（AIは呼び出さない）
+// セクション単位の検証が有効な場合はセクションごとのプロンプトを返す
+// プロンプトがモデルのコンテキストウィンドウに収まらない場合は、検証プロンプトの代わりに断片ごとの根拠抽出のプロンプトを返す
+// 最終的な検証のプロンプトは抽出した根拠から作成するため含まない
+func RenderPrompts(cfg *config.Config, specFile string) (*PromptPreview, error) {
+	v, err := newPreparer(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	result := Result{SpecFile: filepath.Base(specFile)}
+	prepared := v.prepare(specFile, &result)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	preview := &PromptPreview{Prompts: []RenderedPrompt{}}
+	if prepared == nil {
+		preview.Template = templateName(cfg.GetPromptTemplate(""))
+		return preview, nil
+	}
+	preview.Template = templateName(cfg.GetPromptTemplate(prepared.spec.Type))
+
+	targets := []sectionSpec{{prepared: prepared}}
+	if cfg.IsSectionsEnabled() {
+		sections, err := v.sectionSpecs(prepared)
+		if err != nil {
+			return nil, err
+		}
+		if len(sections) > 0 {
+			targets = sections
+		}
+	}
+
+	model := configuredModel(cfg)
+	for _, target := range targets {
+		p := target.prepared
+		chunks, err := ai.BuildChunkPrompts(model, cfg.AI.ContextWindows, cfg.AI.MaxOutputTokens, p.content, p.codeContents, p.opts)
+		if err != nil {
+			return nil, err
+		}
+		if chunks == nil {
+			preview.Prompts = append(preview.Prompts, RenderedPrompt{Section: target.name, Prompt: p.prompt})
+			continue
+		}
+		preview.EvidenceTemplate = templateName(cfg.GetEvidenceTemplate(prepared.spec.Type))
+		preview.ReduceTemplate = templateName(cfg.GetReduceTemplate(prepared.spec.Type))
+		for i, chunk := range chunks {
+			preview.Prompts = append(preview.Prompts, RenderedPrompt{Section: target.name, Chunk: i + 1, Chunks: len(chunks), Prompt: chunk})
+		}
+	}
+	return preview, nil
+}
@@ -0,0 +1,126 @@
+package verifier
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/k-totani/spec-verify/internal/ai"
+	"github.com/k-totani/spec-verify/internal/config"
+)
+
+func TestRenderPrompts_Templates(t *testing.T) {
+	cfg := writeTestProject(t)
+	dir := filepath.Dir(cfg.SpecsDir)
+	projectTemplate := filepath.Join(dir, "verify.tmpl")
+	uiTemplate := filepath.Join(dir, "ui.tmpl")
+	if err := os.WriteFile(projectTemplate, []byte("project {{.Title}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(uiTemplate, []byte("ui {{.SpecType}} {{.Title}}\n{{range .Files}}{{.Path}}{{end}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	specFile := filepath.Join(cfg.SpecsDir, "ui", "login.md")
+
+	preview, err := RenderPrompts(cfg, specFile)
+	if err != nil {
+		t.Fatalf("RenderPrompts failed: %v", err)
+	}
+	if preview.Template != "builtin" || len(preview.Prompts) != 1 || !strings.Contains(preview.Prompts[0].Prompt, "# ログイン画面") {
+		t.Errorf("builtin preview = %+v", preview)
+	}
+
+	cfg.Prompts.Verify = projectTemplate
+	preview, err = RenderPrompts(cfg, specFile)
+	if err != nil {
+		t.Fatalf("RenderPrompts failed: %v", err)
+	}
+	if preview.Prompts[0].Prompt != "project ログイン画面" {
+		t.Errorf("project template prompt = %q", preview.Prompts[0].Prompt)
+	}
+
+	// SPECタイプのテンプレートはプロジェクトのテンプレートより優先する
+	cfg.SpecTypes = map[string]config.SpecType{"ui": {Prompt: uiTemplate}}
+	preview, err = RenderPrompts(cfg, specFile)
+	if err != nil {
+		t.Fatalf("RenderPrompts failed: %v", err)
+	}
+	if preview.Template != uiTemplate || !strings.HasPrefix(preview.Prompts[0].Prompt, "ui ui ログイン画面\n") ||
+		!strings.HasSuffix(preview.Prompts[0].Prompt, "Login.tsx") {
+		t.Errorf("spec type template preview = %+v", preview)
+	}
+}
+
+func TestRenderPrompts_NoCode(t *testing.T) {
+	cfg := writeTestProject(t)
+	preview, err := RenderPrompts(cfg, filepath.Join(cfg.SpecsDir, "ui", "empty.md"))
+	if err != nil {
+		t.Fatalf("RenderPrompts failed: %v", err)
+	}
+	if len(preview.Prompts) != 0 {
+		t.Errorf("prompts = %+v, want none", preview.Prompts)
+	}
+}
+
+func TestEstimateMultipleTypes_InvalidTemplate(t *testing.T) {
+	cfg := writeTestProject(t)
+	cfg.Prompts.Verify = filepath.Join(filepath.Dir(cfg.SpecsDir), "missing.tmpl")
+	if _, err := EstimateMultipleTypes(cfg, []string{"ui"}); err == nil {
+		t.Error("a missing template should be reported before verification")
+	}
+}
+
+func TestRenderPrompts_Language(t *testing.T) {
+	cfg := writeTestProject(t)
+	cfg.Language = "en"
+	specFile := filepath.Join(cfg.SpecsDir, "ui", "login.md")
+
+	preview, err := RenderPrompts(cfg, specFile)
+	if err != nil {
+		t.Fatalf("RenderPrompts failed: %v", err)
+	}
+	prompt := preview.Prompts[0].Prompt
+	for _, want := range []string{"1. Layout: ", "Englishで記述してください。"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt does not contain %q:\n%s", want, prompt)
+		}
+	}
+
+	result := Result{}
+	v := &Verifier{config: cfg}
+	v.prepare(filepath.Join(cfg.SpecsDir, "ui", "empty.md"), &result)
+	if got := result.Verification.UnmatchedItems; len(got) != 1 || got[0] != "No matching code found" {
+		t.Errorf("unmatched items = %v", got)
+	}
+}
+
+func TestRenderPrompts_Chunks(t *testing.T) {
+	cfg := writeTestProject(t)
+	dir := filepath.Dir(cfg.SpecsDir)
+	code := strings.Repeat("export const item = 'login';\n", 600)
+	if err := os.WriteFile(filepath.Join(cfg.CodeDir, "ui", "Login.tsx"), []byte(code), 0644); err != nil {
+		t.Fatal(err)
+	}
+	evidenceTemplate := filepath.Join(dir, "evidence.tmpl")
+	if err := os.WriteFile(evidenceTemplate, []byte("evidence {{.Index}}/{{.Total}}\n{{.Code}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg.AI.ContextWindows = map[string]int{ai.DefaultModel("claude"): 4000}
+	cfg.Prompts.Evidence = evidenceTemplate
+
+	// コンテキストウィンドウに収まらない場合は、実際に送信する断片ごとのプロンプトを返す
+	preview, err := RenderPrompts(cfg, filepath.Join(cfg.SpecsDir, "ui", "login.md"))
+	if err != nil {
+		t.Fatalf("RenderPrompts failed: %v", err)
+	}
+	if preview.EvidenceTemplate != evidenceTemplate || preview.ReduceTemplate != "builtin" || len(preview.Prompts) < 2 {
+		t.Fatalf("preview = %+v", preview)
+	}
+	for i, p := range preview.Prompts {
+		if p.Chunk != i+1 || p.Chunks != len(preview.Prompts) || !strings.HasPrefix(p.Prompt, fmt.Sprintf("evidence %d/%d", i+1, len(preview.Prompts))) {
+			t.Errorf("prompt %d = chunk %d/%d %.40q", i, p.Chunk, p.Chunks, p.Prompt)
+		}
+	}
+}
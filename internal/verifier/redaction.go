@@ -0,0 +1,12 @@
+package verifier
+
+import "github.com/k-totani/spec-verify/internal/redact"
+
+// buildRedactionTotal は全SPECで置き換えた機密情報の件数を返す
+func buildRedactionTotal(results []Result) int {
+	total := 0
+	for _, result := range results {
+		total += redact.Total(result.Redactions)
+	}
+	return total
+}
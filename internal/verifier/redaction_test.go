@@ -0,0 +1,79 @@
+package verifier
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/k-totani/spec-verify/internal/ai"
+	"github.com/k-totani/spec-verify/internal/config"
+	"github.com/k-totani/spec-verify/internal/redact"
+)
+
+// capturingProvider は受け取ったコードを記録するテスト用プロバイダー
+type capturingProvider struct {
+	stubProvider
+	mu   sync.Mutex
+	code []string
+}
+
+func (p *capturingProvider) VerifyWithOptions(ctx context.Context, specContent string, codeContents map[string]string, opts *ai.VerifyOptions) (*ai.VerificationResult, error) {
+	p.mu.Lock()
+	for _, content := range codeContents {
+		p.code = append(p.code, content)
+	}
+	p.mu.Unlock()
+	return p.stubProvider.VerifyWithOptions(ctx, specContent, codeContents, opts)
+}
+
+func (p *capturingProvider) Verify(ctx context.Context, specContent string, codeContents map[string]string) (*ai.VerificationResult, error) {
+	return p.VerifyWithOptions(ctx, specContent, codeContents, nil)
+}
+
+func TestVerifyMultipleTypes_Redaction(t *testing.T) {
+	cfg := writeTestProject(t)
+	disabled := false
+	cfg.Cache.Enabled = &disabled
+	cfg.Redaction.Patterns = []config.RedactionPattern{{Name: "customer_id", Regex: `CUST-\d{8}`}}
+	code := "const apiKey = \"sk-ant-REDACTED\"\n" +
+		"export function Login() { return <form data-owner=\"owner@example.com\" data-customer=\"CUST-00001234\">login</form> }\n"
+	if err := os.WriteFile(filepath.Join(cfg.CodeDir, "ui", "Login.tsx"), []byte(code), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	redactor, err := redact.FromConfig(cfg)
+	if err != nil {
+		t.Fatalf("FromConfig failed: %v", err)
+	}
+	provider := &capturingProvider{}
+	v := &Verifier{config: cfg, provider: provider, redactor: redactor}
+	summary, err := v.VerifyMultipleTypes(context.Background(), []string{"ui"})
+	if err != nil {
+		t.Fatalf("VerifyMultipleTypes failed: %v", err)
+	}
+
+	if len(provider.code) != 1 {
+		t.Fatalf("provider received %d files, want 1", len(provider.code))
+	}
+	for _, secret := range []string{"sk-ant-api03", "owner@example.com", "CUST-00001234"} {
+		if strings.Contains(provider.code[0], secret) {
+			t.Errorf("%q was sent to the provider:\n%s", secret, provider.code[0])
+		}
+	}
+	login := findResult(summary, "login.md")
+	want := map[string]int{redact.RuleAPIKey: 1, redact.RuleEmail: 1, "customer_id": 1}
+	if login == nil || len(login.Redactions) != len(want) {
+		t.Fatalf("login.md = %+v", login)
+	}
+	for name, n := range want {
+		if login.Redactions[name] != n {
+			t.Errorf("Redactions[%s] = %d, want %d", name, login.Redactions[name], n)
+		}
+	}
+	if summary.Redactions != 3 {
+		t.Errorf("summary.Redactions = %d, want 3", summary.Redactions)
+	}
+}
@@ -0,0 +1,118 @@
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/k-totani/spec-verify/internal/ai"
+	"github.com/k-totani/spec-verify/internal/config"
+)
+
+// UnstableSpec は自己一貫性チェックでサンプル間の一致度のばらつきが大きかったSPECの情報
+type UnstableSpec struct {
+	// SPECファイルのパス
+	SpecFile string `json:"specFile"`
+
+	// SPECのタイトル
+	Title string `json:"title"`
+
+	// 統合後の一致度（中央値）
+	MatchPercentage int `json:"matchPercentage"`
+
+	// サンプル間の一致度の最小値と最大値
+	Min int `json:"min"`
+	Max int `json:"max"`
+
+	// サンプル間の一致度の標準偏差
+	StdDev float64 `json:"stdDev"`
+}
+
+// newSampleProviders は自己一貫性チェックの各サンプルを実行するプロバイダーを作成する
+// sample_temperatures が未設定の場合は全サンプルで provider を使用する
+// temperatureごとにプロバイダーを作成する場合もレートリミッターは共有する
+// provider にはヒューリスティック検証で代替しないプロバイダーを渡す（代替は統合した結果に対して行う）
+func newSampleProviders(cfg *config.Config, provider ai.Provider, limiters rateLimiters) ([]ai.Provider, error) {
+	samples := cfg.GetSamples()
+	if samples <= 1 {
+		return nil, nil
+	}
+	if cfg.IsEnsembleEnabled() {
+		return nil, fmt.Errorf("options.samples cannot be combined with ensemble")
+	}
+
+	providers := make([]ai.Provider, samples)
+	for i := range providers {
+		if len(cfg.Options.SampleTemperatures) == 0 {
+			providers[i] = provider
+			continue
+		}
+		sampleCfg := *cfg
+		sampleCfg.AI.Temperature = cfg.GetSampleTemperature(i)
+		p, err := newChainProvider(&sampleCfg, limiters)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create provider for sample %d: %w", i+1, err)
+		}
+		providers[i] = p
+	}
+	return providers, nil
+}
+
+// verifySamples は同じプロンプトで複数回並列に検証し、結果を統合する
+func (v *Verifier) verifySamples(ctx context.Context, p *preparedSpec) (*ai.VerificationResult, error) {
+	results := make([]*ai.VerificationResult, len(v.samplers))
+	errs := make([]error, len(v.samplers))
+
+	var wg sync.WaitGroup
+	for i, provider := range v.samplers {
+		wg.Add(1)
+		go func(i int, provider ai.Provider) {
+			defer wg.Done()
+			results[i], errs[i] = callProvider(ctx, provider, p)
+		}(i, provider)
+	}
+	wg.Wait()
+
+	combined, err := ai.CombineSamples(results, errs, v.config.GetUnstableThreshold())
+	if err != nil {
+		return v.heuristicFallback(ctx, p, v.samplers[0].Name(), err)
+	}
+	return combined, nil
+}
+
+// sampleIdentity はキャッシュキーに使用する識別子を返す
+// サンプル数、temperature、不安定とする閾値も結果に影響するため含める
+func sampleIdentity(cfg *config.Config, providerName, providerModel string) (name, model string) {
+	temps := make([]string, cfg.GetSamples())
+	for i := range temps {
+		if t := cfg.GetSampleTemperature(i); t != nil {
+			temps[i] = fmt.Sprintf("%g", *t)
+		} else {
+			temps[i] = "default"
+		}
+	}
+	return providerName, fmt.Sprintf("%s;samples=%s;unstable=%g",
+		providerModel, strings.Join(temps, ","), cfg.GetUnstableThreshold())
+}
+
+// buildUnstableSpecs はサンプル間のばらつきが大きかったSPECを抽出する
+func buildUnstableSpecs(results []Result) []UnstableSpec {
+	var unstable []UnstableSpec
+	for _, result := range results {
+		if result.Error != nil || result.Verification == nil || result.Verification.Samples == nil {
+			continue
+		}
+		if s := result.Verification.Samples; s.Unstable {
+			unstable = append(unstable, UnstableSpec{
+				SpecFile:        result.SpecFile,
+				Title:           result.Title,
+				MatchPercentage: result.Verification.MatchPercentage,
+				Min:             s.Min,
+				Max:             s.Max,
+				StdDev:          s.StdDev,
+			})
+		}
+	}
+	return unstable
+}
@@ -0,0 +1,106 @@
+package verifier
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/k-totani/spec-verify/internal/ai"
+	"github.com/k-totani/spec-verify/internal/config"
+)
+
+// sequenceProvider は呼び出しごとに異なる一致度を返すテスト用プロバイダー
+type sequenceProvider struct {
+	stubProvider
+	scores chan int
+}
+
+func (p *sequenceProvider) VerifyWithOptions(ctx context.Context, specContent string, codeContents map[string]string, opts *ai.VerifyOptions) (*ai.VerificationResult, error) {
+	p.calls.Add(1)
+	return &ai.VerificationResult{MatchPercentage: <-p.scores}, nil
+}
+
+func (p *sequenceProvider) Verify(ctx context.Context, specContent string, codeContents map[string]string) (*ai.VerificationResult, error) {
+	return p.VerifyWithOptions(ctx, specContent, codeContents, nil)
+}
+
+func TestVerifyMultipleTypes_Samples(t *testing.T) {
+	cfg := writeTestProject(t)
+	disabled := false
+	cfg.Cache.Enabled = &disabled
+	cfg.Options.Samples = 3
+
+	provider := &sequenceProvider{scores: make(chan int, 3)}
+	for _, score := range []int{90, 50, 80} {
+		provider.scores <- score
+	}
+	v := &Verifier{config: cfg, provider: provider}
+	var err error
+	v.samplers, err = newSampleProviders(cfg, provider, rateLimiters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := v.VerifyMultipleTypes(context.Background(), []string{"ui"})
+	if err != nil {
+		t.Fatalf("VerifyMultipleTypes failed: %v", err)
+	}
+	if provider.calls.Load() != 3 {
+		t.Errorf("provider was called %d times, want 3", provider.calls.Load())
+	}
+
+	var login *Result
+	for i := range summary.Results {
+		if summary.Results[i].SpecFile == "login.md" {
+			login = &summary.Results[i]
+		}
+	}
+	if login == nil || login.Verification == nil || login.Verification.Samples == nil {
+		t.Fatalf("login.md was not sampled: %+v", summary.Results)
+	}
+	if login.Verification.MatchPercentage != 80 || login.Verification.LowerBound() != 50 {
+		t.Errorf("MatchPercentage = %d, LowerBound = %d", login.Verification.MatchPercentage, login.Verification.LowerBound())
+	}
+	if len(summary.UnstableSpecs) != 1 || summary.UnstableSpecs[0].Max != 90 {
+		t.Errorf("UnstableSpecs = %+v", summary.UnstableSpecs)
+	}
+}
+
+func TestNewSampleProviders(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AIProvider = "ollama"
+	provider := &stubProvider{}
+
+	providers, err := newSampleProviders(cfg, provider, rateLimiters{})
+	if err != nil || providers != nil {
+		t.Errorf("single sample = %v, %v; want nil, nil", providers, err)
+	}
+
+	cfg.Options.Samples = 2
+	providers, err = newSampleProviders(cfg, provider, rateLimiters{})
+	if err != nil || len(providers) != 2 || providers[1] != provider {
+		t.Errorf("samples without temperatures should reuse the provider: %v, %v", providers, err)
+	}
+
+	cfg.Options.SampleTemperatures = []float64{0, 0.7}
+	providers, err = newSampleProviders(cfg, provider, rateLimiters{})
+	if err != nil || len(providers) != 2 || providers[0] == provider {
+		t.Errorf("samples with temperatures should create providers: %v, %v", providers, err)
+	}
+
+	cfg.Ensemble.Members = []config.EnsembleMember{{Provider: "ollama"}, {Provider: "openai-compatible"}}
+	if _, err := newSampleProviders(cfg, provider, rateLimiters{}); err == nil {
+		t.Error("expected error when combined with ensemble")
+	}
+}
+
+func TestSampleIdentity(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Options.Samples = 3
+	cfg.Options.SampleTemperatures = []float64{0, 0.5}
+
+	_, model := sampleIdentity(cfg, "claude", "m")
+	if !strings.HasPrefix(model, "m;samples=0,0.5,0;") {
+		t.Errorf("model = %q", model)
+	}
+}
@@ -0,0 +1,209 @@
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/k-totani/spec-verify/internal/ai"
+)
+
+// SectionResult はSPECの1セクション（## 見出し）の検証結果
+type SectionResult struct {
+	// セクション名
+	Name string `json:"name"`
+
+	// 全体の一致度を計算する際の重み
+	Weight float64 `json:"weight"`
+
+	// 検証結果（スキップまたは失敗したセクションは nil）
+	Verification *ai.VerificationResult `json:"verification,omitempty"`
+
+	// キャッシュから取得した結果かどうか
+	Cached bool `json:"cached"`
+
+	// 予算の上限によりスキップされた理由
+	SkipReason string `json:"skipReason,omitempty"`
+
+	// 検証に失敗した場合のエラー
+	Error string `json:"error,omitempty"`
+}
+
+// SectionAverage はセクション単位の検証でのセクションごとの平均一致度
+type SectionAverage struct {
+	// セクション名
+	Name string `json:"name"`
+
+	// 平均一致度
+	AverageMatch float64 `json:"averageMatch"`
+
+	// そのセクションを含むSPEC数
+	Specs int `json:"specs"`
+}
+
+// sectionSpec はセクション単位で検証するために準備したSPEC
+type sectionSpec struct {
+	name     string
+	weight   float64
+	prepared *preparedSpec
+}
+
+// sectionSpecs はSPECを検証対象のセクションごとに分割する
+// 各セクションはSPECのタイトルとそのセクションのみを含む文書としてAIに送信する
+// 重みが0のセクションと内容が空のセクションは除く
+func (v *Verifier) sectionSpecs(p *preparedSpec) ([]sectionSpec, error) {
+	var sections []sectionSpec
+	for _, name := range p.spec.SectionNames {
+		weight := v.config.GetSectionWeight(name)
+		content := p.spec.Sections[name]
+		if weight == 0 || content == "" {
+			continue
+		}
+		doc := fmt.Sprintf("# %s\n\n## %s\n\n%s\n", p.spec.Title, name, content)
+		prompt, err := ai.BuildVerificationPrompt(doc, p.codeContents, p.opts)
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, sectionSpec{
+			name:   name,
+			weight: weight,
+			prepared: &preparedSpec{
+				spec:         p.spec,
+				content:      doc,
+				codeContents: p.codeContents,
+				opts:         p.opts,
+				prompt:       prompt,
+				inputBytes:   p.inputBytes,
+			},
+		})
+	}
+	return sections, nil
+}
+
+// verifySections はSPECをセクションごとに検証し、重み付き平均で全体の結果を求める
+// キャッシュと予算はセクションごとに適用する。検証対象のセクションがない場合はSPEC全体を検証する
+// セクションがスキップまたは失敗した場合は残りのセクションを検証せず、SPEC全体をスキップまたはエラーとする
+// それまでに検証したセクションの結果と使用量は、失敗したセクションとともに結果に残す
+func (v *Verifier) verifySections(ctx context.Context, prepared *preparedSpec, result *Result) {
+	sections, err := v.sectionSpecs(prepared)
+	if err != nil {
+		result.Error = err
+		return
+	}
+	if len(sections) == 0 {
+		v.verifyPrepared(ctx, prepared, result)
+		return
+	}
+
+	cached := true
+	var usage []UsageRecord
+	results := make([]SectionResult, 0, len(sections))
+	defer func() {
+		result.Sections = results
+		result.Usage = mergeUsageRecords(usage)
+	}()
+	for _, section := range sections {
+		var sectionResult Result
+		v.verifyPrepared(ctx, section.prepared, &sectionResult)
+		usage = append(usage, sectionResult.Usage...)
+		current := SectionResult{Name: section.name, Weight: section.weight}
+		if sectionResult.Skipped {
+			current.SkipReason = sectionResult.SkipReason
+			results = append(results, current)
+			result.Skipped = true
+			result.SkipReason = sectionResult.SkipReason
+			return
+		}
+		if sectionResult.Error != nil {
+			current.Error = sectionResult.Error.Error()
+			results = append(results, current)
+			result.Error = fmt.Errorf("section %q: %w", section.name, sectionResult.Error)
+			return
+		}
+		cached = cached && sectionResult.Cached
+		current.Verification = sectionResult.Verification
+		current.Cached = sectionResult.Cached
+		results = append(results, current)
+	}
+
+	result.Verification = combineSections(results)
+	result.Cached = cached
+}
+
+// combineSections はセクションごとの結果を1つの検証結果に統合する
+// 一致度は重み付き平均、項目と補足コメントはセクション名を付けて連結する
+func combineSections(sections []SectionResult) *ai.VerificationResult {
+	combined := &ai.VerificationResult{
+		MatchedItems:   []string{},
+		UnmatchedItems: []string{},
+	}
+
+	var weighted, totalWeight float64
+	var usage ai.Usage
+	hasUsage := false
+	var notes []string
+	for _, s := range sections {
+		r := s.Verification
+		weighted += s.Weight * float64(r.MatchPercentage)
+		totalWeight += s.Weight
+
+		for _, item := range r.MatchedItems {
+			combined.MatchedItems = append(combined.MatchedItems, fmt.Sprintf("[%s] %s", s.Name, item))
+		}
+		for _, item := range r.UnmatchedItems {
+			combined.UnmatchedItems = append(combined.UnmatchedItems, fmt.Sprintf("[%s] %s", s.Name, item))
+		}
+		if r.Notes != "" {
+			notes = append(notes, fmt.Sprintf("[%s] %s", s.Name, r.Notes))
+		}
+
+		combined.Retries += r.Retries
+		combined.Attempts += r.Attempts
+		combined.LatencyMs += r.LatencyMs
+		if r.Usage != nil {
+			usage.Add(*r.Usage)
+			hasUsage = true
+		}
+		if r.Fallback != "" {
+			combined.Fallback = r.Fallback
+		}
+		if r.Provider != "" {
+			combined.Provider, combined.Model = r.Provider, r.Model
+		}
+	}
+
+	if totalWeight > 0 {
+		combined.MatchPercentage = int(math.Round(weighted / totalWeight))
+	}
+	combined.Notes = strings.Join(notes, "\n")
+	if hasUsage {
+		combined.Usage = &usage
+	}
+	return combined
+}
+
+// buildSectionAverages はセクションごとの平均一致度をSPECに現れた順で集計する
+func buildSectionAverages(results []Result) []SectionAverage {
+	var averages []SectionAverage
+	index := map[string]int{}
+	for _, result := range results {
+		if result.Error != nil || result.Skipped {
+			continue
+		}
+		for _, s := range result.Sections {
+			i, ok := index[s.Name]
+			if !ok {
+				i = len(averages)
+				index[s.Name] = i
+				averages = append(averages, SectionAverage{Name: s.Name})
+			}
+			averages[i].AverageMatch += float64(s.Verification.MatchPercentage)
+			averages[i].Specs++
+		}
+	}
+	for i := range averages {
+		averages[i].AverageMatch /= float64(averages[i].Specs)
+	}
+	return averages
+}
@@ -0,0 +1,180 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/k-totani/spec-verify/internal/ai"
+	"github.com/k-totani/spec-verify/internal/cache"
+)
+
+// sectionProvider はSPECに含まれるセクションに応じた一致度を返すテスト用プロバイダー
+type sectionProvider struct {
+	stubProvider
+	scores map[string]int
+
+	// 呼び出しに失敗するセクション
+	failing string
+}
+
+func (p *sectionProvider) VerifyWithOptions(ctx context.Context, specContent string, codeContents map[string]string, opts *ai.VerifyOptions) (*ai.VerificationResult, error) {
+	p.calls.Add(1)
+	if p.failing != "" && strings.Contains(specContent, "## "+p.failing) {
+		return nil, errors.New("API error (status 503)")
+	}
+	for section, score := range p.scores {
+		if strings.Contains(specContent, "## "+section) {
+			return &ai.VerificationResult{
+				MatchPercentage: score,
+				MatchedItems:    []string{section + "の項目"},
+				UnmatchedItems:  []string{},
+				Usage:           &ai.Usage{InputTokens: 100, OutputTokens: 10},
+				Attempts:        1,
+			}, nil
+		}
+	}
+	return &ai.VerificationResult{MatchPercentage: 0}, nil
+}
+
+func (p *sectionProvider) Verify(ctx context.Context, specContent string, codeContents map[string]string) (*ai.VerificationResult, error) {
+	return p.VerifyWithOptions(ctx, specContent, codeContents, nil)
+}
+
+const sectionedSpec = "# ログイン画面\n\n" +
+	"## 画面構成\n\n- ログインボタン\n\n" +
+	"## バリデーション\n\n- メールアドレスは必須\n\n" +
+	"## 関連ファイル\n- `~/ui/Login.tsx`\n"
+
+func TestVerifyMultipleTypes_Sections(t *testing.T) {
+	cfg := writeTestProject(t)
+	if err := os.WriteFile(filepath.Join(cfg.SpecsDir, "ui", "login.md"), []byte(sectionedSpec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Sections.Enabled = true
+	cfg.Sections.Weights = map[string]float64{"バリデーション": 3}
+
+	provider := &sectionProvider{scores: map[string]int{"画面構成": 95, "バリデーション": 40}}
+	v := &Verifier{config: cfg, provider: provider, cache: cache.New(cfg.Cache.Dir)}
+
+	summary, err := v.VerifyMultipleTypes(context.Background(), []string{"ui"})
+	if err != nil {
+		t.Fatalf("VerifyMultipleTypes failed: %v", err)
+	}
+	login := findResult(summary, "login.md")
+	if login == nil || len(login.Sections) != 2 {
+		t.Fatalf("login.md sections = %+v", login)
+	}
+	// 関連ファイルは検証しない
+	if provider.calls.Load() != 2 {
+		t.Errorf("provider was called %d times, want 2", provider.calls.Load())
+	}
+	if login.Sections[0].Name != "画面構成" || login.Sections[1].Name != "バリデーション" || login.Sections[1].Weight != 3 {
+		t.Errorf("sections = %+v", login.Sections)
+	}
+	// (95*1 + 40*3) / 4 = 53.75
+	if login.Verification.MatchPercentage != 54 {
+		t.Errorf("MatchPercentage = %d, want 54", login.Verification.MatchPercentage)
+	}
+	if got := login.Verification.MatchedItems; len(got) != 2 || got[0] != "[画面構成] 画面構成の項目" {
+		t.Errorf("MatchedItems = %v", got)
+	}
+	if login.Verification.Usage == nil || login.Verification.Usage.InputTokens != 200 {
+		t.Errorf("Usage = %+v, want the total of all sections", login.Verification.Usage)
+	}
+	if len(summary.SectionAverages) != 2 || summary.SectionAverages[1].AverageMatch != 40 {
+		t.Errorf("SectionAverages = %+v", summary.SectionAverages)
+	}
+
+	// セクションごとにキャッシュされるため、2回目はAPIを呼び出さない
+	summary, err = v.VerifyMultipleTypes(context.Background(), []string{"ui"})
+	if err != nil {
+		t.Fatalf("VerifyMultipleTypes failed: %v", err)
+	}
+	if login := findResult(summary, "login.md"); login == nil || !login.Cached || login.Verification.MatchPercentage != 54 {
+		t.Errorf("second run = %+v", login)
+	}
+	if provider.calls.Load() != 2 {
+		t.Errorf("provider was called %d times after the second run, want 2", provider.calls.Load())
+	}
+}
+
+func TestVerifySections_NoSections(t *testing.T) {
+	cfg := writeTestProject(t)
+	disabled := false
+	cfg.Cache.Enabled = &disabled
+	cfg.Sections.Enabled = true
+
+	// login.md には関連ファイルのセクションしかないため、SPEC全体を検証する
+	provider := &stubProvider{}
+	v := &Verifier{config: cfg, provider: provider}
+	summary, err := v.VerifyMultipleTypes(context.Background(), []string{"ui"})
+	if err != nil {
+		t.Fatalf("VerifyMultipleTypes failed: %v", err)
+	}
+	login := findResult(summary, "login.md")
+	if login == nil || login.Sections != nil || login.Verification.MatchPercentage != 90 {
+		t.Errorf("login.md = %+v", login)
+	}
+}
+
+func TestEstimateMultipleTypes_Sections(t *testing.T) {
+	cfg := writeTestProject(t)
+	if err := os.WriteFile(filepath.Join(cfg.SpecsDir, "ui", "login.md"), []byte(sectionedSpec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Sections.Enabled = true
+
+	estimate, err := EstimateMultipleTypes(cfg, []string{"ui"})
+	if err != nil {
+		t.Fatalf("EstimateMultipleTypes failed: %v", err)
+	}
+	if estimate.Requests != 2 {
+		t.Errorf("Requests = %d, want 2 (one per section)", estimate.Requests)
+	}
+}
+
+// findResult はファイル名でSPECの結果を探す
+func findResult(summary *Summary, specFile string) *Result {
+	for i := range summary.Results {
+		if summary.Results[i].SpecFile == specFile {
+			return &summary.Results[i]
+		}
+	}
+	return nil
+}
+
+func TestVerifySections_Failure(t *testing.T) {
+	cfg := writeTestProject(t)
+	if err := os.WriteFile(filepath.Join(cfg.SpecsDir, "ui", "login.md"), []byte(sectionedSpec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	disabled := false
+	cfg.Cache.Enabled = &disabled
+	cfg.Sections.Enabled = true
+
+	provider := &sectionProvider{scores: map[string]int{"画面構成": 95}, failing: "バリデーション"}
+	v := &Verifier{config: cfg, provider: provider}
+	summary, err := v.VerifyMultipleTypes(context.Background(), []string{"ui"})
+	if err != nil {
+		t.Fatalf("VerifyMultipleTypes failed: %v", err)
+	}
+
+	// 失敗したセクションがあってもそれまでのセクションの結果と使用量は残す
+	login := findResult(summary, "login.md")
+	if login == nil || login.Error == nil || !strings.Contains(login.Error.Error(), "バリデーション") {
+		t.Fatalf("login.md = %+v, want a section error", login)
+	}
+	if len(login.Sections) != 2 || login.Sections[0].Verification == nil || login.Sections[1].Error == "" {
+		t.Errorf("sections = %+v, want the completed and the failed section", login.Sections)
+	}
+	if len(login.Usage) != 1 || login.Usage[0].InputTokens != 100 {
+		t.Errorf("Usage = %+v, want the usage of the completed section", login.Usage)
+	}
+	if len(summary.UsageByModel) != 1 || len(summary.SectionAverages) != 0 {
+		t.Errorf("UsageByModel = %+v, SectionAverages = %+v", summary.UsageByModel, summary.SectionAverages)
+	}
+}
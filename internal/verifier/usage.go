@@ -0,0 +1,181 @@
+package verifier
+
+import (
+	"sort"
+
+	"github.com/k-totani/spec-verify/internal/ai"
+)
+
+// UsageRecord は1つのSPECの検証で1つのプロバイダー/モデルが使用したAPIの量
+type UsageRecord struct {
+	// プロバイダー名
+	Provider string `json:"provider"`
+
+	// モデル名
+	Model string `json:"model"`
+
+	// 入力トークン数
+	InputTokens int `json:"inputTokens"`
+
+	// 出力トークン数
+	OutputTokens int `json:"outputTokens"`
+
+	// 検証にかかった時間（ミリ秒）
+	LatencyMs int64 `json:"latencyMs"`
+
+	// API呼び出しの回数（再試行、再質問、分割したチャンクの呼び出しを含む）
+	Attempts int `json:"attempts"`
+}
+
+// add は使用量を加算する
+func (r *UsageRecord) add(other UsageRecord) {
+	r.InputTokens += other.InputTokens
+	r.OutputTokens += other.OutputTokens
+	r.LatencyMs += other.LatencyMs
+	r.Attempts += other.Attempts
+}
+
+// UsageTotal は複数のSPECの使用量をプロバイダー/モデルまたはSPECタイプごとに合計したもの
+type UsageTotal struct {
+	// プロバイダー名とモデル名（プロバイダー/モデル別の集計のみ）
+	Provider string `json:"provider,omitempty"`
+	Model    string `json:"model,omitempty"`
+
+	// SPECタイプ（SPECタイプ別の集計のみ）
+	SpecType string `json:"specType,omitempty"`
+
+	// APIを呼び出して検証したSPEC数
+	Specs int `json:"specs"`
+
+	// 入力トークン数
+	InputTokens int `json:"inputTokens"`
+
+	// 出力トークン数
+	OutputTokens int `json:"outputTokens"`
+
+	// 検証にかかった時間の合計（ミリ秒）
+	LatencyMs int64 `json:"latencyMs"`
+
+	// API呼び出しの回数
+	Attempts int `json:"attempts"`
+}
+
+// add は1つのSPECの使用量を加算する
+func (t *UsageTotal) add(r UsageRecord) {
+	t.InputTokens += r.InputTokens
+	t.OutputTokens += r.OutputTokens
+	t.LatencyMs += r.LatencyMs
+	t.Attempts += r.Attempts
+}
+
+// usageRecords は検証結果からプロバイダー/モデルごとの使用量を取り出す
+// アンサンブル検証ではメンバーごと、それ以外では結果を返したプロバイダーの使用量を返す
+// APIを呼び出していない結果（キャッシュ、カセットの再生、ヒューリスティック検証）では nil を返す
+func usageRecords(verification *ai.VerificationResult) []UsageRecord {
+	if verification == nil {
+		return nil
+	}
+	if c := verification.Consensus; c != nil {
+		var records []UsageRecord
+		for _, m := range c.Members {
+			if m.Attempts > 0 {
+				records = append(records, newUsageRecord(m.Provider, m.Model, m.Usage, m.LatencyMs, m.Attempts))
+			}
+		}
+		return records
+	}
+	if verification.Attempts == 0 {
+		return nil
+	}
+	return []UsageRecord{newUsageRecord(verification.Provider, verification.Model,
+		verification.Usage, verification.LatencyMs, verification.Attempts)}
+}
+
+// newUsageRecord は使用量のレコードを作成する（usage が nil の場合はトークン数を0とする）
+func newUsageRecord(provider, model string, usage *ai.Usage, latencyMs int64, attempts int) UsageRecord {
+	record := UsageRecord{Provider: provider, Model: model, LatencyMs: latencyMs, Attempts: attempts}
+	if usage != nil {
+		record.InputTokens = usage.InputTokens
+		record.OutputTokens = usage.OutputTokens
+	}
+	return record
+}
+
+// mergeUsageRecords は同じプロバイダー/モデルのレコードを1つにまとめる（最初に現れた順）
+func mergeUsageRecords(records []UsageRecord) []UsageRecord {
+	var merged []UsageRecord
+	index := make(map[[2]string]int)
+	for _, r := range records {
+		key := [2]string{r.Provider, r.Model}
+		if i, ok := index[key]; ok {
+			merged[i].add(r)
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// buildUsageByModel はプロバイダー/モデルごとの使用量を集計する（プロバイダー名、モデル名の順）
+func buildUsageByModel(results []Result) []UsageTotal {
+	totals := make(map[[2]string]*UsageTotal)
+	for _, result := range results {
+		for _, r := range result.Usage {
+			key := [2]string{r.Provider, r.Model}
+			total, ok := totals[key]
+			if !ok {
+				total = &UsageTotal{Provider: r.Provider, Model: r.Model}
+				totals[key] = total
+			}
+			total.Specs++
+			total.add(r)
+		}
+	}
+
+	byModel := make([]UsageTotal, 0, len(totals))
+	for _, total := range totals {
+		byModel = append(byModel, *total)
+	}
+	sort.Slice(byModel, func(i, j int) bool {
+		if byModel[i].Provider != byModel[j].Provider {
+			return byModel[i].Provider < byModel[j].Provider
+		}
+		return byModel[i].Model < byModel[j].Model
+	})
+	if len(byModel) == 0 {
+		return nil
+	}
+	return byModel
+}
+
+// buildUsageBySpecType はSPECタイプごとの使用量を集計する（SPECタイプ名の順）
+func buildUsageBySpecType(results []Result) []UsageTotal {
+	totals := make(map[string]*UsageTotal)
+	for _, result := range results {
+		if len(result.Usage) == 0 {
+			continue
+		}
+		total, ok := totals[result.SpecType]
+		if !ok {
+			total = &UsageTotal{SpecType: result.SpecType}
+			totals[result.SpecType] = total
+		}
+		total.Specs++
+		for _, r := range result.Usage {
+			total.add(r)
+		}
+	}
+
+	byType := make([]UsageTotal, 0, len(totals))
+	for _, total := range totals {
+		byType = append(byType, *total)
+	}
+	sort.Slice(byType, func(i, j int) bool {
+		return byType[i].SpecType < byType[j].SpecType
+	})
+	if len(byType) == 0 {
+		return nil
+	}
+	return byType
+}
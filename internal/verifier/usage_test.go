@@ -0,0 +1,117 @@
+package verifier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/k-totani/spec-verify/internal/ai"
+	"github.com/k-totani/spec-verify/internal/cache"
+)
+
+// accountingProvider は使用量、所要時間、呼び出し回数を設定した結果を返すテスト用プロバイダー
+type accountingProvider struct {
+	stubProvider
+}
+
+func (p *accountingProvider) VerifyWithOptions(ctx context.Context, specContent string, codeContents map[string]string, opts *ai.VerifyOptions) (*ai.VerificationResult, error) {
+	p.calls.Add(1)
+	return &ai.VerificationResult{
+		MatchPercentage: 80,
+		Usage:           &ai.Usage{InputTokens: 1000, OutputTokens: 200},
+		Provider:        p.Name(),
+		Model:           p.Model(),
+		LatencyMs:       1500,
+		Attempts:        2,
+	}, nil
+}
+
+func (p *accountingProvider) Verify(ctx context.Context, specContent string, codeContents map[string]string) (*ai.VerificationResult, error) {
+	return p.VerifyWithOptions(ctx, specContent, codeContents, nil)
+}
+
+func TestVerifyMultipleTypes_Usage(t *testing.T) {
+	cfg := writeTestProject(t)
+	v := &Verifier{config: cfg, provider: &accountingProvider{}, cache: cache.New(cfg.Cache.Dir)}
+
+	summary, err := v.VerifyMultipleTypes(context.Background(), []string{"ui"})
+	if err != nil {
+		t.Fatalf("VerifyMultipleTypes failed: %v", err)
+	}
+	login := findResult(summary, "login.md")
+	want := UsageRecord{Provider: "claude", Model: "claude-sonnet-4-20250514", InputTokens: 1000, OutputTokens: 200, LatencyMs: 1500, Attempts: 2}
+	if login == nil || login.SpecType != "ui" || len(login.Usage) != 1 || login.Usage[0] != want {
+		t.Fatalf("login.md = %+v", login)
+	}
+	// コードがないSPECはAPIを呼び出さないため使用量に含めない
+	if empty := findResult(summary, "empty.md"); empty == nil || empty.Usage != nil {
+		t.Errorf("empty.md = %+v", empty)
+	}
+	if len(summary.UsageByModel) != 1 || summary.UsageByModel[0].Specs != 1 || summary.UsageByModel[0].InputTokens != 1000 {
+		t.Errorf("UsageByModel = %+v", summary.UsageByModel)
+	}
+	if len(summary.UsageBySpecType) != 1 || summary.UsageBySpecType[0].SpecType != "ui" || summary.UsageBySpecType[0].Attempts != 2 {
+		t.Errorf("UsageBySpecType = %+v", summary.UsageBySpecType)
+	}
+
+	// キャッシュから取得した結果はAPIを呼び出していないため集計しない
+	summary, err = v.VerifyMultipleTypes(context.Background(), []string{"ui"})
+	if err != nil {
+		t.Fatalf("VerifyMultipleTypes failed: %v", err)
+	}
+	login = findResult(summary, "login.md")
+	if login == nil || !login.Cached || login.Usage != nil || login.Verification.Attempts != 0 || login.Verification.LatencyMs != 0 {
+		t.Errorf("cached login.md = %+v", login)
+	}
+	if summary.UsageByModel != nil || summary.UsageBySpecType != nil {
+		t.Errorf("usage of cached results = %+v / %+v", summary.UsageByModel, summary.UsageBySpecType)
+	}
+}
+
+func TestUsageRecords_Ensemble(t *testing.T) {
+	verification := &ai.VerificationResult{
+		Usage:    &ai.Usage{InputTokens: 300, OutputTokens: 30},
+		Attempts: 3,
+		Consensus: &ai.Consensus{Members: []ai.MemberResult{
+			{Provider: "claude", Model: "sonnet", Usage: &ai.Usage{InputTokens: 100, OutputTokens: 10}, LatencyMs: 900, Attempts: 1},
+			{Provider: "openai", Model: "gpt-4o", Usage: &ai.Usage{InputTokens: 200, OutputTokens: 20}, LatencyMs: 1200, Attempts: 2},
+			{Provider: "gemini", Model: "flash", Error: "status 503"},
+		}},
+	}
+
+	records := usageRecords(verification)
+	if len(records) != 2 || records[0].Provider != "claude" || records[1].InputTokens != 200 || records[1].Attempts != 2 {
+		t.Errorf("records = %+v, want one record per member that called the API", records)
+	}
+}
+
+func TestBuildUsageTotals(t *testing.T) {
+	results := []Result{
+		{SpecType: "api", Usage: []UsageRecord{{Provider: "openai", Model: "gpt-4o", InputTokens: 100, OutputTokens: 10, LatencyMs: 500, Attempts: 1}}},
+		{SpecType: "ui", Usage: []UsageRecord{
+			{Provider: "claude", Model: "sonnet", InputTokens: 200, OutputTokens: 20, LatencyMs: 700, Attempts: 1},
+			{Provider: "openai", Model: "gpt-4o", InputTokens: 300, OutputTokens: 30, LatencyMs: 800, Attempts: 2},
+		}},
+		{SpecType: "ui", Cached: true},
+	}
+
+	byModel := buildUsageByModel(results)
+	if len(byModel) != 2 || byModel[0].Provider != "claude" {
+		t.Fatalf("byModel = %+v, want claude and openai in order", byModel)
+	}
+	if got := byModel[1]; got.Specs != 2 || got.InputTokens != 400 || got.OutputTokens != 40 || got.LatencyMs != 1300 || got.Attempts != 3 {
+		t.Errorf("openai total = %+v", got)
+	}
+
+	byType := buildUsageBySpecType(results)
+	if len(byType) != 2 || byType[0].SpecType != "api" {
+		t.Fatalf("byType = %+v, want api and ui in order", byType)
+	}
+	if got := byType[1]; got.Specs != 1 || got.InputTokens != 500 || got.Attempts != 3 {
+		t.Errorf("ui total = %+v", got)
+	}
+
+	merged := mergeUsageRecords(append(results[1].Usage, results[0].Usage...))
+	if len(merged) != 2 || merged[1].InputTokens != 400 || merged[1].Attempts != 3 {
+		t.Errorf("merged = %+v", merged)
+	}
+}
@@ -3,12 +3,18 @@ package verifier
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"os"
 	"path/filepath"
 	"sync"
 
 	"github.com/k-totani/spec-verify/internal/ai"
+	"github.com/k-totani/spec-verify/internal/cache"
 	"github.com/k-totani/spec-verify/internal/config"
+	"github.com/k-totani/spec-verify/internal/egress"
+	"github.com/k-totani/spec-verify/internal/i18n"
 	"github.com/k-totani/spec-verify/internal/parser"
+	"github.com/k-totani/spec-verify/internal/redact"
 )
 
 // Result は単一のSPEC検証結果
@@ -19,15 +25,39 @@ type Result struct {
 	// SPECのタイトル
 	Title string
 
+	// SPECタイプ（ui, api など）
+	SpecType string
+
 	// ルートパス
 	RoutePath string
 
 	// 見つかったコードファイル
 	CodeFiles []string
 
+	// AIに送信する前にコードから取り除いた機密情報の件数（キーは検出ルール名）
+	Redactions map[string]int
+
+	// egress の設定で送信を禁止されたため除外した関連コードファイル
+	DroppedFiles []string
+
 	// 検証結果
 	Verification *ai.VerificationResult
 
+	// キャッシュから取得した結果かどうか（セクション単位の検証では全セクションがキャッシュの場合）
+	Cached bool
+
+	// プロバイダー/モデルごとのAPIの使用量（APIを呼び出していない場合は空）
+	Usage []UsageRecord
+
+	// セクションごとの検証結果（sections.enabled の場合のみ）
+	Sections []SectionResult
+
+	// 予算の上限によりスキップされたかどうか
+	Skipped bool
+
+	// スキップされた理由
+	SkipReason string
+
 	// エラー（検証に失敗した場合）
 	Error error
 }
@@ -61,12 +91,42 @@ type Summary struct {
 	// 低一致数（50%未満）
 	LowMatchCount int
 
+	// キャッシュから取得した検証数
+	CachedSpecs int
+
+	// アンサンブル検証でメンバー間の評価が割れ、レビューが必要なSPEC一覧
+	ReviewSpecs []ReviewSpec `json:"reviewSpecs,omitempty"`
+
+	// 自己一貫性チェックでサンプル間のばらつきが大きかったSPEC一覧
+	UnstableSpecs []UnstableSpec `json:"unstableSpecs,omitempty"`
+
+	// セクション単位の検証でのセクションごとの平均一致度
+	SectionAverages []SectionAverage `json:"sectionAverages,omitempty"`
+
+	// 予算の上限によりスキップされたSPEC数
+	SkippedSpecs int
+
+	// AIに送信する前にコードから取り除いた機密情報の件数（全SPECの合計）
+	Redactions int `json:"redactions,omitempty"`
+
+	// egress の設定で送信を禁止されたため除外したファイル数（全SPECの合計）
+	DroppedFiles int `json:"droppedFiles,omitempty"`
+
+	// プロバイダー/モデルごとのAPIの使用量
+	UsageByModel []UsageTotal `json:"usageByModel,omitempty"`
+
+	// SPECタイプごとのAPIの使用量
+	UsageBySpecType []UsageTotal `json:"usageBySpecType,omitempty"`
+
 	// 個別結果
 	Results []Result
 
 	// 個別閾値（この値未満は失敗）
 	FailUnder int `json:"failUnder,omitempty"`
 
+	// 個別閾値の判定に使用した一致度（median または lower_bound）
+	FailUnderMode string `json:"failUnderMode,omitempty"`
+
 	// 個別閾値を下回ったSPEC一覧
 	FailingSpecs []FailingSpec `json:"failingSpecs,omitempty"`
 }
@@ -75,21 +135,285 @@ type Summary struct {
 type Verifier struct {
 	config   *config.Config
 	provider ai.Provider
+
+	// 検証結果キャッシュ（nilの場合は使用しない）
+	cache *cache.Cache
+
+	// キャッシュを読まずに検証し直し、結果で上書きする
+	refreshCache bool
+
+	// 実行あたりの使用量の上限（nilの場合は制限しない）
+	budget *budget
+
+	// プロバイダーごとのレートリミッター（主プロバイダー、アンサンブルのメンバー、フォールバック先で共有する）
+	limiters rateLimiters
+
+	// アンサンブル検証のメンバー（空の場合は provider のみで検証する）
+	ensemble []ai.Provider
+
+	// 自己一貫性チェックの各サンプルを実行するプロバイダー（空の場合は provider で1回のみ検証する）
+	samplers []ai.Provider
+
+	// アンサンブル検証や自己一貫性チェックが全て失敗した場合の代替（nilの場合は代替しない）
+	heuristic *ai.HeuristicProvider
+
+	// 設定されたプロンプトテンプレート（ない場合は組み込みのテンプレート）
+	templates promptTemplates
+
+	// AIに送信するコードから機密情報を取り除く（nilの場合は取り除かない）
+	redactor *redact.Redactor
+
+	// AIに送信してよいファイルの制限（nilの場合は制限しない）
+	egress *egress.Policy
 }
 
-// New は新しいVerifierを作成する
-func New(cfg *config.Config) (*Verifier, error) {
-	provider, err := ai.NewProvider(cfg.AIProvider, cfg.AIAPIKey)
+// Option はVerifier作成時のオプション
+type Option func(*Verifier)
+
+// WithRefreshCache はキャッシュを読まずに検証し、結果でキャッシュを更新するオプション
+func WithRefreshCache(refresh bool) Option {
+	return func(v *Verifier) {
+		v.refreshCache = refresh
+	}
+}
+
+// NewProvider は設定に基づいてAIプロバイダーを作成する
+func NewProvider(cfg *config.Config) (ai.Provider, error) {
+	return newProvider(cfg, rateLimiters{})
+}
+
+// rateLimiters はプロバイダーの正式名ごとのレートリミッター
+// 同じプロバイダーを使用する全てのプロバイダー（アンサンブルのメンバー、フォールバック先、サンプル）で1つのリミッターを共有し、
+// 合計の送信ペースを rate_limits の値に保つ
+type rateLimiters map[string]*ai.RateLimiter
+
+// get はプロバイダーのレートリミッターを返す（制限がない場合はnil）
+// 初回の呼び出しで作成する。プロバイダーの作成時にのみ呼び出すため排他制御はしない
+func (l rateLimiters) get(cfg *config.Config, provider string) *ai.RateLimiter {
+	name := ai.CanonicalProviderName(provider)
+	if limiter, ok := l[name]; ok {
+		return limiter
+	}
+	rateLimit := rateLimitFor(cfg, provider)
+	limiter := ai.NewRateLimiter(ai.RateLimit{
+		RequestsPerMinute:    rateLimit.RequestsPerMinute,
+		InputTokensPerMinute: rateLimit.InputTokensPerMinute,
+	})
+	l[name] = limiter
+	return limiter
+}
+
+// rateLimitFor はプロバイダーのレート制限設定を返す
+// ai_provider にエイリアス（anthropic, gpt, google など）を指定した場合も、正式名のキーの設定を使用する
+func rateLimitFor(cfg *config.Config, provider string) config.RateLimit {
+	if rateLimit, ok := cfg.AI.RateLimits[provider]; ok {
+		return rateLimit
+	}
+	return cfg.GetRateLimit(ai.CanonicalProviderName(provider))
+}
+
+// newProvider は limiters のレートリミッターを使用するAIプロバイダーを作成する
+// レートリミッターはプロバイダーごとに1つ作成し、全ての検証/抽出goroutineで共有する
+// ai.heuristic_fallback が有効な場合は、失敗時にヒューリスティック検証で代替する
+func newProvider(cfg *config.Config, limiters rateLimiters) (ai.Provider, error) {
+	provider, err := newChainProvider(cfg, limiters)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create AI provider: %w", err)
+		return nil, err
+	}
+	return withHeuristicFallback(cfg, provider), nil
+}
+
+// withHeuristicFallback は ai.heuristic_fallback が有効な場合に provider をヒューリスティック検証による代替で包む
+// アンサンブルのメンバーや自己一貫性チェックのサンプルは包まず、統合した結果に対してのみ代替する
+func withHeuristicFallback(cfg *config.Config, provider ai.Provider) ai.Provider {
+	if cfg.AI.HeuristicFallback && provider.Name() != ai.HeuristicProviderName {
+		return ai.NewHeuristicFallback(provider, ai.WithLanguage(cfg.Language))
+	}
+	return provider
+}
+
+// newChainProvider は ai_provider に指定したプロバイダーを順に試すプロバイダーを作成する
+// フォールバック先のプロバイダーはアンサンブルのメンバーと同様に、モデルとベースURLにデフォルトを使用する
+func newChainProvider(cfg *config.Config, limiters rateLimiters) (ai.Provider, error) {
+	primary, err := newRecordingProvider(cfg, limiters)
+	if err != nil || len(cfg.AIProviderFallbacks) == 0 {
+		return primary, err
+	}
+
+	providers := []ai.Provider{primary}
+	for _, name := range cfg.AIProviderFallbacks {
+		fallbackCfg := memberConfig(cfg, config.EnsembleMember{Provider: name})
+		p, err := newRecordingProvider(fallbackCfg, limiters)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create fallback provider %s: %w", name, err)
+		}
+		providers = append(providers, p)
+	}
+	return ai.NewChainProvider(providers...)
+}
+
+// newRecordingProvider はカセットの設定に応じたプロバイダーを作成する
+// ai.cassette が指定されている場合は、replay ではカセットから再生し、それ以外では応答を記録する
+func newRecordingProvider(cfg *config.Config, limiters rateLimiters) (ai.Provider, error) {
+	if ai.CanonicalProviderName(cfg.AIProvider) == ai.ReplayProviderName {
+		return newReplayProvider(cfg, limiters)
+	}
+
+	provider, err := newAPIProvider(cfg, limiters)
+	if err != nil || cfg.AI.Cassette == "" {
+		return provider, err
+	}
+	cassette, err := ai.OpenCassette(cfg.AI.Cassette)
+	if err != nil {
+		return nil, err
 	}
+	return ai.NewRecordingProvider(provider, cassette), nil
+}
+
+// newReplayProvider はカセットから応答を再生するプロバイダーを作成する
+// ai.replay_fallback が指定されている場合は、記録がないリクエストをそのプロバイダーで処理してカセットに追記する
+func newReplayProvider(cfg *config.Config, limiters rateLimiters) (ai.Provider, error) {
+	if cfg.AI.Cassette == "" {
+		return nil, fmt.Errorf("replay provider requires a cassette (--cassette or ai.cassette)")
+	}
+	cassette, err := ai.LoadCassette(cfg.AI.Cassette)
+	if err != nil {
+		return nil, err
+	}
+
+	var fallback ai.Provider
+	if cfg.AI.ReplayFallback != "" {
+		fallbackCfg := *cfg
+		fallbackCfg.AIProvider = cfg.AI.ReplayFallback
+		fallbackCfg.AIProviderFallbacks = nil
+		if fallbackCfg.AIAPIKey == "" {
+			fallbackCfg.AIAPIKey = config.GetAPIKeyFromEnv(cfg.AI.ReplayFallback)
+		}
+		fallback, err = newChainProvider(&fallbackCfg, limiters)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create replay fallback provider: %w", err)
+		}
+	}
+	return ai.NewReplayProvider(cassette, fallback), nil
+}
 
+// newHTTPClient は ai.http の設定に基づいたHTTPクライアントを返す
+// 同じ設定のプロバイダー（アンサンブルのメンバーやフォールバック先を含む）は同じクライアントを共有する
+func newHTTPClient(cfg *config.Config) (*http.Client, error) {
+	return ai.SharedHTTPClient(ai.HTTPSettings{
+		ConnectTimeout:  cfg.AI.HTTP.ConnectTimeout,
+		ResponseTimeout: cfg.AI.HTTP.ResponseTimeout,
+		Proxy:           cfg.AI.HTTP.Proxy,
+		CABundle:        cfg.AI.HTTP.CABundle,
+		Headers:         cfg.GetHTTPHeaders(),
+	})
+}
+
+// newAPIProvider はAPIを呼び出すAIプロバイダーを作成する
+// egress.audit_log が指定されている場合は、送信する内容を監査ログに記録してから送信する
+// （ヒューリスティック検証は外部に送信しないため記録しない）
+func newAPIProvider(cfg *config.Config, limiters rateLimiters) (ai.Provider, error) {
+	client, err := newHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	opts := []ai.ProviderOption{
+		ai.WithHTTPClient(client),
+		ai.WithBaseURL(cfg.AI.BaseURL),
+		ai.WithModel(cfg.AI.Model),
+		ai.WithMaxOutputTokens(cfg.AI.MaxOutputTokens),
+		ai.WithTimeout(cfg.AI.RequestTimeout),
+		ai.WithMaxAttempts(cfg.AI.MaxAttempts),
+		ai.WithContextWindows(cfg.AI.ContextWindows),
+		ai.WithEndpoint(cfg.AI.Endpoint),
+		ai.WithDeployment(cfg.AI.Deployment),
+		ai.WithAPIVersion(cfg.AI.APIVersion),
+		ai.WithCommand(cfg.AI.Exec.Command),
+		ai.WithCommandEnv(cfg.GetExecEnv()),
+		ai.WithLanguage(cfg.Language),
+	}
+	if limiter := limiters.get(cfg, cfg.AIProvider); limiter != nil {
+		opts = append(opts, ai.WithRateLimiter(limiter))
+	}
+	if cfg.AI.Temperature != nil {
+		opts = append(opts, ai.WithTemperature(*cfg.AI.Temperature))
+	}
+	if cfg.AI.StructuredOutput != nil {
+		opts = append(opts, ai.WithStructuredOutput(*cfg.AI.StructuredOutput))
+	}
+	if cfg.Options.Verbose {
+		// JSON出力を壊さないようログは標準エラーに出力する
+		opts = append(opts, ai.WithMiddleware(ai.LogMiddleware(os.Stderr)))
+	}
+	provider, err := ai.NewProvider(cfg.AIProvider, cfg.AIAPIKey, opts...)
+	if err != nil || cfg.Egress.AuditLog == "" || provider.Name() == ai.HeuristicProviderName {
+		return provider, err
+	}
+	return egress.NewAuditProvider(provider, egress.NewAuditLog(cfg.Egress.AuditLog)), nil
+}
+
+// newPreparer はプロンプトの準備（SPECとコードの読み込み、送信するファイルの選択、機密情報のマスク、
+// テンプレートの描画）に必要な設定だけを持つVerifierを作成する。プロバイダーは作成しない
+func newPreparer(cfg *config.Config) (*Verifier, error) {
+	templates, err := loadPromptTemplates(cfg)
+	if err != nil {
+		return nil, err
+	}
+	redactor, err := redact.FromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := egress.NewPolicy(cfg.Egress.Allow, cfg.Egress.Deny)
+	if err != nil {
+		return nil, err
+	}
 	return &Verifier{
-		config:   cfg,
-		provider: provider,
+		config:    cfg,
+		templates: templates,
+		redactor:  redactor,
+		egress:    policy,
 	}, nil
 }
 
+// New は新しいVerifierを作成する
+func New(cfg *config.Config, opts ...Option) (*Verifier, error) {
+	limiters := rateLimiters{}
+	base, err := newChainProvider(cfg, limiters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AI provider: %w", err)
+	}
+	provider := withHeuristicFallback(cfg, base)
+
+	v, err := newPreparer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	v.provider = provider
+	v.limiters = limiters
+	if cfg.IsCacheEnabled() {
+		v.cache = cache.New(cfg.Cache.Dir)
+	}
+	v.ensemble, err = newEnsembleProviders(cfg, limiters)
+	if err != nil {
+		return nil, err
+	}
+	v.samplers, err = newSampleProviders(cfg, base, limiters)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.AI.HeuristicFallback && base.Name() != ai.HeuristicProviderName {
+		v.heuristic = ai.NewHeuristicProvider(ai.WithLanguage(cfg.Language))
+	}
+	v.budget, err = newBudget(cfg, v.pricedMembers())
+	if err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v, nil
+}
+
 // VerifyAll は全てのSPECを検証する
 // specTypeが空の場合は全タイプ、指定がある場合はそのタイプのみを検証する
 func (v *Verifier) VerifyAll(ctx context.Context, specType string) (*Summary, error) {
@@ -114,69 +438,276 @@ func (v *Verifier) VerifyOne(ctx context.Context, specFile string) (*Result, err
 	return &result, nil
 }
 
-// verifyOne は単一のSPECを検証する（内部用）
-func (v *Verifier) verifyOne(ctx context.Context, specFile string) Result {
-	result := Result{
-		SpecFile: filepath.Base(specFile),
-	}
+// preparedSpec はAIに送信する直前まで準備したSPEC
+type preparedSpec struct {
+	spec *parser.Spec
+
+	// AIに送信するSPECの内容（セクション単位の検証ではそのセクションのみ）
+	content string
 
+	codeContents map[string]string
+	opts         *ai.VerifyOptions
+
+	// AIに送信する検証プロンプト
+	prompt string
+
+	// AIに送信するSPEC全体と関連コードのバイト数（max_input_bytes_per_spec の判定に使用する）
+	// セクション単位の検証でもSPEC全体の値とする
+	inputBytes int
+}
+
+// prepare はSPECを解析し、関連コードを読み込んで検証の準備をする
+// AIに送信する必要がない場合（エラー、コードなし）は result に結果を設定して nil を返す
+func (v *Verifier) prepare(specFile string, result *Result) *preparedSpec {
 	// SPECファイルを解析
 	spec, err := parser.ParseSpec(specFile)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to parse spec: %w", err)
-		return result
+		return nil
 	}
 
 	result.Title = spec.Title
+	result.SpecType = spec.Type
 	result.RoutePath = spec.RoutePath
 
+	// deny に一致するSPECは関連コードとともに送信せず、検証できないためエラーとする
+	// allow は関連コードを限定する設定のため、SPECには適用しない
+	if v.egress.Denied(specFile) {
+		result.DroppedFiles = []string{specFile}
+		result.Error = fmt.Errorf("spec file is denied by egress policy: %s", specFile)
+		return nil
+	}
+
 	// 関連コードファイルを検索（spec_types.code_paths を使用）
 	codePaths := v.config.GetCodePaths(spec.Type)
 	codeFiles, err := parser.FindCodeFilesWithCodePaths(spec, v.config.CodeDir, codePaths)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to find code files: %w", err)
-		return result
+		return nil
 	}
+	// egress で送信を禁止されたファイルは読み込まずに除外する
+	codeFiles, result.DroppedFiles = v.egress.Filter(codeFiles)
 
 	result.CodeFiles = codeFiles
 
+	msg := i18n.New(v.config.Language)
 	if len(codeFiles) == 0 {
 		result.Verification = &ai.VerificationResult{
 			MatchPercentage: 0,
 			MatchedItems:    []string{},
-			UnmatchedItems:  []string{"対応するコードが見つかりません"},
-			Notes:           "未実装の可能性があります",
+			UnmatchedItems:  []string{msg.Sprintf("verify.no_code_item")},
+			Notes:           msg.Sprintf("verify.no_code_notes"),
 		}
-		return result
+		return nil
 	}
 
 	// コードファイルを読み込む
 	codeContents, err := parser.ReadFiles(codeFiles)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to read code files: %w", err)
+		return nil
+	}
+	// シークレットや個人情報を外部のAPIに送信しないよう、プレースホルダーに置き換える
+	codeContents, result.Redactions = v.redactor.RedactFiles(codeContents)
+
+	// 検証観点とプロンプトテンプレートを取得
+	opts := &ai.VerifyOptions{
+		VerificationFocus: v.config.GetVerificationFocus(spec.Type),
+		Template:          v.templates.verify[v.config.GetPromptTemplate(spec.Type)],
+		EvidenceTemplate:  v.templates.evidence[v.config.GetEvidenceTemplate(spec.Type)],
+		ReduceTemplate:    v.templates.reduce[v.config.GetReduceTemplate(spec.Type)],
+		Title:             spec.Title,
+		SpecType:          spec.Type,
+		Metadata:          spec.Metadata,
+	}
+	if len(opts.VerificationFocus) == 0 {
+		opts.VerificationFocus = ai.DefaultVerificationFocus(msg)
+	}
+	// 組み込みのプロンプトは日本語のため、他の言語の場合のみ回答の言語を指示する
+	if msg.Language() != i18n.Japanese {
+		opts.Language = msg.Sprintf("language.name")
+	}
+	prompt, err := ai.BuildVerificationPrompt(spec.Content, codeContents, opts)
+	if err != nil {
+		result.Error = err
+		return nil
+	}
+
+	inputBytes := len(spec.Content)
+	for _, content := range codeContents {
+		inputBytes += len(content)
+	}
+
+	return &preparedSpec{
+		spec:         spec,
+		content:      spec.Content,
+		codeContents: codeContents,
+		opts:         opts,
+		prompt:       prompt,
+		inputBytes:   inputBytes,
+	}
+}
+
+// cacheKey は検証結果キャッシュのキーを返す
+// プロンプトにはテンプレート、SPEC、コードの内容が全て含まれる
+// 分割検証のテンプレートを差し替えた場合は、コードを分割したときの結果が変わるためその本文も含める
+// temperature などの生成のオプションも結果に影響するため含める
+func cacheKey(cfg *config.Config, providerName, model string, p *preparedSpec) string {
+	prompt := p.prompt
+	if p.opts != nil {
+		for _, t := range []*ai.PromptTemplate{p.opts.EvidenceTemplate, p.opts.ReduceTemplate} {
+			if t != nil {
+				prompt += "\x00" + t.Source()
+			}
+		}
+	}
+	return cache.Key(providerName, model, generationOptions(cfg), prompt)
+}
+
+// generationOptions は結果に影響する生成のオプション（未指定の場合は default）をキャッシュキー用の文字列にする
+func generationOptions(cfg *config.Config) string {
+	temperature := "default"
+	if cfg.AI.Temperature != nil {
+		temperature = fmt.Sprintf("%g", *cfg.AI.Temperature)
+	}
+	structured := "default"
+	if cfg.AI.StructuredOutput != nil {
+		structured = fmt.Sprintf("%t", *cfg.AI.StructuredOutput)
+	}
+	return fmt.Sprintf("temperature=%s;max_output_tokens=%d;structured_output=%s",
+		temperature, cfg.AI.MaxOutputTokens, structured)
+}
+
+// verifyOne は単一のSPECを検証する（内部用）
+func (v *Verifier) verifyOne(ctx context.Context, specFile string) Result {
+	result := Result{
+		SpecFile: filepath.Base(specFile),
+	}
+	ctx = egress.WithSpec(ctx, specFile)
+
+	prepared := v.prepare(specFile, &result)
+	if prepared == nil {
 		return result
 	}
 
-	// 検証観点を取得
-	verificationFocus := v.config.GetVerificationFocus(spec.Type)
+	if v.config.IsSectionsEnabled() {
+		v.verifySections(ctx, prepared, &result)
+		return result
+	}
+	v.verifyPrepared(ctx, prepared, &result)
+	return result
+}
+
+// verifyPrepared はキャッシュと予算を確認し、準備済みのSPECを検証して result に設定する
+func (v *Verifier) verifyPrepared(ctx context.Context, prepared *preparedSpec, result *Result) {
+	// キャッシュを確認
+	var key string
+	if v.cache != nil {
+		name, model := v.identity()
+		key = cacheKey(v.config, name, model, prepared)
+		if !v.refreshCache {
+			var cached ai.VerificationResult
+			if found, _ := v.cache.Get(key, &cached); found {
+				// 今回はAPIを呼び出していないので再試行回数、使用量、所要時間は引き継がない
+				cached.Retries = 0
+				cached.Usage = nil
+				cached.LatencyMs = 0
+				cached.Attempts = 0
+				result.Verification = &cached
+				result.Cached = true
+				return
+			}
+		}
+	}
+
+	// 予算の上限を確認して推定量を予約する
+	reserved, reason := v.budget.admit(prepared, v.callsPerSpec())
+	if reason != "" {
+		result.Skipped = true
+		result.SkipReason = reason
+		return
+	}
 
 	// AIで検証
 	var verification *ai.VerificationResult
-	if len(verificationFocus) > 0 {
-		opts := &ai.VerifyOptions{
-			VerificationFocus: verificationFocus,
-		}
-		verification, err = v.provider.VerifyWithOptions(ctx, spec.Content, codeContents, opts)
-	} else {
-		verification, err = v.provider.Verify(ctx, spec.Content, codeContents)
+	var err error
+	switch {
+	case len(v.ensemble) > 0:
+		verification, err = v.verifyEnsemble(ctx, prepared)
+	case len(v.samplers) > 0:
+		verification, err = v.verifySamples(ctx, prepared)
+	default:
+		verification, err = callProvider(ctx, v.provider, prepared)
 	}
 	if err != nil {
 		result.Error = fmt.Errorf("failed to verify with AI: %w", err)
-		return result
+		return
+	}
+	// 呼び出し回数が0の結果はAPIを呼び出していないため予算に計上しない
+	records := usageRecords(verification)
+	if verification.Attempts == 0 {
+		v.budget.release(reserved)
+	} else {
+		v.budget.settle(reserved, records)
+	}
+
+	// キャッシュへの保存に失敗しても検証結果には影響させない
+	// 代替プロバイダーの結果は次回の実行でAIによる検証をやり直すため保存しない
+	if v.cache != nil && verification.Fallback == "" {
+		_ = v.cache.Put(key, verification)
 	}
 
 	result.Verification = verification
-	return result
+	result.Usage = records
+}
+
+// heuristicFallback は統合する検証が err で失敗した場合に、ヒューリスティック検証の結果で代替する
+// ai.heuristic_fallback が無効な場合やキャンセルされた場合は err をそのまま返す
+func (v *Verifier) heuristicFallback(ctx context.Context, p *preparedSpec, name string, err error) (*ai.VerificationResult, error) {
+	if v.heuristic == nil || ctx.Err() != nil {
+		return nil, err
+	}
+	return v.heuristic.VerifyFallback(ctx, name, err, p.content, p.codeContents, p.opts)
+}
+
+// callProvider は準備済みのSPECをプロバイダーで検証する
+func callProvider(ctx context.Context, provider ai.Provider, p *preparedSpec) (*ai.VerificationResult, error) {
+	return provider.VerifyWithOptions(ctx, p.content, p.codeContents, p.opts)
+}
+
+// identity はキャッシュキーに使用するプロバイダー名とモデル名を返す
+func (v *Verifier) identity() (name, model string) {
+	if len(v.ensemble) > 0 {
+		return ensembleIdentity(v.config)
+	}
+	if len(v.samplers) > 0 {
+		return sampleIdentity(v.config, v.provider.Name(), v.provider.Model())
+	}
+	return v.provider.Name(), v.provider.Model()
+}
+
+// callsPerSpec は1SPECあたりのAPI呼び出し回数を返す
+func (v *Verifier) callsPerSpec() int {
+	if len(v.ensemble) > 0 {
+		return len(v.ensemble)
+	}
+	if len(v.samplers) > 0 {
+		return len(v.samplers)
+	}
+	return 1
+}
+
+// pricedMembers は検証に使用するプロバイダー名とモデル名の一覧を返す（コスト計算用）
+func (v *Verifier) pricedMembers() []pricedMember {
+	if len(v.ensemble) == 0 {
+		// サンプリングでは同じモデルを繰り返し呼び出すため、料金は1回分と同じになる
+		return []pricedMember{{provider: v.provider.Name(), model: v.provider.Model()}}
+	}
+	members := make([]pricedMember, len(v.ensemble))
+	for i, p := range v.ensemble {
+		members[i] = pricedMember{provider: p.Name(), model: p.Model()}
+	}
+	return members
 }
 
 // calculateSummary はサマリーを計算する
@@ -188,8 +719,14 @@ func (v *Verifier) calculateSummary(results []Result) *Summary {
 
 	var totalMatch int
 	for _, result := range results {
+		if result.Skipped {
+			summary.SkippedSpecs++
+		}
 		if result.Error == nil && result.Verification != nil {
 			summary.VerifiedSpecs++
+			if result.Cached {
+				summary.CachedSpecs++
+			}
 			totalMatch += result.Verification.MatchPercentage
 
 			if result.Verification.MatchPercentage >= 80 {
@@ -203,6 +740,15 @@ func (v *Verifier) calculateSummary(results []Result) *Summary {
 	if summary.VerifiedSpecs > 0 {
 		summary.AverageMatch = float64(totalMatch) / float64(summary.VerifiedSpecs)
 	}
+	summary.ReviewSpecs = buildReviewSpecs(results)
+	summary.UnstableSpecs = buildUnstableSpecs(results)
+	summary.SectionAverages = buildSectionAverages(results)
+	summary.UsageByModel = buildUsageByModel(results)
+	summary.UsageBySpecType = buildUsageBySpecType(results)
+	summary.Redactions = buildRedactionTotal(results)
+	for _, result := range results {
+		summary.DroppedFiles += len(result.DroppedFiles)
+	}
 
 	return summary
 }
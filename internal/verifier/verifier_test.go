@@ -0,0 +1,126 @@
+package verifier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/k-totani/spec-verify/internal/ai"
+	"github.com/k-totani/spec-verify/internal/cache"
+	"github.com/k-totani/spec-verify/internal/config"
+)
+
+func TestNewProvider_Chain(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AIProvider = "ollama"
+	cfg.AIProviderFallbacks = []string{"heuristic", "ollama"}
+	cfg.AI.Model = "qwen2.5-coder:32b"
+
+	provider, err := NewProvider(cfg)
+	if err != nil {
+		t.Fatalf("NewProvider failed: %v", err)
+	}
+	chain, ok := provider.(*ai.ChainProvider)
+	if !ok {
+		t.Fatalf("provider = %T, want *ai.ChainProvider", provider)
+	}
+
+	var got []string
+	for _, p := range chain.Providers() {
+		got = append(got, p.Name()+"/"+p.Model())
+	}
+	// フォールバック先には主プロバイダーのモデルを引き継がない
+	want := []string{
+		"ollama/qwen2.5-coder:32b",
+		"heuristic/" + ai.DefaultModel("heuristic"),
+		"ollama/" + ai.DefaultModel("ollama"),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("providers = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("provider %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestNewProvider_SingleProvider(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AIProvider = "ollama"
+
+	provider, err := NewProvider(cfg)
+	if err != nil {
+		t.Fatalf("NewProvider failed: %v", err)
+	}
+	if _, ok := provider.(*ai.ChainProvider); ok {
+		t.Error("a single provider should not be wrapped in a chain")
+	}
+}
+
+func TestRateLimitFor_Alias(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AI.RateLimits = map[string]config.RateLimit{
+		"claude": {RequestsPerMinute: 50},
+		"gpt":    {RequestsPerMinute: 500},
+	}
+
+	if got := rateLimitFor(cfg, "anthropic"); got.RequestsPerMinute != 50 {
+		t.Errorf("anthropic = %+v, want the claude limit", got)
+	}
+	if got := rateLimitFor(cfg, "gpt"); got.RequestsPerMinute != 500 {
+		t.Errorf("gpt = %+v, want the limit of the exact key", got)
+	}
+	if got := rateLimitFor(cfg, "gemini"); got != (config.RateLimit{}) {
+		t.Errorf("gemini = %+v, want no limit", got)
+	}
+}
+
+func TestRateLimiters_SharedPerProvider(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AI.RateLimits = map[string]config.RateLimit{"claude": {RequestsPerMinute: 50}}
+
+	limiters := rateLimiters{}
+	claude := limiters.get(cfg, "claude")
+	if claude == nil {
+		t.Fatal("expected a rate limiter for claude")
+	}
+	// エイリアスやアンサンブルのメンバーの設定でも同じリミッターを使用する
+	if limiters.get(memberConfig(cfg, config.EnsembleMember{Provider: "anthropic", Model: "claude-3-5-haiku-latest"}), "anthropic") != claude {
+		t.Error("members of the same provider should share the rate limiter")
+	}
+	if limiters.get(cfg, "openai") != nil {
+		t.Error("expected no rate limiter for openai")
+	}
+}
+
+func TestVerifyMultipleTypes_CacheKeyGenerationOptions(t *testing.T) {
+	cfg := writeTestProject(t)
+	provider := &stubProvider{percentage: 80}
+	v := &Verifier{config: cfg, provider: provider, cache: cache.New(cfg.Cache.Dir)}
+
+	verify := func() *Result {
+		t.Helper()
+		summary, err := v.VerifyMultipleTypes(context.Background(), []string{"ui"})
+		if err != nil {
+			t.Fatalf("VerifyMultipleTypes failed: %v", err)
+		}
+		return findResult(summary, "login.md")
+	}
+
+	if login := verify(); login == nil || login.Cached {
+		t.Fatalf("first run = %+v", login)
+	}
+	if login := verify(); login == nil || !login.Cached {
+		t.Fatalf("second run = %+v, want a cache hit with the same settings", login)
+	}
+
+	// temperature を変更した場合は以前の設定で得た結果を使わない
+	temperature := 0.7
+	cfg.AI.Temperature = &temperature
+	if login := verify(); login == nil || login.Cached {
+		t.Errorf("run after changing temperature = %+v, want a cache miss", login)
+	}
+	if provider.calls.Load() != 2 {
+		t.Errorf("provider was called %d times, want 2", provider.calls.Load())
+	}
+}